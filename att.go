@@ -0,0 +1,311 @@
+package hde
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// attNames maps mnemonics whose AT&T spelling differs from the Intel one
+var attNames = map[Mnemonic]string{
+	CBW:    "cbtw",
+	CWDE:   "cwtl",
+	CDQE:   "cltq",
+	CWD:    "cwtd",
+	CDQ:    "cltd",
+	CQO:    "cqto",
+	MOVSXD: "movslq",
+	RETF:   "lret",
+}
+
+// attSuffix returns the AT&T operand size suffix for a size in bits
+func attSuffix(bits int) string {
+	switch bits {
+	case 8:
+		return "b"
+	case 16:
+		return "w"
+	case 32:
+		return "l"
+	case 64:
+		return "q"
+	}
+	return ""
+}
+
+// x87Suffix returns the AT&T suffix of an x87 memory operand
+func x87Suffix(mnem Mnemonic, bits int) string {
+	name := mnem.String()
+	if name[1] == 'i' {
+		// Integer operands: fild, fist, fisttp, fiadd and friends
+		switch bits {
+		case 16:
+			return "s"
+		case 32:
+			return "l"
+		case 64:
+			return "ll"
+		}
+		return ""
+	}
+	switch bits {
+	case 32:
+		return "s"
+	case 64:
+		return "l"
+	case 80:
+		return "t"
+	}
+	return ""
+}
+
+// attMnemonic returns the AT&T mnemonic of the instruction, with the operand size
+// suffix objdump -M suffix prints
+func (insn *Insn) attMnemonic(f *opForm, ops []Operand) string {
+	if name, ok := attNames[f.mnem]; ok {
+		if f.mnem == RETF {
+			name += attSuffix(insn.operandSize(f.flags))
+		}
+		return name
+	}
+	name := f.mnem.String()
+	osz := insn.operandSize(f.flags)
+
+	switch f.mnem {
+	case MOVZX, MOVSX:
+		return name[:4] + attSuffix(int(ops[1].Size)) + attSuffix(int(ops[0].Size))
+	case PUSHF, PUSHFD, PUSHFQ, POPF, POPFD, POPFQ, IRET, IRETD, IRETQ,
+		PUSHA, PUSHAD, POPA, POPAD:
+		name = strings.TrimRight(name, "dq")
+		return name + attSuffix(osz)
+	case PCMPESTRI, PCMPESTRM:
+		// The implicit lengths in EAX and EDX widen with REX.W
+		if insn.vexW() != 0 {
+			return name + "q"
+		}
+		return name + "l"
+	case LOOP, LOOPE, LOOPNE:
+		return name + attSuffix(insn.AddressSize())
+	case FSUB, FSUBR, FDIV, FDIVR, FSUBP, FSUBRP, FDIVP, FDIVRP:
+		// The SysV assembler swaps the reversed forms that store to ST(i)
+		if insn.ModRM.Mod() == 3 && (insn.Opcode == 0xdc || insn.Opcode == 0xde) {
+			if strings.Contains(name, "r") {
+				return strings.Replace(name, "r", "", 1)
+			}
+			return name[:4] + "r" + name[4:]
+		}
+	case BSWAP, ARPL:
+		return name
+	}
+	if insn.Map() == Map0F && insn.OpcodeByte()&0xf0 == 0x90 && insn.encoding() == encLegacy {
+		// SETcc is only defined on bytes and objdump leaves it unsuffixed
+		return name
+	}
+
+	// The suffix follows the first general purpose operand
+	sized, vector := -1, false
+	for i, s := range f.ops {
+		if s.am == amNone {
+			break
+		}
+		switch s.am {
+		case amE, amG, amR, amZ, amB, amO, amX, amY, amC, amD:
+			if sized < 0 {
+				sized = int(ops[i].Size)
+			}
+		case amFixed:
+			if sized < 0 && s.reg.Class() == ClassGPR && s.reg != DX {
+				sized = int(ops[i].Size)
+			}
+			vector = vector || s.reg.Class() != ClassGPR && s.reg.Class() != ClassSeg
+		case amA:
+			sized = osz
+		case amJ:
+			if f.mnem == CALL || f.mnem == JMP && s.size != szB {
+				sized = osz
+			}
+		case amSTi, amV, amW, amU, amH, amL, amP, amQ, amN, amKG, amKH, amKR, amKE, amMV:
+			vector = true
+		}
+	}
+	if sized < 0 && f.hasMethod(amS) || f.ops[0].am == amFixed && f.ops[0].reg.Class() == ClassSeg {
+		// Segment registers are moved, pushed and popped at the operand size
+		sized = osz
+	}
+
+	switch {
+	case f.hasMethod(amX, amY):
+		// String instructions are named by their operand size
+		return name[:len(name)-1] + attSuffix(sized)
+	case name[0] == 'f' && f.mnem != FBLD && f.mnem != FBSTP:
+		for i, op := range ops {
+			switch {
+			case op.Type != OpMem || f.ops[i].am != amM:
+			case f.ops[i].size == szE || f.ops[i].size == szST:
+				// The environment and state layouts depend on the operand size
+				return name + attSuffix(osz)
+			default:
+				return name + x87Suffix(f.mnem, int(op.Size))
+			}
+		}
+		return name
+	case vector:
+		// Conversions from general purpose registers name the source size
+		if strings.Contains(name, "cvtsi2") || strings.Contains(name, "cvtusi2") {
+			for i, op := range ops {
+				if f.ops[i].am == amE {
+					return name + attSuffix(int(op.Size))
+				}
+			}
+		}
+		return name
+	case f.mnem == MOV && insn.Mode.long && insn.intelMnemonic(f, ops) == "movabs":
+		name = "movabs"
+	case sized < 0 && f.flags&(ffD64|ffF64) != 0 && !f.hasMethod(amJ):
+		sized = osz
+	}
+	if (f.mnem == JMP || f.mnem == CALL) && (f.ops[0].am == amA || f.ops[0].size == szP) {
+		name, sized = "l"+name, osz
+	}
+	if sized > 0 {
+		name += attSuffix(sized)
+	}
+	return name
+}
+
+// attMem formats a memory reference, reporting whether it printed the segment override
+func (insn *Insn) attMem(b *strings.Builder, s opSpec, op Operand) bool {
+	m := op.Mem
+	override := insn.Flags.Segment()
+	segUsed := false
+	switch {
+	case s.am == amX, s.am == amY, s.am == amXlat:
+		b.WriteString("%" + m.Segment.Register().String() + ":")
+		segUsed = override != SegNone && s.am != amY
+	case override != SegNone && !insn.isNullSegment(override):
+		b.WriteString("%" + m.Segment.Register().String() + ":")
+		segUsed = true
+	}
+
+	index, scale := insn.memIndex(m)
+	if insn.Flags&(HasDisp8|HasDisp16|HasDisp32) != 0 || s.am == amO || m.Base == RegNone && scale == 0 {
+		// Displacements are signed, except for absolute 32 and 64-bit addresses
+		if m.Disp < 0 && (m.Base != RegNone || scale != 0 || insn.AddressSize() == 16) {
+			b.WriteString("-" + hexImm(uint64(-m.Disp)))
+		} else {
+			b.WriteString(hexImm(truncate(uint64(m.Disp), insn.AddressSize())))
+		}
+	}
+	if m.Base == RegNone && scale == 0 {
+		return segUsed
+	}
+
+	b.WriteByte('(')
+	if m.Base != RegNone {
+		b.WriteString("%" + m.Base.String())
+	}
+	if scale != 0 {
+		b.WriteString(",%" + index)
+		if insn.AddressSize() != 16 {
+			b.WriteString("," + strconv.Itoa(int(scale)))
+		}
+	}
+	b.WriteByte(')')
+	return segUsed
+}
+
+// FormatATT returns the instruction in AT&T syntax, as printed by objdump -M suffix
+// with operands separated by ", ". Mnemonics carry an operand size suffix derived
+// from the operand size, and indirect branch targets are marked with '*'. Branch
+// targets are resolved against addr, the address of the instruction. As with
+// FormatIntel, comparison predicates are folded into the mnemonic (cmpltps,
+// vpcmpeqb) and redundant prefixes are not reproduced.
+func (insn *Insn) FormatATT(addr uint64) string {
+	f := insn.form()
+	if f == nil {
+		return "(bad)"
+	}
+	ops := insn.Operands()
+	evex := insn.encoding() == encEVEX
+	indirect := (f.mnem == CALL || f.mnem == JMP) && !f.hasMethod(amJ, amA)
+	name := insn.attMnemonic(f, ops)
+	if alias := insn.predicateMnemonic(f, name); alias != "" {
+		// The predicate replaces the immediate operand
+		name, ops = alias, ops[:len(ops)-1]
+	}
+
+	var (
+		args    []string
+		segUsed bool
+	)
+	for i, op := range ops {
+		s := f.ops[i]
+		var b strings.Builder
+		if indirect {
+			b.WriteByte('*')
+		}
+		switch op.Type {
+		case OpReg:
+			switch {
+			case s.am == amFixed && op.Reg == ST0:
+				b.WriteString("%st")
+			case s.am == amFixed && op.Reg == DX:
+				b.WriteString("(%dx)")
+			default:
+				b.WriteString("%" + op.Reg.String())
+			}
+		case OpMem:
+			if insn.attMem(&b, s, op) {
+				segUsed = true
+			}
+			if op.Mem.Broadcast {
+				b.WriteString("{1to" + strconv.Itoa(insn.vectorBits()/int(op.Size)) + "}")
+			}
+		case OpImm:
+			if s.am == amOne {
+				continue
+			}
+			b.WriteString("$" + hexImm(truncate(uint64(op.Imm), int(op.Size))))
+		case OpRel:
+			target := addr + uint64(insn.Len()) + uint64(op.Imm)
+			target = addr - truncate(addr, int(op.Size)) + truncate(target, int(op.Size))
+			b.WriteString(hexImm(target))
+		case OpFar:
+			b.WriteString("$" + hexImm(uint64(op.Selector)) + ", $" + hexImm(uint64(op.Imm)))
+		}
+		if evex && i == 0 {
+			if mask := insn.Mask(); mask != RegNone {
+				b.WriteString("{%" + mask.String() + "}")
+			}
+			if insn.EVEX.Z() != 0 {
+				b.WriteString("{z}")
+			}
+		}
+		args = append(args, b.String())
+	}
+
+	// AT&T lists the source operands first; ENTER and BOUND keep the Intel order
+	if f.mnem != ENTER && f.mnem != BOUND {
+		for i, j := 0, len(args)-1; i < j; i, j = i+1, j-1 {
+			args[i], args[j] = args[j], args[i]
+		}
+	}
+	if evex && insn.EVEX.Bcst() != 0 && insn.ModRM.Mod() == 3 {
+		rc := roundingNames[insn.EVEX.LL()]
+		if saeOnly(f.mnem) {
+			rc = "{sae}"
+		}
+		// The rounding control follows the immediate, if there is one
+		at := 0
+		if len(args) != 0 && strings.HasPrefix(args[0], "$") {
+			at = 1
+		}
+		args = slices.Insert(args, at, rc)
+	}
+
+	text := insn.textPrefixes(f, segUsed) + name
+	if len(args) != 0 {
+		text += " " + strings.Join(args, ", ")
+	}
+	return text
+}
@@ -46,12 +46,15 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 
 	var (
 		x, c, opcode uint8
+		opmap        uint8
 		cflags       cflag
 		pref         PrefixSet
 		mod, reg, rm uint8
 		op64         bool
+		vex, evex    bool
 	)
 
+	hs.Mode = mode
 	p := code
 	for x = 16; x > 0; x-- {
 		if len(p) == 0 {
@@ -65,14 +68,74 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 			break
 		}
 		pref = pref.Add(pi)
+		if int(hs.NumPrefixes) < len(hs.Prefixes) {
+			hs.Prefixes[hs.NumPrefixes] = c
+			hs.NumPrefixes++
+		}
 	}
 	hs.Flags = hs.Flags.AddPrefixes(pref)
 
+	// The size override prefixes toggle between the mode's default size and the alternate one.
+	opSize16 := pref.Has(PreOpSize) != mode.short
+	addr16 := !mode.long && pref.Has(PreAddrSize) != mode.short
+
 	if pref == 0 {
 		pref = pref.Add(PreNone)
 	}
 
-	if mode.long {
+	// C4/C5/62 are always VEX/EVEX prefixes in 64-bit mode, otherwise they encode
+	// LES/LDS/BOUND unless the following byte would be a register operand.
+	if c == VEX3 || c == VEX2 || c == EVEX4 {
+		if len(p) == 0 {
+			return hs, ErrLength
+		}
+		if mode.long || p[0]&0xc0 == 0xc0 {
+			if pref.Has(PreOpSize) || pref.Has(PreRep) || pref.Has(PreRepNZ) || pref.Has(PreLock) {
+				return hs, ErrUnknownOpcode
+			}
+			var pp uint8
+			switch c {
+			case VEX2:
+				hs.VEX = newVEX2(p[0])
+				p = p[1:]
+			case VEX3:
+				if len(p) < 2 {
+					return hs, ErrLength
+				}
+				hs.VEX = newVEX3(p[0], p[1])
+				p = p[2:]
+			default:
+				if len(p) < 3 {
+					return hs, ErrLength
+				}
+				hs.EVEX = newEVEX(p[0], p[1], p[2])
+				p = p[3:]
+				if !hs.EVEX.valid() {
+					return hs, ErrUnknownOpcode
+				}
+				evex = true
+			}
+			if evex {
+				hs.Flags |= HasEVEX
+				opmap, pp = hs.EVEX.Map(), hs.EVEX.PP()
+			} else {
+				hs.Flags |= HasVEX
+				opmap, pp = hs.VEX.Map(), hs.VEX.PP()
+			}
+			if len(p) == 0 {
+				return hs, ErrLength
+			}
+			c, p = p[0], p[1:]
+			vex = true
+
+			// Validate against the implied prefix rather than the legacy ones.
+			if pp != 0 {
+				pref = pref.Remove(PreNone).Add(ppToPrefixID[pp])
+			}
+		}
+	}
+
+	if mode.long && !vex {
 		if (c & 0xf0) == 0x40 {
 			hs.Flags |= HasREX
 			hs.REX = REX(c)
@@ -90,25 +153,87 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 	}
 
 	hs.Opcode = c
+	hs.OpcodeOffset = uint8(len(code) - len(p) - 1)
 	tbl := mode.table
-	if c == 0x0f {
+	if vex {
+		hs.Opcode = 0x0f
+		switch opmap {
+		case 1:
+			hs.Opcode2 = c
+			tbl = tbl[mode.dtOpcodes:]
+		case 2:
+			hs.Opcode2, hs.Opcode3 = 0x38, c
+		case 3:
+			hs.Opcode2, hs.Opcode3 = 0x3a, c
+		}
+	} else if c == 0x0f {
 		if len(p) == 0 {
 			return hs, ErrLength
 		}
 		c, p = p[0], p[1:]
 		hs.Opcode2 = c
+		opmap = 1
 		tbl = tbl[mode.dtOpcodes:]
-	} else if c >= 0xa0 && c <= 0xa3 {
-		op64 = true
-		if pref.Has(PreAddrSize) {
-			pref = pref.Add(PreOpSize)
-		} else {
-			pref = pref.Remove(PreOpSize)
+		if c == 0x38 || c == 0x3a {
+			if len(p) == 0 {
+				return hs, ErrLength
+			}
+			c, p = p[0], p[1:]
+			hs.Opcode3 = c
+			if hs.Opcode2 == 0x38 {
+				opmap = 2
+				tbl = mode.table3
+			} else {
+				opmap = 3
+				tbl = mode.table3[mode.dtOpcodes3A:]
+			}
 		}
+	} else if c >= 0xa0 && c <= 0xa3 {
+		// The memory offset is sized by the address size
+		op64 = !pref.Has(PreAddrSize)
+		opSize16 = addr16
 	}
 
 	opcode = c
-	cflags = tbl[tbl[opcode>>2]+(opcode&3)]
+	if !vex {
+		cflags = tbl[tbl[opcode>>2]+(opcode&3)]
+	} else {
+		switch opmap {
+		case 1:
+			cflags = cfError
+			if evex && opcode >= 0x78 && opcode <= 0x7b {
+				cflags = cfModRM
+			} else if vexOpcode0F(opcode) {
+				cflags = tbl[tbl[opcode>>2]+(opcode&3)]
+			}
+		case 2:
+			cflags = cfModRM
+		case 3:
+			cflags = cfModRM | cfImm8
+		default:
+			cflags = cfError
+		}
+	}
+
+	checkPrefixes := true
+	if opmap == 1 && !vex {
+		switch {
+		case opcode == 0xb8 && pref.Has(PreRep):
+			// F3 0F B8 is POPCNT r, r/m, which the HDE tables decode as the
+			// operand-less JMPE 0F B8
+			cflags = cfModRM
+		case (opcode == 0xbc || opcode == 0xbd) && pref.Has(PreRep):
+			// F3 0F BC and BD are TZCNT and LZCNT r, r/m, for which the HDE
+			// tables reject the F3 prefix of BSF and BSR
+			checkPrefixes = false
+		case opcode == 0x0b:
+			// UD2, which the HDE tables reject
+			cflags = 0
+		case opcode == 0xb9 || opcode == 0xff:
+			// UD1 r, r/m and UD0 r, r/m, which the HDE tables reject
+			cflags = cfModRM
+		}
+	}
 
 	if cflags == cfError {
 		return hs, ErrUnknownOpcode
@@ -118,14 +243,29 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 	if cflags&cfGroup != 0 {
 		t := uint16(tbl[cflags&0x7f]) | uint16(tbl[cflags&0x7f+1])<<8
 		cflags = uint8(t)
-		x = uint8(t >> 8)
+		if !evex {
+			x = uint8(t >> 8)
+		}
+	}
+
+	if vex && cflags&(cfImmP66|cfRel8|cfRel32) != 0 {
+		return hs, ErrUnknownOpcode
 	}
 
-	if hs.Opcode2 != 0 {
+	// Check the mandatory prefixes, VEX opcodes outside of the 0F map have no
+	// legacy equivalent to check against.
+	tbl = nil
+	switch {
+	case !checkPrefixes:
+	case opmap == 1 && !evex:
 		tbl = mode.table[mode.dtPrefixes:]
-		if tbl[tbl[opcode>>2]+(opcode&3)]&byte(pref) != 0 {
-			return hs, ErrUnknownOpcode
-		}
+	case opmap == 2 && !vex:
+		tbl = mode.table3[mode.dtPrefixes38:]
+	case opmap == 3 && !vex:
+		tbl = mode.table3[mode.dtPrefixes3A:]
+	}
+	if tbl != nil && tbl[tbl[opcode>>2]+(opcode&3)]&byte(pref) != 0 {
+		return hs, ErrUnknownOpcode
 	}
 
 	if cflags&cfModRM != 0 {
@@ -136,15 +276,27 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 
 		hs.Flags |= IsModRM
 		hs.ModRM = ModRM(c)
+		hs.ModRMOffset = uint8(len(code) - len(p) - 1)
 		mod = hs.ModRM.Mod()
 		rm = hs.ModRM.RM()
 		reg = hs.ModRM.Reg()
 
+		if opmap == 0 && opcode&0xfe == 0xc6 && hs.ModRM == 0xf8 {
+			// XABORT ib and XBEGIN rel16/32, which the HDE group tables reject
+			x = 0
+			if opcode == 0xc7 {
+				cflags |= cfRel32
+			}
+		}
+		if opmap == 1 && !vex && opcode == 0x01 && (hs.ModRM == 0xe8 || hs.ModRM == 0xee || hs.ModRM == 0xef) {
+			// SERIALIZE, RDPKRU and WRPKRU, which the HDE group tables reject
+			x = 0
+		}
 		if x != 0 && ((x<<reg)&0x80) != 0 {
 			return hs, ErrUnknownOpcode
 		}
 
-		if hs.Opcode2 == 0 && opcode >= 0xd9 && opcode <= 0xdf {
+		if opmap == 0 && opcode >= 0xd9 && opcode <= 0xdf {
 			t := opcode - 0xd9
 			if mod == 3 {
 				tbl = mode.table[mode.dtFPUModRM+int(t)*8:]
@@ -164,7 +316,9 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 			} else {
 				op := opcode
 				var end []cflag
-				if hs.Opcode2 != 0 {
+				if opmap > 1 {
+					return hs, ErrInvalidLock
+				} else if hs.Opcode2 != 0 {
 					tbl = mode.table[mode.dtOp2LockOk:]
 					end = tbl[mode.dtOpOnlyMem-mode.dtOp2LockOk:]
 				} else {
@@ -187,7 +341,8 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 			}
 		}
 
-		if hs.Opcode2 != 0 {
+		switch opmap {
+		case 1:
 			switch opcode {
 			case 0x20, 0x22:
 				mod = 3
@@ -200,7 +355,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 					return hs, ErrBadOperand
 				}
 			}
-		} else {
+		case 0:
 			switch opcode {
 			case 0x8c:
 				if reg > 5 {
@@ -215,7 +370,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 
 		if mod == 3 {
 			var it, end int
-			if hs.Opcode2 != 0 {
+			if opmap != 0 {
 				it = mode.dtOp2OnlyMem
 				end = len(mode.table) - mode.dtOp2OnlyMem
 			} else {
@@ -233,7 +388,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 					}
 				}
 			}
-		} else if hs.Opcode2 != 0 {
+		} else if opmap == 1 {
 			switch opcode {
 			case 0x50, 0xd7, 0xf7:
 				if pref.Has(PreNone) || pref.Has(PreOpSize) {
@@ -248,7 +403,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 			}
 		}
 
-		if reg <= 1 {
+		if reg <= 1 && opmap == 0 {
 			if opcode == 0xf6 {
 				cflags |= cfImm8
 			} else if opcode == 0xf7 {
@@ -259,7 +414,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 		var dispSize uint8
 		switch mod {
 		case 0:
-			if pref.Has(PreAddrSize) {
+			if addr16 {
 				if rm == 6 {
 					dispSize = 2
 				}
@@ -272,25 +427,29 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 			dispSize = 1
 		case 2:
 			dispSize = 2
-			if !pref.Has(PreAddrSize) {
+			if !addr16 {
 				dispSize <<= 1
 			}
 		}
 
 		if mod != 3 && rm == 4 {
-			if mode.long || !pref.Has(PreAddrSize) {
+			if !addr16 {
 				hs.Flags |= IsSIB
 				if len(p) == 0 {
 					return hs, ErrLength
 				}
 				c, p = p[0], p[1:]
 				hs.SIB = SIB(c)
+				hs.SIBOffset = uint8(len(code) - len(p) - 1)
 				if hs.SIB.Base() == 5 && mod&1 == 0 {
 					dispSize = 4
 				}
 			}
 		}
 
+		if dispSize != 0 {
+			hs.DispOffset = uint8(len(code) - len(p))
+		}
 		switch dispSize {
 		case 1:
 			hs.Flags |= HasDisp8
@@ -312,9 +471,12 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 		return hs, ErrInvalidLock
 	}
 
+	if cflags&(cfImmP66|cfImm16|cfImm8|cfRel32|cfRel8) != 0 {
+		hs.ImmOffset = uint8(len(code) - len(p))
+	}
 	if cflags&cfImmP66 != 0 {
 		if cflags&cfRel32 != 0 {
-			if pref.Has(PreOpSize) {
+			if opSize16 {
 				hs.Flags |= IsRelative | HasImm16
 				if !hs.Imm.read16(&p) {
 					return hs, ErrLength
@@ -332,7 +494,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 					if !hs.Imm.read64(&p) {
 						return hs, ErrLength
 					}
-				} else if !pref.Has(PreOpSize) {
+				} else if !opSize16 {
 					hs.Flags |= HasImm32
 					if !hs.Imm.read32(&p) {
 						return hs, ErrLength
@@ -342,7 +504,7 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 					//goto imm16_ok
 				}
 			} else {
-				if pref.Has(PreOpSize) {
+				if opSize16 {
 					hs.Flags |= HasImm16
 					if !hs.Imm.read16(&p) {
 						return hs, ErrLength
@@ -374,13 +536,22 @@ func (mode *Mode) Decode(code []byte) (hs Insn, err error) {
 				dst = &hs.Imm
 			}
 		}
+		if dst == &hs.Disp {
+			hs.DispOffset = uint8(len(code) - len(p))
+		}
 		if !dst.read16(&p) {
 			return hs, ErrLength
 		}
 	}
 	if cflags&cfImm8 != 0 {
+		// ENTER follows its imm16 frame size with an imm8 nesting level.
+		dst := &hs.Imm
+		if hs.Flags&HasImm16 != 0 {
+			dst = &hs.Disp
+			hs.DispOffset = uint8(len(code) - len(p))
+		}
 		hs.Flags |= HasImm8
-		if !hs.Imm.read8(&p) {
+		if !dst.read8(&p) {
 			return hs, ErrLength
 		}
 	}
@@ -0,0 +1,587 @@
+package elf
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	hde "github.com/can1357/go-hde"
+)
+
+// ErrFrame is returned for .eh_frame and .eh_frame_hdr contents that do not parse
+var ErrFrame = errors.New("elf: malformed call frame information")
+
+// Pointer encodings of .eh_frame (DW_EH_PE_*)
+const (
+	peAbsPtr  = 0x00
+	peULEB128 = 0x01
+	peUData2  = 0x02
+	peUData4  = 0x03
+	peUData8  = 0x04
+	peSLEB128 = 0x09
+	peSData2  = 0x0a
+	peSData4  = 0x0b
+	peSData8  = 0x0c
+	pePCRel   = 0x10
+	peTextRel = 0x20
+	peDataRel = 0x30
+	peAligned = 0x50
+	peIndir   = 0x80
+	peOmit    = 0xff
+)
+
+// DWARF register numbers of the System V psABIs
+var (
+	dwarfRegs64 = []hde.Register{
+		hde.RAX, hde.RDX, hde.RCX, hde.RBX, hde.RSI, hde.RDI, hde.RBP, hde.RSP,
+		hde.R8, hde.R9, hde.R10, hde.R11, hde.R12, hde.R13, hde.R14, hde.R15, hde.RIP,
+	}
+	dwarfRegs32 = []hde.Register{
+		hde.EAX, hde.ECX, hde.EDX, hde.EBX, hde.ESP, hde.EBP, hde.ESI, hde.EDI, hde.EIP,
+	}
+)
+
+// CFARule is the rule computing the canonical frame address, the value of the stack
+// pointer at the call site, from an address of a function on
+type CFARule struct {
+	Addr   uint64       // First address the rule applies at
+	Reg    hde.Register // The CFA is Reg + Offset, unless Expr is set
+	Offset int64
+	Expr   []byte // DWARF expression computing the CFA, from DW_CFA_def_cfa_expression
+}
+
+// String returns the rule in the notation of readelf, such as rsp+16 or exp
+func (r CFARule) String() string {
+	if r.Expr != nil {
+		return "exp"
+	}
+	return fmt.Sprintf("%v%+d", r.Reg, r.Offset)
+}
+
+// FDE is a frame description entry of .eh_frame, which covers a function, or a part
+// of one split off by the compiler
+type FDE struct {
+	Start uint64    // Address of the first instruction
+	End   uint64    // Address following the last instruction
+	CFA   []CFARule // CFA rules in address order, the first at Start
+}
+
+// frame parses the .eh_frame section of an image
+type frame struct {
+	img  *Image
+	data []byte
+	addr uint64         // Address of .eh_frame
+	mu   sync.Mutex     // Guards cies
+	cies map[int]*cie   // CIEs by section offset
+	text uint64         // Address of .text, for text relative pointers
+	regs []hde.Register // DWARF register numbers
+	ptr  int            // Size of an address
+	bo   binary.ByteOrder
+}
+
+// cie is a common information entry, shared by the FDEs that point to it
+type cie struct {
+	codeAlign uint64
+	dataAlign int64
+	fdeEnc    byte   // Encoding of the addresses in FDEs
+	aug       bool   // Whether FDEs have augmentation data
+	initial   []byte // Initial CFA instructions
+}
+
+// cursor reads the values of a call frame entry
+type cursor struct {
+	*frame
+	data []byte
+	addr uint64 // Address of data[0]
+	off  int
+	err  error
+}
+
+// bytes reads n bytes, recording an error and returning zeros past the end of the
+// entry. The other readers build on it.
+func (c *cursor) bytes(n int) []byte {
+	if c.err != nil || n < 0 || n > len(c.data)-c.off {
+		if c.err == nil {
+			c.err = fmt.Errorf("%w: entry at %#x is truncated", ErrFrame, c.addr)
+		}
+		return make([]byte, max(n, 0))
+	}
+	b := c.data[c.off : c.off+n]
+	c.off += n
+	return b
+}
+
+func (c *cursor) u8() byte      { return c.bytes(1)[0] }
+func (c *cursor) u16() uint16   { return c.bo.Uint16(c.bytes(2)) }
+func (c *cursor) u32() uint32   { return c.bo.Uint32(c.bytes(4)) }
+func (c *cursor) u64() uint64   { return c.bo.Uint64(c.bytes(8)) }
+func (c *cursor) block() []byte { return c.bytes(int(c.uleb())) }
+func (c *cursor) reg() hde.Register {
+	if n := c.uleb(); n < uint64(len(c.regs)) {
+		return c.regs[n]
+	}
+	return hde.RegNone
+}
+
+func (c *cursor) cstring() []byte {
+	n := slices.Index(c.data[c.off:], 0)
+	if n < 0 {
+		c.bytes(len(c.data) - c.off + 1)
+		return nil
+	}
+	b := c.bytes(n)
+	c.off++
+	return b
+}
+
+func (c *cursor) uleb() uint64 {
+	var v uint64
+	for shift := 0; ; shift += 7 {
+		b := c.u8()
+		if shift < 64 {
+			v |= uint64(b&0x7f) << shift
+		}
+		if b&0x80 == 0 || c.err != nil {
+			return v
+		}
+	}
+}
+
+func (c *cursor) sleb() int64 {
+	var v int64
+	shift := 0
+	for {
+		b := c.u8()
+		if shift < 64 {
+			v |= int64(b&0x7f) << shift
+		}
+		shift += 7
+		if b&0x80 == 0 || c.err != nil {
+			if shift < 64 && b&0x40 != 0 {
+				v |= -1 << shift
+			}
+			return v
+		}
+	}
+}
+
+// pointer reads an address with the DW_EH_PE encoding, relative to base for data
+// relative addresses
+func (c *cursor) pointer(enc byte, base uint64) uint64 {
+	if enc == peOmit {
+		return 0
+	}
+	pc := c.addr + uint64(c.off)
+	var v uint64
+	switch enc & 0x0f {
+	case peAbsPtr:
+		if enc&0x70 == peAligned {
+			c.bytes(int(-pc) & (c.ptr - 1))
+		}
+		if c.ptr == 8 {
+			v = c.u64()
+		} else {
+			v = uint64(c.u32())
+		}
+	case peULEB128:
+		v = c.uleb()
+	case peUData2:
+		v = uint64(c.u16())
+	case peUData4:
+		v = uint64(c.u32())
+	case peUData8:
+		v = c.u64()
+	case peSLEB128:
+		v = uint64(c.sleb())
+	case peSData2:
+		v = uint64(int16(c.u16()))
+	case peSData4:
+		v = uint64(int32(c.u32()))
+	case peSData8:
+		v = c.u64()
+	default:
+		c.err = fmt.Errorf("%w: pointer encoding %#x", ErrFrame, enc)
+		return 0
+	}
+	switch enc & 0x70 {
+	case pePCRel:
+		v += pc
+	case peTextRel:
+		v += c.text
+	case peDataRel:
+		v += base
+	}
+	if c.ptr == 4 {
+		v = uint64(uint32(v))
+	}
+	if enc&peIndir != 0 && c.err == nil {
+		// The address is read from a pointer in the image, such as a GOT entry
+		var err error
+		if v, err = c.img.pointer(v); err != nil {
+			c.err = err
+		}
+	}
+	return v
+}
+
+// ehFrame returns the parser of the .eh_frame section, or nil if the image does not
+// have one. The parser is built once and shares its CIEs across calls.
+func (img *Image) ehFrame() (*frame, error) {
+	img.frameOnce.Do(func() {
+		img.frame, img.frameErr = img.newFrame()
+	})
+	return img.frame, img.frameErr
+}
+
+// newFrame returns a parser of the .eh_frame section, or nil if the image does not
+// have one
+func (img *Image) newFrame() (*frame, error) {
+	s := img.File.Section(".eh_frame")
+	if s == nil || s.Type == elf.SHT_NOBITS {
+		return nil, nil
+	}
+	data, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("elf: reading .eh_frame: %w", err)
+	}
+	f := &frame{img: img, data: data, addr: s.Addr, cies: map[int]*cie{}, bo: img.File.ByteOrder, ptr: 8, regs: dwarfRegs64}
+	if img.File.Class == elf.ELFCLASS32 {
+		f.ptr, f.regs = 4, dwarfRegs32
+	}
+	if text := img.File.Section(".text"); text != nil {
+		f.text = text.Addr
+	}
+	return f, nil
+}
+
+// pointer reads a pointer-sized value of the image at the address
+func (img *Image) pointer(addr uint64) (uint64, error) {
+	for _, p := range img.File.Progs {
+		if p.Type != elf.PT_LOAD || addr < p.Vaddr || addr-p.Vaddr >= p.Filesz {
+			continue
+		}
+		b := make([]byte, 8)
+		if img.File.Class == elf.ELFCLASS32 {
+			b = b[:4]
+		}
+		if _, err := p.ReadAt(b, int64(addr-p.Vaddr)); err != nil {
+			return 0, fmt.Errorf("elf: reading %#x: %w", addr, err)
+		}
+		if len(b) == 4 {
+			return uint64(img.File.ByteOrder.Uint32(b)), nil
+		}
+		return img.File.ByteOrder.Uint64(b), nil
+	}
+	return 0, fmt.Errorf("%w: indirect pointer at %#x is not mapped", ErrFrame, addr)
+}
+
+// entry returns a cursor over the entry at the section offset, positioned after its
+// length, and the offset of the next entry. A zero length terminates the section.
+func (f *frame) entry(off int) (*cursor, int, error) {
+	c := &cursor{frame: f, data: f.data, addr: f.addr, off: off}
+	n := uint64(c.u32())
+	if n == 0xffffffff {
+		n = c.u64()
+	}
+	if c.err != nil || n > uint64(len(f.data)-c.off) {
+		return nil, 0, fmt.Errorf("%w: entry at %#x runs past the end of .eh_frame", ErrFrame, f.addr+uint64(off))
+	}
+	end := c.off + int(n)
+	c.data = f.data[:end]
+	return c, end, nil
+}
+
+// cie parses the CIE at the section offset
+func (f *frame) cie(off int) (*cie, error) {
+	f.mu.Lock()
+	ci, ok := f.cies[off]
+	f.mu.Unlock()
+	if ok {
+		return ci, nil
+	}
+	c, _, err := f.entry(off)
+	if err != nil {
+		return nil, err
+	}
+	if id := c.u32(); id != 0 {
+		return nil, fmt.Errorf("%w: FDE at %#x points to another FDE", ErrFrame, f.addr+uint64(off))
+	}
+	ci = &cie{fdeEnc: peAbsPtr}
+	version := c.u8()
+	aug := c.cstring()
+	if strings.Contains(string(aug), "eh") {
+		c.bytes(f.ptr)
+	}
+	ci.codeAlign = c.uleb()
+	ci.dataAlign = c.sleb()
+	if version == 1 {
+		c.u8()
+	} else {
+		c.uleb()
+	}
+	if len(aug) != 0 && aug[0] == 'z' {
+		ci.aug = true
+		data := c.block()
+		ac := &cursor{frame: f, data: data, addr: f.addr + uint64(c.off-len(data))}
+		for _, a := range aug[1:] {
+			switch a {
+			case 'R':
+				ci.fdeEnc = ac.u8()
+			case 'P':
+				ac.pointer(ac.u8()&^peIndir, 0)
+			case 'L':
+				ac.u8()
+			}
+		}
+		if ac.err != nil {
+			c.err = ac.err
+		}
+	}
+	ci.initial = c.data[min(c.off, len(c.data)):]
+	if c.err != nil {
+		return nil, c.err
+	}
+	f.mu.Lock()
+	f.cies[off] = ci
+	f.mu.Unlock()
+	return ci, nil
+}
+
+// fde parses the FDE at the section offset, returning nil for a CIE
+func (f *frame) fde(off int) (*FDE, error) {
+	c, _, err := f.entry(off)
+	if err != nil {
+		return nil, err
+	}
+	idOff := c.off
+	id := c.u32()
+	if id == 0 {
+		return nil, nil
+	}
+	if uint64(id) > uint64(idOff) {
+		return nil, fmt.Errorf("%w: FDE at %#x points before .eh_frame", ErrFrame, f.addr+uint64(off))
+	}
+	ci, err := f.cie(idOff - int(id))
+	if err != nil {
+		return nil, err
+	}
+	fde := &FDE{Start: c.pointer(ci.fdeEnc, 0)}
+	fde.End = fde.Start + c.pointer(ci.fdeEnc&0x0f, 0)
+	if ci.aug {
+		c.block()
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	fde.CFA, err = f.run(ci, fde, c.data[c.off:])
+	return fde, err
+}
+
+// run executes the initial instructions of the CIE and the instructions of the FDE,
+// returning the CFA rules. Rules for the other registers are skipped.
+func (f *frame) run(ci *cie, fde *FDE, prog []byte) ([]CFARule, error) {
+	var rules []CFARule
+	var stack []CFARule
+	cur := CFARule{Addr: fde.Start}
+	set := func(r CFARule) {
+		r.Addr = cur.Addr
+		cur = r
+		if n := len(rules); n != 0 && rules[n-1].Addr == r.Addr {
+			rules[n-1] = r
+		} else {
+			rules = append(rules, r)
+		}
+	}
+	for _, code := range [][]byte{ci.initial, prog} {
+		c := &cursor{frame: f, data: code, addr: f.addr}
+		for c.off < len(code) && c.err == nil {
+			op := c.u8()
+			advance := uint64(0)
+			switch op >> 6 {
+			case 1: // DW_CFA_advance_loc
+				advance = uint64(op & 0x3f)
+			case 2: // DW_CFA_offset
+				c.uleb()
+			case 3: // DW_CFA_restore
+			default:
+				switch op {
+				case 0x00: // DW_CFA_nop
+				case 0x0a: // DW_CFA_remember_state
+					stack = append(stack, cur)
+				case 0x01: // DW_CFA_set_loc
+					cur.Addr = c.pointer(ci.fdeEnc, 0)
+				case 0x02: // DW_CFA_advance_loc1
+					advance = uint64(c.u8())
+				case 0x03: // DW_CFA_advance_loc2
+					advance = uint64(c.u16())
+				case 0x04: // DW_CFA_advance_loc4
+					advance = uint64(c.u32())
+				case 0x05, 0x09, 0x14, 0x2f: // DW_CFA_offset_extended, register, val_offset, GNU_negative_offset_extended
+					c.uleb()
+					c.uleb()
+				case 0x06, 0x07, 0x08, 0x2e: // DW_CFA_restore_extended, undefined, same_value, GNU_args_size
+					c.uleb()
+				case 0x0b: // DW_CFA_restore_state
+					if len(stack) == 0 {
+						return nil, fmt.Errorf("%w: DW_CFA_restore_state without a saved state at %#x", ErrFrame, cur.Addr)
+					}
+					r := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					set(r)
+				case 0x0c: // DW_CFA_def_cfa
+					reg := c.reg()
+					set(CFARule{Reg: reg, Offset: int64(c.uleb())})
+				case 0x0d: // DW_CFA_def_cfa_register
+					set(CFARule{Reg: c.reg(), Offset: cur.Offset})
+				case 0x0e: // DW_CFA_def_cfa_offset
+					set(CFARule{Reg: cur.Reg, Offset: int64(c.uleb())})
+				case 0x0f: // DW_CFA_def_cfa_expression
+					set(CFARule{Expr: c.block()})
+				case 0x10, 0x16: // DW_CFA_expression, val_expression
+					c.uleb()
+					c.block()
+				case 0x11, 0x15: // DW_CFA_offset_extended_sf, val_offset_sf
+					c.uleb()
+					c.sleb()
+				case 0x12: // DW_CFA_def_cfa_sf
+					reg := c.reg()
+					set(CFARule{Reg: reg, Offset: c.sleb() * ci.dataAlign})
+				case 0x13: // DW_CFA_def_cfa_offset_sf
+					set(CFARule{Reg: cur.Reg, Offset: c.sleb() * ci.dataAlign})
+				default:
+					return nil, fmt.Errorf("%w: unknown CFA instruction %#x at %#x", ErrFrame, op, cur.Addr)
+				}
+			}
+			if advance != 0 {
+				cur.Addr += advance * ci.codeAlign
+				if cur.Addr >= fde.End {
+					return rules, c.err
+				}
+			}
+		}
+		if c.err != nil {
+			return nil, c.err
+		}
+	}
+	return rules, nil
+}
+
+// FDEs returns the frame description entries of .eh_frame, which cover the functions
+// of binaries compiled with unwind tables even when they are stripped, in section
+// order. Entries with an empty range are left out.
+func (img *Image) FDEs() ([]FDE, error) {
+	f, err := img.ehFrame()
+	if f == nil || err != nil {
+		return nil, err
+	}
+	var fdes []FDE
+	for off := 0; off+4 <= len(f.data); {
+		if f.bo.Uint32(f.data[off:]) == 0 {
+			break
+		}
+		_, next, err := f.entry(off)
+		if err != nil {
+			return nil, err
+		}
+		fde, err := f.fde(off)
+		if err != nil {
+			return nil, err
+		}
+		if fde != nil && fde.End > fde.Start {
+			fdes = append(fdes, *fde)
+		}
+		off = next
+	}
+	return fdes, nil
+}
+
+// FindFDE returns the FDE covering the address, looked up in the binary search table
+// of .eh_frame_hdr, or nil if there is none
+func (img *Image) FindFDE(addr uint64) (*FDE, error) {
+	f, err := img.ehFrame()
+	if f == nil || err != nil {
+		return nil, err
+	}
+	s := img.File.Section(".eh_frame_hdr")
+	if s == nil {
+		return nil, fmt.Errorf("%w: no .eh_frame_hdr", ErrFrame)
+	}
+	data, err := s.Data()
+	if err != nil {
+		return nil, fmt.Errorf("elf: reading .eh_frame_hdr: %w", err)
+	}
+	c := &cursor{frame: f, data: data, addr: s.Addr}
+	if version := c.u8(); version != 1 && c.err == nil {
+		return nil, fmt.Errorf("%w: .eh_frame_hdr version %d", ErrFrame, version)
+	}
+	ptrEnc, countEnc, tableEnc := c.u8(), c.u8(), c.u8()
+	c.pointer(ptrEnc, s.Addr)
+	count := c.pointer(countEnc, s.Addr)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if countEnc == peOmit || tableEnc != peDataRel|peSData4 {
+		// Only the table encoding every linker emits is searched
+		return nil, fmt.Errorf("%w: .eh_frame_hdr table encoding %#x", ErrFrame, tableEnc)
+	}
+	table := c.off
+	if count > uint64(len(data)-table)/8 {
+		return nil, fmt.Errorf("%w: .eh_frame_hdr table is truncated", ErrFrame)
+	}
+	entry := func(i int) (uint64, uint64) {
+		c.off = table + i*8
+		return c.pointer(tableEnc, s.Addr), c.pointer(tableEnc, s.Addr)
+	}
+
+	// The last entry starting at or before the address
+	lo, hi := 0, int(count)
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		if start, _ := entry(mid); start <= addr {
+			lo = mid + 1
+		} else {
+			hi = mid
+		}
+	}
+	if lo == 0 {
+		return nil, nil
+	}
+	_, fdeAddr := entry(lo - 1)
+	if fdeAddr < f.addr || fdeAddr-f.addr >= uint64(len(f.data)) {
+		return nil, fmt.Errorf("%w: .eh_frame_hdr entry %#x is outside .eh_frame", ErrFrame, fdeAddr)
+	}
+	fde, err := f.fde(int(fdeAddr - f.addr))
+	if err != nil || fde == nil || addr >= fde.End {
+		return nil, err
+	}
+	return fde, nil
+}
+
+// FrameFunctions decodes the range of every FDE, naming them by the function symbols
+// at their start if the binary has any
+func (img *Image) FrameFunctions() ([]*Function, error) {
+	fdes, err := img.FDEs()
+	if err != nil {
+		return nil, err
+	}
+	syms, err := img.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	names := map[uint64]string{}
+	for _, sym := range syms {
+		if _, ok := names[sym.Addr]; !ok {
+			names[sym.Addr] = sym.Name
+		}
+	}
+	fns := make([]*Function, len(fdes))
+	for i, fde := range fdes {
+		sym := Symbol{Name: names[fde.Start], Addr: fde.Start, Size: fde.End - fde.Start}
+		fns[i] = img.DecodeFunction(sym, fde.End)
+	}
+	return fns, nil
+}
@@ -0,0 +1,222 @@
+// Package elf maps the executable sections of ELF binaries and decodes the functions
+// named by their symbol tables.
+package elf
+
+import (
+	"cmp"
+	"debug/elf"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/flow"
+	"github.com/can1357/go-hde/internal/image"
+)
+
+var (
+	// ErrMachine is returned for binaries that are not x86 or x86-64 code
+	ErrMachine = errors.New("elf: unsupported machine type")
+	// ErrUnmapped is returned for addresses outside of the executable sections
+	ErrUnmapped = errors.New("elf: address not in an executable section")
+	// ErrOverrun is reported for instructions that run past the end of their function
+	ErrOverrun = errors.New("elf: instruction runs past the end of the function")
+)
+
+// Section is an executable section mapped at its address
+type Section struct {
+	Name string // Section name, such as .text or .plt
+	Addr uint64 // Address of the first byte
+	Data []byte // Contents
+}
+
+// Symbol is a function symbol
+type Symbol struct {
+	Name    string
+	Addr    uint64 // Address of the first instruction
+	Size    uint64 // Size in bytes, or 0 if the symbol table does not record it
+	Dynamic bool   // Whether the symbol comes from .dynsym rather than .symtab
+}
+
+// InstError is an error decoding the instruction at an address
+type InstError struct {
+	Addr uint64
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *InstError) Error() string {
+	return fmt.Sprintf("%#x: %s", e.Addr, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *InstError) Unwrap() error {
+	return e.Err
+}
+
+// Function is a function symbol with its decoded instructions
+type Function struct {
+	Symbol
+	End    uint64     // Address the function ends at
+	Insts  []hde.Inst // Instructions in address order
+	Errors []error    // InstErrors for bytes that did not decode and for instructions past End
+}
+
+// Image is an ELF binary with its executable sections mapped at their addresses.
+// Sections lists them in header order: .init, .plt, .text, .fini and others. Mode is
+// Mode32 for 32-bit x86 binaries and Mode64 for x86-64 ones. The embedded image
+// provides Section, Decode, Instructions and Close.
+type Image struct {
+	image.Image[*Section]
+	File *elf.File
+
+	frameOnce sync.Once // Guards frame and frameErr
+	frame     *frame    // Parser of .eh_frame, built on first use
+	frameErr  error
+}
+
+// Open opens the named ELF file
+func Open(name string) (*Image, error) {
+	return image.Open(name, NewImage)
+}
+
+// NewImage reads an ELF binary from r and maps its executable sections
+func NewImage(r io.ReaderAt) (*Image, error) {
+	f, err := elf.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+	var mode *hde.Mode
+	switch {
+	case f.Machine == elf.EM_386 && f.Class == elf.ELFCLASS32:
+		mode = hde.Mode32
+	case f.Machine == elf.EM_X86_64:
+		// Including the x32 ABI, which runs 64-bit code with 32-bit pointers
+		mode = hde.Mode64
+	default:
+		return nil, fmt.Errorf("%w: %v, %v", ErrMachine, f.Class, f.Machine)
+	}
+	img := &Image{Image: image.New[*Section](mode, ErrUnmapped), File: f}
+
+	for _, s := range f.Sections {
+		if s.Flags&elf.SHF_EXECINSTR == 0 || s.Flags&elf.SHF_ALLOC == 0 || s.Type == elf.SHT_NOBITS {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("elf: reading section %s: %w", s.Name, err)
+		}
+		image.Map(&img.Image, &Section{Name: s.Name, Addr: s.Addr, Data: data}, s.Addr, data, true)
+	}
+	return img, nil
+}
+
+// Symbols returns the defined function symbols in executable sections from .symtab
+// and .dynsym, in address order. Symbols present in both tables are listed once,
+// from .symtab.
+func (img *Image) Symbols() ([]Symbol, error) {
+	type key struct {
+		name string
+		addr uint64
+	}
+	seen := map[key]bool{}
+	var syms []Symbol
+	add := func(list []elf.Symbol, dynamic bool) {
+		for _, s := range list {
+			if elf.ST_TYPE(s.Info) != elf.STT_FUNC || s.Section == elf.SHN_UNDEF || img.Section(s.Value) == nil {
+				continue
+			}
+			if k := (key{s.Name, s.Value}); !seen[k] {
+				seen[k] = true
+				syms = append(syms, Symbol{Name: s.Name, Addr: s.Value, Size: s.Size, Dynamic: dynamic})
+			}
+		}
+	}
+
+	symtab, err := img.File.Symbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return nil, fmt.Errorf("elf: reading .symtab: %w", err)
+	}
+	add(symtab, false)
+	dynsym, err := img.File.DynamicSymbols()
+	if err != nil && !errors.Is(err, elf.ErrNoSymbols) {
+		return nil, fmt.Errorf("elf: reading .dynsym: %w", err)
+	}
+	add(dynsym, true)
+
+	slices.SortStableFunc(syms, func(a, b Symbol) int { return cmp.Compare(a.Addr, b.Addr) })
+	return syms, nil
+}
+
+// DecodeFunction decodes the instructions of the function from its address to its
+// end. Bytes that do not decode are skipped one at a time, and an instruction
+// crossing end is kept and reported with ErrOverrun.
+func (img *Image) DecodeFunction(sym Symbol, end uint64) *Function {
+	fn := &Function{Symbol: sym, End: end}
+	for inst, err := range img.Instructions(sym.Addr) {
+		if inst.Addr >= end {
+			break
+		}
+		if err != nil {
+			fn.Errors = append(fn.Errors, &InstError{inst.Addr, err})
+			if errors.Is(err, ErrUnmapped) {
+				break
+			}
+			continue
+		}
+		fn.Insts = append(fn.Insts, inst)
+		if inst.Addr+uint64(len(inst.Bytes)) > end {
+			fn.Errors = append(fn.Errors, &InstError{inst.Addr, ErrOverrun})
+			break
+		}
+	}
+	return fn
+}
+
+// Functions decodes every function symbol. Functions without a recorded size extend
+// to the next function symbol or the end of their section.
+func (img *Image) Functions() ([]*Function, error) {
+	syms, err := img.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	var fns []*Function
+	for i, sym := range syms {
+		end := sym.Addr + sym.Size
+		if sym.Size == 0 {
+			s := img.Section(sym.Addr)
+			end = s.Addr + uint64(len(s.Data))
+			for _, next := range syms[i+1:] {
+				if next.Addr > sym.Addr {
+					end = min(end, next.Addr)
+					break
+				}
+			}
+		}
+		fns = append(fns, img.DecodeFunction(sym, end))
+	}
+	return fns, nil
+}
+
+// Disassemble decodes the executable sections by recursive descent from the entry
+// point and the function symbols of the binary
+func (img *Image) Disassemble() (*flow.Graph, error) {
+	syms, err := img.Symbols()
+	if err != nil {
+		return nil, err
+	}
+	var entries []uint64
+	if img.Section(img.File.Entry) != nil {
+		entries = append(entries, img.File.Entry)
+	}
+	for _, sym := range syms {
+		entries = append(entries, sym.Addr)
+	}
+	segs := make([]flow.Segment, len(img.Sections))
+	for i, s := range img.Sections {
+		segs[i] = flow.Segment{Addr: s.Addr, Data: s.Data}
+	}
+	return flow.DisassembleSegments(img.Mode, segs, entries...)
+}
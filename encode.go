@@ -0,0 +1,129 @@
+package hde
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var (
+	// ErrLiteralRange is returned when a displacement or immediate does not fit its width
+	ErrLiteralRange error = &DecoderError{errors.New("literal out of range")}
+	// ErrEncoding is returned when the fields of an instruction do not describe the bytes they encode to
+	ErrEncoding error = &DecoderError{errors.New("inconsistent instruction fields")}
+)
+
+// layoutFlags are the flags describing the ModR/M, SIB, displacement and immediate layout
+const layoutFlags = Flag(1)<<prefixSetShift - 1
+
+// fits reports whether the literal value can be stored in its width, either as an
+// unsigned value or as a sign-extended negative one
+func (lit Literal) fits() bool {
+	switch lit.Bits {
+	case 0:
+		return lit.Value == 0
+	case 8, 16, 32:
+		return lit.Value>>lit.Bits == 0 || int64(lit.Value)>>(lit.Bits-1) == -1
+	case 64:
+		return true
+	}
+	return false
+}
+
+// appendTo appends the literal in little endian order at its width
+func (lit Literal) appendTo(b []byte) []byte {
+	switch lit.Bits {
+	case 8:
+		return append(b, byte(lit.Value))
+	case 16:
+		return binary.LittleEndian.AppendUint16(b, uint16(lit.Value))
+	case 32:
+		return binary.LittleEndian.AppendUint32(b, uint32(lit.Value))
+	case 64:
+		return binary.LittleEndian.AppendUint64(b, lit.Value)
+	}
+	return b
+}
+
+// appendPrefixes appends the legacy prefixes of the instruction. Prefixes recorded by
+// Decode are written back in their original order, otherwise one of each prefix in
+// Flags is written.
+func (insn *Insn) appendPrefixes(b []byte) []byte {
+	if insn.NumPrefixes != 0 {
+		return append(b, insn.Prefixes[:insn.NumPrefixes]...)
+	}
+	for _, pfx := range [...]byte{insn.LockPrefix(), insn.RepPrefix(), insn.SegmentPrefix(), insn.OpSizePrefix(), insn.AddrSizePrefix()} {
+		if pfx != 0 {
+			b = append(b, pfx)
+		}
+	}
+	return b
+}
+
+// Encode serializes the instruction back to machine code. The prefixes, REX, VEX or
+// EVEX prefix, opcode, ModR/M, SIB, displacement and immediate are written from the
+// fields of insn, so the bytes of a decoded instruction are reproduced exactly and
+// edits to Disp and Imm are carried over at their current width. Values out of range
+// of their width yield ErrLiteralRange; fields that do not decode back to the same
+// length and operand layout in this mode yield ErrEncoding.
+func (mode *Mode) Encode(insn *Insn) ([]byte, error) {
+	if !insn.Imm.fits() || !insn.Disp.fits() {
+		return nil, ErrLiteralRange
+	}
+	if int(insn.NumPrefixes) > len(insn.Prefixes) {
+		return nil, ErrEncoding
+	}
+
+	b := make([]byte, 0, MaxInsnLen)
+	b = insn.appendPrefixes(b)
+	switch {
+	case insn.Flags&HasEVEX != 0:
+		b = append(b, EVEX4, insn.EVEX.P0(), insn.EVEX.P1(), insn.EVEX.P2())
+		b = append(b, insn.OpcodeByte())
+	case insn.Flags&HasVEX != 0:
+		b1, b2 := byte(insn.VEX>>8), byte(insn.VEX>>16)
+		if insn.VEX.Escape() == VEX2 {
+			b = append(b, VEX2, b1&0x80|b2&0x7f)
+		} else {
+			b = append(b, VEX3, b1, b2)
+		}
+		b = append(b, insn.OpcodeByte())
+	default:
+		if insn.Flags&HasREX != 0 {
+			b = append(b, byte(insn.REX))
+		}
+		b = append(b, insn.Opcode)
+		if insn.Opcode == 0x0f {
+			b = append(b, insn.Opcode2)
+			if insn.Opcode2 == 0x38 || insn.Opcode2 == 0x3a {
+				b = append(b, insn.Opcode3)
+			}
+		}
+	}
+
+	if insn.Flags&IsModRM != 0 {
+		b = append(b, byte(insn.ModRM))
+		if insn.Flags&IsSIB != 0 {
+			b = append(b, byte(insn.SIB))
+		}
+		b = insn.Disp.appendTo(b)
+		b = insn.Imm.appendTo(b)
+	} else {
+		// Without a ModR/M byte, Disp holds the second immediate of far pointers and ENTER
+		b = insn.Imm.appendTo(b)
+		b = insn.Disp.appendTo(b)
+	}
+	if len(b) > MaxInsnLen {
+		return nil, ErrEncoding
+	}
+
+	// The bytes must decode to the same layout, or edited fields would be misread
+	dec, err := mode.Decode(b)
+	if err != nil {
+		return nil, err
+	}
+	if int(dec.Length) != len(b) || dec.Flags&layoutFlags != insn.Flags&layoutFlags ||
+		dec.Imm.Bits != insn.Imm.Bits || dec.Disp.Bits != insn.Disp.Bits {
+		return nil, ErrEncoding
+	}
+	return b, nil
+}
@@ -0,0 +1,386 @@
+package hde
+
+import "fmt"
+
+// EVEX escape byte
+const EVEX4 byte = 0x62
+
+// EVEX represents the three payload bytes (P0, P1, P2) following the 62h escape byte.
+// Inverted fields are stored as encoded.
+type EVEX uint32
+
+// newEVEX builds an EVEX from the three payload bytes
+func newEVEX(p0, p1, p2 byte) EVEX {
+	return EVEX(p0) | EVEX(p1)<<8 | EVEX(p2)<<16
+}
+
+// valid returns true if the fixed bits of the payload hold their required values
+func (e EVEX) valid() bool {
+	return e&0x08 == 0 && e&0x400 != 0
+}
+
+// P0 returns the first payload byte (R.X.B.R'.0.mmm)
+func (e EVEX) P0() byte {
+	return byte(e)
+}
+
+// P1 returns the second payload byte (W.vvvv.1.pp)
+func (e EVEX) P1() byte {
+	return byte(e >> 8)
+}
+
+// P2 returns the third payload byte (z.L'L.b.V'.aaa)
+func (e EVEX) P2() byte {
+	return byte(e >> 16)
+}
+
+// R returns the ModR/M reg field extension from the EVEX prefix
+func (e EVEX) R() uint8 {
+	return ^uint8(e>>7) & 1
+}
+
+// X returns the SIB index field extension from the EVEX prefix.
+// With a register r/m operand it is the high bit of the 32-register index instead.
+func (e EVEX) X() uint8 {
+	return ^uint8(e>>6) & 1
+}
+
+// B returns the ModR/M r/m or SIB base field extension from the EVEX prefix
+func (e EVEX) B() uint8 {
+	return ^uint8(e>>5) & 1
+}
+
+// R2 returns the high-16 register extension (R') of the ModR/M reg field
+func (e EVEX) R2() uint8 {
+	return ^uint8(e>>4) & 1
+}
+
+// Map returns the opcode map selector (mmm) from the EVEX prefix.
+// 1 selects the 0F map, 2 the 0F38 map and 3 the 0F3A map.
+func (e EVEX) Map() uint8 {
+	return uint8(e) & 7
+}
+
+// W returns the operand size / opcode extension bit from the EVEX prefix
+func (e EVEX) W() uint8 {
+	return uint8(e>>15) & 1
+}
+
+// V returns the additional register specifier (vvvv) from the EVEX prefix
+func (e EVEX) V() uint8 {
+	return ^uint8(e>>11) & 0xf
+}
+
+// PP returns the implied mandatory prefix selector (pp) from the EVEX prefix
+func (e EVEX) PP() uint8 {
+	return uint8(e>>8) & 3
+}
+
+// Z returns the zeroing-masking bit from the EVEX prefix
+func (e EVEX) Z() uint8 {
+	return uint8(e>>23) & 1
+}
+
+// LL returns the vector length (L'L) from the EVEX prefix.
+// 0 is 128-bit, 1 is 256-bit and 2 is 512-bit; with a register-only operand and
+// the b bit set it selects the static rounding mode instead.
+func (e EVEX) LL() uint8 {
+	return uint8(e>>21) & 3
+}
+
+// Bcst returns the broadcast / rounding control / SAE bit (b) from the EVEX prefix
+func (e EVEX) Bcst() uint8 {
+	return uint8(e>>20) & 1
+}
+
+// V2 returns the high-16 register extension (V') of the vvvv field
+func (e EVEX) V2() uint8 {
+	return ^uint8(e>>19) & 1
+}
+
+// AAA returns the opmask register specifier (aaa) from the EVEX prefix
+func (e EVEX) AAA() uint8 {
+	return uint8(e>>16) & 7
+}
+
+// Prefix returns the legacy prefix byte implied by the pp field, if any
+func (e EVEX) Prefix() byte {
+	return ppToPrefix[e.PP()]
+}
+
+func (e EVEX) String() string {
+	return fmt.Sprintf("EVEX[R=%d,X=%d,B=%d,R'=%d,map=%d,W=%d,v=%d,pp=%d,z=%d,L'L=%d,b=%d,V'=%d,aaa=%d]",
+		e.R(), e.X(), e.B(), e.R2(), e.Map(), e.W(), e.V(), e.PP(), e.Z(), e.LL(), e.Bcst(), e.V2(), e.AAA())
+}
+
+// Disp8N returns the scale applied to an 8-bit displacement.
+// EVEX-encoded instructions compress their displacement by the size of the memory
+// access (disp8*N), for every other instruction the scale is 1.
+func (insn *Insn) Disp8N() uint8 {
+	if insn.Flags&HasEVEX == 0 || insn.Flags&HasDisp8 == 0 {
+		return 1
+	}
+	e := insn.EVEX
+	vl := uint8(16) << e.LL()
+	w := e.W()
+	switch evexTuple(e.Map(), e.PP(), insn.OpcodeByte(), w, insn.ModRM.Reg()) {
+	case tupleFV:
+		if e.Bcst() != 0 {
+			return 4 << w
+		}
+		return vl
+	case tupleHV:
+		if e.Bcst() != 0 {
+			return 4
+		}
+		return vl >> 1
+	case tupleHF:
+		if e.Bcst() != 0 {
+			return 4 << w
+		}
+		if w == 0 {
+			return vl >> 1
+		}
+		return vl
+	case tupleFVM:
+		return vl
+	case tupleHVM:
+		return vl >> 1
+	case tupleQVM:
+		return vl >> 2
+	case tupleOVM:
+		return vl >> 3
+	case tupleT1S:
+		return 4 << w
+	case tupleT1S8:
+		return 1
+	case tupleT1S16:
+		return 2
+	case tupleT1SBW:
+		return 1 << w
+	case tupleT1F32:
+		return 4
+	case tupleT1F64:
+		return 8
+	case tupleT2:
+		return 8 << w
+	case tupleM128:
+		return 16
+	case tupleT8:
+		return 32
+	case tupleDUP:
+		if vl == 16 {
+			return 8
+		}
+		return vl
+	}
+	return 1
+}
+
+// Displacement returns the sign-extended displacement with the disp8*N scaling applied.
+// Returns 0,false if the instruction has no displacement.
+func (insn *Insn) Displacement() (int64, bool) {
+	d, ok := insn.Disp.Int()
+	if !ok || insn.Disp.Bits != 8 {
+		return d, ok
+	}
+	return d * int64(insn.Disp8N()), true
+}
+
+// EVEX memory operand tuple types, see the Intel SDM section 2.7.5
+type tupleType uint8
+
+const (
+	tupleNone  tupleType = iota // No memory operand
+	tupleFV                     // Full vector, broadcast element size from W
+	tupleHV                     // Half vector, broadcast of 32-bit elements
+	tupleHF                     // Half vector if W0 (32-bit source elements), full vector otherwise
+	tupleFVM                    // Full vector memory
+	tupleHVM                    // Half vector memory
+	tupleQVM                    // Quarter vector memory
+	tupleOVM                    // Eighth vector memory
+	tupleT1S                    // Single element, size from W
+	tupleT1S8                   // Single byte element
+	tupleT1S16                  // Single word element
+	tupleT1SBW                  // Single byte element if W0, word element otherwise
+	tupleT1F32                  // Single 32-bit element regardless of W
+	tupleT1F64                  // Single 64-bit element regardless of W
+	tupleT2                     // Two elements, size from W
+	tupleM128                   // Fixed 128-bit access
+	tupleT8                     // Fixed 256-bit access
+	tupleDUP                    // MOVDDUP access
+)
+
+// evexTuple returns the memory tuple type of an EVEX-encoded instruction
+func evexTuple(mmm, pp, op, w, reg uint8) tupleType {
+	const np, p66, pF3, pF2 = 0, 1, 2, 3
+	switch mmm {
+	case 1:
+		switch op {
+		case 0x10, 0x11:
+			if pp >= pF3 {
+				return tupleT1S
+			}
+			return tupleFVM
+		case 0x12:
+			switch pp {
+			case np:
+				return tupleT2
+			case p66:
+				return tupleT1S
+			case pF3:
+				return tupleFVM
+			}
+			return tupleDUP
+		case 0x13, 0x17:
+			if pp == np {
+				return tupleT2
+			}
+			return tupleT1S
+		case 0x16:
+			switch pp {
+			case np:
+				return tupleT2
+			case p66:
+				return tupleT1S
+			}
+			return tupleFVM
+		case 0x28, 0x29, 0x2b:
+			return tupleFVM
+		case 0x2a, 0x2e, 0x2f:
+			return tupleT1S
+		case 0x2c, 0x2d, 0x78, 0x79:
+			switch pp {
+			case pF3:
+				return tupleT1F32
+			case pF2:
+				return tupleT1F64
+			}
+			return tupleFV
+		case 0x51, 0x58, 0x59, 0x5c, 0x5d, 0x5e, 0x5f, 0xc2:
+			if pp >= pF3 {
+				return tupleT1S
+			}
+			return tupleFV
+		case 0x5a:
+			switch pp {
+			case np:
+				return tupleHV
+			case p66:
+				return tupleFV
+			case pF3:
+				return tupleT1F32
+			}
+			return tupleT1F64
+		case 0x7a:
+			if pp == pF2 {
+				return tupleFV
+			}
+			return tupleHF
+		case 0x7b:
+			if pp >= pF3 {
+				return tupleT1S
+			}
+			return tupleHF
+		case 0xe6:
+			if pp == pF3 {
+				return tupleHF
+			}
+			return tupleFV
+		case 0x6e, 0x7e, 0xd6:
+			if pp == pF3 || op == 0xd6 {
+				return tupleT1F64
+			}
+			return tupleT1S
+		case 0x70:
+			if pp == p66 {
+				return tupleFV
+			}
+			return tupleFVM
+		case 0x73:
+			if reg == 3 || reg == 7 {
+				return tupleFVM
+			}
+			return tupleFV
+		case 0xc4:
+			return tupleT1S16
+		case 0xd1, 0xd2, 0xd3, 0xe1, 0xe2, 0xf1, 0xf2, 0xf3:
+			return tupleM128
+		case 0x14, 0x15, 0x54, 0x55, 0x56, 0x57, 0x5b, 0x62, 0x66, 0x6a, 0x6b, 0x6c, 0x6d,
+			0x72, 0x76, 0xc6, 0xd4, 0xdb, 0xdf, 0xeb, 0xef, 0xf4, 0xfa, 0xfb, 0xfe:
+			return tupleFV
+		}
+		return tupleFVM
+	case 2:
+		if pp == pF3 {
+			switch op {
+			case 0x10, 0x13, 0x15, 0x20, 0x23, 0x25, 0x30, 0x33, 0x35:
+				return tupleHVM
+			case 0x11, 0x14, 0x21, 0x24, 0x31, 0x34:
+				return tupleQVM
+			case 0x12, 0x22, 0x32:
+				return tupleOVM
+			case 0x26:
+				return tupleFVM
+			case 0x27, 0x52, 0x72:
+				return tupleFV
+			}
+			return tupleNone
+		}
+		switch op {
+		case 0x00, 0x04, 0x0b, 0x10, 0x11, 0x12, 0x1c, 0x1d, 0x26, 0x2a, 0x38, 0x3a, 0x3c, 0x3e,
+			0x54, 0x66, 0x70, 0x75, 0x7d, 0x8d, 0x8f, 0xcf, 0xdc, 0xdd, 0xde, 0xdf:
+			return tupleFVM
+		case 0x72:
+			if pp == p66 {
+				return tupleFVM
+			}
+		case 0x13, 0x20, 0x23, 0x25, 0x30, 0x33, 0x35:
+			return tupleHVM
+		case 0x21, 0x24, 0x31, 0x34:
+			return tupleQVM
+		case 0x22, 0x32:
+			return tupleOVM
+		case 0x18, 0x2d, 0x43, 0x4d, 0x4f, 0x88, 0x89, 0x8a, 0x8b, 0x90, 0x91, 0x92, 0x93,
+			0x99, 0x9b, 0x9d, 0x9f, 0xa0, 0xa1, 0xa2, 0xa3, 0xa9, 0xab, 0xad, 0xaf,
+			0xb9, 0xbb, 0xbd, 0xbf, 0xc6, 0xc7, 0xcb, 0xcd:
+			return tupleT1S
+		case 0x62, 0x63:
+			return tupleT1SBW
+		case 0x58:
+			return tupleT1F32
+		case 0x19, 0x59:
+			return tupleT1F64
+		case 0x78:
+			return tupleT1S8
+		case 0x79:
+			return tupleT1S16
+		case 0x1a, 0x5a:
+			return tupleM128
+		case 0x1b, 0x5b:
+			return tupleT8
+		}
+		return tupleFV
+	case 3:
+		switch op {
+		case 0x0a, 0x0b, 0x16, 0x22, 0x27, 0x51, 0x55, 0x57, 0x67:
+			return tupleT1S
+		case 0x14, 0x20:
+			return tupleT1S8
+		case 0x15:
+			return tupleT1S16
+		case 0x17, 0x21:
+			return tupleT1F32
+		case 0x18, 0x19, 0x38, 0x39:
+			return tupleM128
+		case 0x1a, 0x1b, 0x3a, 0x3b:
+			return tupleT8
+		case 0x1d:
+			return tupleHVM
+		case 0x0f, 0x3e, 0x3f, 0x42, 0x44, 0x70, 0x72:
+			return tupleFVM
+		}
+		return tupleFV
+	}
+	return tupleNone
+}
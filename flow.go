@@ -0,0 +1,87 @@
+package hde
+
+import "fmt"
+
+// FlowKind classifies how an instruction transfers control
+type FlowKind uint8
+
+const (
+	Fallthrough  FlowKind = iota // Continues with the next instruction
+	CondBranch                   // Relative branch that may fall through: Jcc, JCXZ, LOOP, XBEGIN
+	Jump                         // Unconditional direct jump, near or far
+	IndirectJump                 // Jump through a register or memory operand (FF /4, FF /5)
+	Call                         // Direct call, near or far
+	IndirectCall                 // Call through a register or memory operand (FF /2, FF /3)
+	Return                       // Near and far returns, IRET, SYSRET and SYSEXIT
+	Interrupt                    // Software interrupt that resumes at the next instruction: INT n, INTO, INT1
+	Syscall                      // System call: SYSCALL, SYSENTER
+	Halt                         // HLT
+	Trap                         // Instruction that raises an exception instead of continuing: UD0, UD1, UD2, INT3
+)
+
+var flowKindNames = [...]string{
+	Fallthrough:  "Fallthrough",
+	CondBranch:   "CondBranch",
+	Jump:         "Jump",
+	IndirectJump: "IndirectJump",
+	Call:         "Call",
+	IndirectCall: "IndirectCall",
+	Return:       "Return",
+	Interrupt:    "Interrupt",
+	Syscall:      "Syscall",
+	Halt:         "Halt",
+	Trap:         "Trap",
+}
+
+// String returns the name of the flow kind
+func (k FlowKind) String() string {
+	if int(k) >= len(flowKindNames) {
+		return fmt.Sprintf("FlowKind(%d)", k)
+	}
+	return flowKindNames[k]
+}
+
+// IsBranch reports whether the flow kind transfers control to a computed or encoded
+// target: a conditional branch, a jump or a call
+func (k FlowKind) IsBranch() bool {
+	return k >= CondBranch && k <= IndirectCall
+}
+
+// Flow classifies the control transfer performed by the instruction. Unlike IsJCC,
+// IsJMP and IsCALL it resolves the ModR/M extension of FF, and covers far transfers,
+// LOOP and JCXZ, returns from interrupts and system calls. Instructions missing from
+// the opcode table are reported as Fallthrough.
+func (insn *Insn) Flow() FlowKind {
+	f := insn.form()
+	if f == nil {
+		return Fallthrough
+	}
+	switch f.mnem {
+	case JMP:
+		if f.hasMethod(amJ, amA) {
+			return Jump
+		}
+		return IndirectJump
+	case CALL:
+		if f.hasMethod(amJ, amA) {
+			return Call
+		}
+		return IndirectCall
+	case LOOP, LOOPE, LOOPNE, JCXZ, JECXZ, JRCXZ, XBEGIN:
+		return CondBranch
+	case RET, RETF, IRET, IRETD, IRETQ, SYSRET, SYSEXIT:
+		return Return
+	case INT, INTO, INT1:
+		return Interrupt
+	case SYSCALL, SYSENTER:
+		return Syscall
+	case HLT:
+		return Halt
+	case UD0, UD1, UD2, INT3:
+		return Trap
+	}
+	if insn.IsJCC() {
+		return CondBranch
+	}
+	return Fallthrough
+}
@@ -0,0 +1,144 @@
+package flow
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// edgeColors are the DOT colors of the edge kinds
+var edgeColors = [...]string{
+	Fallthrough: "blue",
+	Taken:       "darkgreen",
+	NotTaken:    "red",
+	Call:        "gray",
+}
+
+// dotEscape escapes a string for a quoted DOT label
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// hexAddr formats an address the way both exports spell them
+func hexAddr(addr uint64) string {
+	return fmt.Sprintf("%#x", addr)
+}
+
+// WriteDOT writes the graph in Graphviz DOT syntax. Blocks are boxes listing their
+// instructions in Intel syntax, and edges are labeled and colored by their kind.
+// Targets without a block, such as calls out of the code, are drawn as dashed
+// ellipses.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph cfg {")
+	fmt.Fprintln(bw, "\tnode [shape=box, fontname=\"monospace\"];")
+
+	blocks := g.Sorted()
+	external := map[uint64]bool{}
+	for _, b := range blocks {
+		var label strings.Builder
+		label.WriteString(hexAddr(b.Start) + `:\l`)
+		for _, inst := range b.Insts {
+			label.WriteString(dotEscape(hexAddr(inst.Addr)+"  "+inst.Insn.FormatIntel(inst.Addr)) + `\l`)
+		}
+		if b.Err != nil {
+			label.WriteString(dotEscape(b.Err.Error()) + `\l`)
+		}
+		fmt.Fprintf(bw, "\t\"%s\" [label=\"%s\"];\n", hexAddr(b.Start), label.String())
+		for _, e := range b.Succs {
+			if _, ok := g.Blocks[e.To]; !ok {
+				external[e.To] = true
+			}
+		}
+	}
+	for _, b := range blocks {
+		for _, e := range b.Succs {
+			if external[e.To] {
+				fmt.Fprintf(bw, "\t\"%s\" [shape=ellipse, style=dashed];\n", hexAddr(e.To))
+				delete(external, e.To)
+			}
+		}
+	}
+	for _, b := range blocks {
+		for _, e := range b.Succs {
+			fmt.Fprintf(bw, "\t\"%s\" -> \"%s\" [label=\"%s\", color=%s];\n",
+				hexAddr(e.From), hexAddr(e.To), e.Kind, edgeColors[e.Kind])
+		}
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// jsonInst is the JSON form of an instruction
+type jsonInst struct {
+	Addr  string `json:"addr"`
+	Bytes string `json:"bytes"`
+	Text  string `json:"text"`
+}
+
+// jsonEdge is the JSON form of an edge, naming the block at its other end
+type jsonEdge struct {
+	Block string `json:"block"`
+	Kind  string `json:"kind"`
+}
+
+// jsonBlock is the JSON form of a basic block
+type jsonBlock struct {
+	Start        string     `json:"start"`
+	End          string     `json:"end"`
+	Instructions []jsonInst `json:"instructions"`
+	Successors   []jsonEdge `json:"successors"`
+	Predecessors []jsonEdge `json:"predecessors"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// jsonGraph is the JSON form of a graph
+type jsonGraph struct {
+	Bits    int         `json:"bits"`
+	Entries []string    `json:"entries"`
+	Blocks  []jsonBlock `json:"blocks"`
+}
+
+// MarshalJSON implements json.Marshaler. The graph is written as an object holding
+// the address size in "bits", the "entries" and the "blocks" in address order. Each
+// block has its "start" and "end", its "instructions" with their "addr", "bytes" in
+// hex and "text" in Intel syntax, its "successors" and "predecessors" as the "block"
+// at the other end and the edge "kind", and an "error" if decoding stopped early.
+// Addresses are hexadecimal strings, so that 64-bit values survive JSON readers that
+// parse numbers as doubles.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	out := jsonGraph{Bits: g.Mode.Bits(), Entries: []string{}, Blocks: []jsonBlock{}}
+	for _, e := range g.Entries {
+		out.Entries = append(out.Entries, hexAddr(e))
+	}
+	for _, b := range g.Sorted() {
+		jb := jsonBlock{
+			Start:        hexAddr(b.Start),
+			End:          hexAddr(b.End),
+			Instructions: []jsonInst{},
+			Successors:   []jsonEdge{},
+			Predecessors: []jsonEdge{},
+		}
+		for _, inst := range b.Insts {
+			jb.Instructions = append(jb.Instructions, jsonInst{
+				Addr:  hexAddr(inst.Addr),
+				Bytes: hex.EncodeToString(inst.Bytes),
+				Text:  inst.Insn.FormatIntel(inst.Addr),
+			})
+		}
+		for _, e := range b.Succs {
+			jb.Successors = append(jb.Successors, jsonEdge{hexAddr(e.To), e.Kind.String()})
+		}
+		for _, e := range b.Preds {
+			jb.Predecessors = append(jb.Predecessors, jsonEdge{hexAddr(e.From), e.Kind.String()})
+		}
+		if b.Err != nil {
+			jb.Error = b.Err.Error()
+		}
+		out.Blocks = append(out.Blocks, jb)
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,243 @@
+// Package flow discovers the basic blocks of machine code by recursive descent,
+// following branches and calls from a set of entry points.
+package flow
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+
+	hde "github.com/can1357/go-hde"
+)
+
+// ErrOutside is recorded on blocks that run past the end of the code
+var ErrOutside = errors.New("flow: address outside of the code")
+
+// EdgeKind classifies a control flow edge between basic blocks
+type EdgeKind uint8
+
+const (
+	Fallthrough EdgeKind = iota // Execution continues into the next block: after a call, or into a split block
+	Taken                       // A conditional branch is taken, or an unconditional jump
+	NotTaken                    // A conditional branch falls through
+	Call                        // A call to a function entry
+)
+
+var edgeKindNames = [...]string{
+	Fallthrough: "fallthrough",
+	Taken:       "taken",
+	NotTaken:    "not-taken",
+	Call:        "call",
+}
+
+// String returns the name of the edge kind
+func (k EdgeKind) String() string {
+	if int(k) >= len(edgeKindNames) {
+		return fmt.Sprintf("EdgeKind(%d)", k)
+	}
+	return edgeKindNames[k]
+}
+
+// Edge is a control flow edge from the block starting at From to the block starting at To
+type Edge struct {
+	From, To uint64
+	Kind     EdgeKind
+}
+
+// Block is a basic block: a run of instructions entered at the first one and left
+// after the last one
+type Block struct {
+	Start uint64     // Address of the first instruction
+	End   uint64     // Address following the last instruction
+	Insts []hde.Inst // Instructions in address order
+	Succs []Edge     // Edges to the blocks control may continue in
+	Preds []Edge     // Edges from the blocks that may continue here
+	Err   error      // Error that ended the block early, for code that does not decode
+}
+
+// Graph holds the basic blocks reachable from a set of entry points
+type Graph struct {
+	Mode    *hde.Mode
+	Entries []uint64          // Entry points, which include the targets of direct calls
+	Blocks  map[uint64]*Block // Blocks by start address
+}
+
+// Sorted returns the blocks in order of their start address. Blocks that overlap,
+// where a branch lands inside an instruction of another block, are both included.
+func (g *Graph) Sorted() []*Block {
+	blocks := make([]*Block, 0, len(g.Blocks))
+	for _, b := range g.Blocks {
+		blocks = append(blocks, b)
+	}
+	slices.SortFunc(blocks, func(a, b *Block) int { return cmp.Compare(a.Start, b.Start) })
+	return blocks
+}
+
+// Segment is a run of code mapped at an address
+type Segment struct {
+	Addr uint64 // Address of the first byte
+	Data []byte // Code bytes
+}
+
+// builder holds the state of a disassembly
+type builder struct {
+	*Graph
+	segs  []Segment
+	insts map[uint64]*Block // Block holding the instruction that starts at an address
+	work  []uint64
+}
+
+// code returns the code from addr to the end of its segment, or nil if addr is not
+// inside a segment
+func (s *builder) code(addr uint64) []byte {
+	for _, seg := range s.segs {
+		if addr >= seg.Addr && addr-seg.Addr < uint64(len(seg.Data)) {
+			return seg.Data[addr-seg.Addr:]
+		}
+	}
+	return nil
+}
+
+// inCode reports whether addr is inside the code
+func (s *builder) inCode(addr uint64) bool {
+	return s.code(addr) != nil
+}
+
+// split splits the block holding the instruction at addr so that a block starts
+// there, and returns that block
+func (s *builder) split(addr uint64) *Block {
+	b := s.insts[addr]
+	if b.Start == addr {
+		return b
+	}
+	i := slices.IndexFunc(b.Insts, func(inst hde.Inst) bool { return inst.Addr == addr })
+	tail := &Block{Start: addr, End: b.End, Insts: b.Insts[i:], Succs: b.Succs, Err: b.Err}
+	b.Insts, b.End, b.Err = b.Insts[:i:i], addr, nil
+	b.Succs = []Edge{{To: addr, Kind: Fallthrough}}
+	for _, inst := range tail.Insts {
+		s.insts[inst.Addr] = tail
+	}
+	s.Blocks[addr] = tail
+	return tail
+}
+
+// edge adds an edge from the block to target and queues the target
+func (s *builder) edge(b *Block, target uint64, kind EdgeKind) {
+	b.Succs = append(b.Succs, Edge{To: target, Kind: kind})
+	s.work = append(s.work, target)
+}
+
+// explore decodes the block starting at addr, unless it was decoded already
+func (s *builder) explore(addr uint64) {
+	if _, ok := s.Blocks[addr]; ok {
+		return
+	}
+	if _, ok := s.insts[addr]; ok {
+		s.split(addr)
+		return
+	}
+	b := &Block{Start: addr, End: addr}
+	s.Blocks[addr] = b
+	for pc := addr; ; {
+		if _, ok := s.insts[pc]; ok && pc != addr {
+			// Ran into an instruction decoded before, which now starts a block
+			s.edge(b, pc, Fallthrough)
+			return
+		}
+		code := s.code(pc)
+		if code == nil {
+			b.Err = ErrOutside
+			return
+		}
+		insn, err := s.Mode.Decode(code)
+		if err != nil {
+			b.Err = fmt.Errorf("flow: decoding at %#x: %w", pc, err)
+			return
+		}
+		next := pc + uint64(insn.Len())
+		b.Insts = append(b.Insts, hde.Inst{Addr: pc, Bytes: code[:insn.Len():insn.Len()], Insn: insn})
+		b.End = next
+		s.insts[pc] = b
+		target, direct := insn.BranchTarget(pc)
+		pc = next
+
+		switch insn.Flow() {
+		case hde.CondBranch:
+			s.edge(b, target, Taken)
+			s.edge(b, next, NotTaken)
+			return
+		case hde.Jump:
+			if direct {
+				s.edge(b, target, Taken)
+			}
+			return
+		case hde.Call:
+			if direct {
+				if s.inCode(target) {
+					s.Entries = append(s.Entries, target)
+				}
+				s.edge(b, target, Call)
+			}
+			s.edge(b, next, Fallthrough)
+			return
+		case hde.IndirectCall:
+			s.edge(b, next, Fallthrough)
+			return
+		case hde.IndirectJump, hde.Return, hde.Halt, hde.Trap:
+			return
+		}
+	}
+}
+
+// Disassemble decodes the code located at base by recursive descent from the entry
+// points, following conditional branches, direct jumps and direct calls, and ending
+// blocks at branches, calls, returns and instructions that do not continue. A branch
+// to an instruction inside a block splits it; a branch into the middle of an
+// instruction starts a block that overlaps the other until their instructions line
+// up again. Targets outside the code keep their edges but have no block.
+func Disassemble(mode *hde.Mode, code []byte, base uint64, entries ...uint64) (*Graph, error) {
+	return DisassembleSegments(mode, []Segment{{base, code}}, entries...)
+}
+
+// DisassembleSegments is like Disassemble for code mapped in several segments, such
+// as the executable sections of an image. Branches and calls between segments are
+// followed; blocks that run past the end of the code end with ErrOutside.
+func DisassembleSegments(mode *hde.Mode, segs []Segment, entries ...uint64) (*Graph, error) {
+	s := &builder{
+		Graph: &Graph{Mode: mode, Entries: slices.Clone(entries), Blocks: map[uint64]*Block{}},
+		segs:  segs,
+		insts: map[uint64]*Block{},
+		work:  slices.Clone(entries),
+	}
+	for _, e := range entries {
+		if !s.inCode(e) {
+			return nil, fmt.Errorf("%w: entry point %#x", ErrOutside, e)
+		}
+	}
+	for len(s.work) != 0 {
+		addr := s.work[len(s.work)-1]
+		s.work = s.work[:len(s.work)-1]
+		if s.inCode(addr) {
+			s.explore(addr)
+		}
+	}
+
+	// Edges are only tied to their source blocks once all splits are done
+	for _, b := range s.Blocks {
+		for i := range b.Succs {
+			b.Succs[i].From = b.Start
+			if to, ok := s.Blocks[b.Succs[i].To]; ok {
+				to.Preds = append(to.Preds, b.Succs[i])
+			}
+		}
+	}
+	for _, b := range s.Blocks {
+		slices.SortFunc(b.Preds, func(x, y Edge) int {
+			return cmp.Or(cmp.Compare(x.From, y.From), cmp.Compare(x.Kind, y.Kind))
+		})
+	}
+	slices.Sort(s.Entries)
+	s.Entries = slices.Compact(s.Entries)
+	return s.Graph, nil
+}
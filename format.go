@@ -0,0 +1,355 @@
+package hde
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// intelPtrNames maps memory operand sizes in bits to their Intel size keyword
+var intelPtrNames = map[uint16]string{
+	8:   "byte",
+	16:  "word",
+	32:  "dword",
+	48:  "fword",
+	64:  "qword",
+	80:  "tbyte",
+	128: "xmmword",
+	256: "ymmword",
+	512: "zmmword",
+}
+
+// roundingNames are the EVEX static rounding modes selected by L'L
+var roundingNames = [4]string{"{rn-sae}", "{rd-sae}", "{ru-sae}", "{rz-sae}"}
+
+// saeOnly reports whether EVEX.b on a register form of the instruction suppresses
+// exceptions without selecting a rounding mode
+func saeOnly(m Mnemonic) bool {
+	switch m {
+	case VCMPPD, VCMPPS, VCMPSD, VCMPSS, VCOMISD, VCOMISS, VUCOMISD, VUCOMISS,
+		VMAXPD, VMAXPS, VMAXSD, VMAXSS, VMINPD, VMINPS, VMINSD, VMINSS,
+		VGETEXPPD, VGETEXPPS, VGETEXPSD, VGETEXPSS, VGETMANTPD, VGETMANTPS, VGETMANTSD, VGETMANTSS,
+		VRNDSCALEPD, VRNDSCALEPS, VRNDSCALESD, VRNDSCALESS, VREDUCEPD, VREDUCEPS, VREDUCESD, VREDUCESS,
+		VRANGEPD, VRANGEPS, VRANGESD, VRANGESS, VFIXUPIMMPD, VFIXUPIMMPS, VFIXUPIMMSD, VFIXUPIMMSS,
+		VCVTPH2PS, VCVTPS2PD, VCVTPS2PH, VCVTSS2SD, VEXP2PD, VEXP2PS,
+		VRCP28PD, VRCP28PS, VRCP28SD, VRCP28SS, VRSQRT28PD, VRSQRT28PS, VRSQRT28SD, VRSQRT28SS:
+		return true
+	}
+	return strings.HasPrefix(m.String(), "vcvtt")
+}
+
+// hasMethod reports whether the instruction form has an operand of one of the given methods
+func (f *opForm) hasMethod(ams ...addrMethod) bool {
+	for _, s := range f.ops {
+		for _, am := range ams {
+			if s.am == am {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isNullSegment reports whether the segment override is ignored by the processor,
+// as ES, CS, SS and DS are in 64-bit mode
+func (insn *Insn) isNullSegment(seg Segment) bool {
+	return insn.Mode.long && seg >= SegDS && seg <= SegES
+}
+
+// isIndirectBranch reports whether the instruction is an indirect near CALL or JMP
+func (insn *Insn) isIndirectBranch() bool {
+	return insn.Map() == MapPrimary && insn.Opcode == 0xff && (insn.ModRM.Reg() == 2 || insn.ModRM.Reg() == 4)
+}
+
+// textPrefixes returns the prefixes objdump prints ahead of the mnemonic, in either syntax
+func (insn *Insn) textPrefixes(f *opForm, segUsed bool) string {
+	var pfx []string
+	if seg := insn.Flags.Segment(); seg != SegNone && !segUsed {
+		if seg == SegDS && insn.isIndirectBranch() {
+			pfx = append(pfx, "notrack")
+		} else {
+			pfx = append(pfx, seg.Register().String())
+		}
+	}
+
+	// REP prefixes that are not mandatory prefixes of the form are printed by name
+	rep := insn.Flags&HasRep != 0 && f.mp != mpF3
+	repnz := insn.Flags&HasRepNZ != 0 && f.mp != mpF2
+	switch {
+	case insn.encoding() != encLegacy:
+	case insn.Flags&HasLock != 0:
+		if repnz {
+			pfx = append(pfx, "xacquire")
+		} else if rep {
+			pfx = append(pfx, "xrelease")
+		}
+		pfx = append(pfx, "lock")
+	case repnz && (f.hasMethod(amJ) || insn.IsRET() || insn.isIndirectBranch()):
+		pfx = append(pfx, "bnd")
+	case repnz:
+		pfx = append(pfx, "repnz")
+	case rep && f.hasMethod(amX, amY):
+		switch f.mnem {
+		case CMPSB, CMPSW, CMPSD, CMPSQ, SCASB, SCASW, SCASD, SCASQ:
+			pfx = append(pfx, "repz")
+		default:
+			pfx = append(pfx, "rep")
+		}
+	case rep && f.mnem == MOV && insn.Map() == MapPrimary && insn.ModRM.Mod() != 3 &&
+		(insn.Opcode&0xfe == 0x88 || insn.Opcode&0xfe == 0xc6):
+		pfx = append(pfx, "xrelease")
+	case rep:
+		pfx = append(pfx, "repz")
+	}
+	if len(pfx) == 0 {
+		return ""
+	}
+	return strings.Join(pfx, " ") + " "
+}
+
+// intelMnemonic returns the mnemonic spelled the way objdump prints it
+func (insn *Insn) intelMnemonic(f *opForm, ops []Operand) string {
+	name := f.mnem.String()
+	switch {
+	case f.hasMethod(amX, amY):
+		// String instructions carry the size in their operands
+		name = name[:len(name)-1]
+	case f.mnem == MOV && insn.Mode.long:
+		for i, op := range ops {
+			if f.ops[i].am == amO || op.Type == OpImm && insn.Imm.Bits == 64 {
+				return "movabs"
+			}
+		}
+	}
+	return name
+}
+
+// Comparison predicates objdump folds into the mnemonics of CMPPS, VCMPPS and VPCMP,
+// indexed by the immediate
+var (
+	ssePredicates = [8]string{"eq", "lt", "le", "unord", "neq", "nlt", "nle", "ord"}
+	avxPredicates = [32]string{
+		"eq", "lt", "le", "unord", "neq", "nlt", "nle", "ord",
+		"eq_uq", "nge", "ngt", "false", "neq_oq", "ge", "gt", "true",
+		"eq_os", "lt_oq", "le_oq", "unord_s", "neq_us", "nlt_uq", "nle_uq", "ord_s",
+		"eq_us", "nge_uq", "ngt_uq", "false_os", "neq_os", "ge_oq", "gt_oq", "true_us",
+	}
+	// The always false and always true predicates of VPCMP keep their immediate
+	intPredicates = [8]string{"eq", "lt", "le", "", "neq", "nlt", "nle", ""}
+)
+
+// predicateMnemonic returns the mnemonic with the comparison predicate of the
+// immediate folded in, as in cmpltps and vpcmpequb, or "" if objdump prints the
+// immediate instead
+func (insn *Insn) predicateMnemonic(f *opForm, name string) string {
+	var preds []string
+	switch {
+	case insn.Map() == Map0F && insn.OpcodeByte() == 0xc2 && insn.encoding() == encLegacy:
+		preds = ssePredicates[:]
+	case insn.Map() == Map0F && insn.OpcodeByte() == 0xc2:
+		preds = avxPredicates[:]
+	default:
+		switch f.mnem {
+		case VPCMPB, VPCMPW, VPCMPD, VPCMPQ, VPCMPUB, VPCMPUW, VPCMPUD, VPCMPUQ:
+			preds = intPredicates[:]
+		}
+	}
+	i := strings.Index(name, "cmp")
+	if preds == nil || i < 0 || insn.Imm.Value >= uint64(len(preds)) || preds[insn.Imm.Value] == "" {
+		return ""
+	}
+	i += len("cmp")
+	return name[:i] + preds[insn.Imm.Value] + name[i:]
+}
+
+// hexImm formats an unsigned value in hexadecimal
+func hexImm(v uint64) string {
+	return "0x" + strconv.FormatUint(v, 16)
+}
+
+// truncate masks v to the given number of bits
+func truncate(v uint64, bits int) uint64 {
+	if bits <= 0 || bits >= 64 {
+		return v
+	}
+	return v & (1<<bits - 1)
+}
+
+// hasSIB reports whether the instruction has a SIB byte
+func (insn *Insn) hasSIB() bool {
+	return insn.Flags&IsSIB != 0
+}
+
+// memIndex returns the name of the index register of a memory operand and its scale.
+// Like objdump, it spells out a SIB byte without an index register as EIZ or RIZ
+// where it differs from the shorter encoding.
+func (insn *Insn) memIndex(m Mem) (string, uint8) {
+	if m.Index != RegNone || insn.Flags&IsSIB == 0 {
+		return m.Index.String(), m.Scale
+	}
+	if insn.SIB.Scale() != 0 || m.Base == RegNone && !insn.Mode.long ||
+		m.Base != RegNone && insn.SIB.Base() != 4 {
+		if insn.AddressSize() == 64 {
+			return "riz", 1 << insn.SIB.Scale()
+		}
+		return "eiz", 1 << insn.SIB.Scale()
+	}
+	return "", 0
+}
+
+// intelMem formats a memory reference, reporting whether it printed the segment override
+func (insn *Insn) intelMem(b *strings.Builder, f *opForm, s opSpec, op Operand) bool {
+	m := op.Mem
+	if op.Size != 0 && s.size != szS && s.size != szE && s.size != szST && s.am != amO {
+		if name, ok := intelPtrNames[op.Size]; ok {
+			if f.mnem == CMPXCHG16B {
+				name = "oword"
+			}
+			b.WriteString(name)
+			if m.Broadcast {
+				b.WriteString(" bcst ")
+			} else {
+				b.WriteString(" ptr ")
+			}
+		}
+	}
+
+	index, scale := insn.memIndex(m)
+
+	// Absolute addresses always name the segment, string operands name their default
+	override := insn.Flags.Segment()
+	absolute := m.Base == RegNone && scale == 0
+	segUsed := false
+	switch {
+	case absolute, s.am == amX, s.am == amY, s.am == amXlat:
+		b.WriteString(m.Segment.Register().String())
+		b.WriteByte(':')
+		segUsed = override != SegNone && s.am != amY
+	case override != SegNone && !insn.isNullSegment(override):
+		b.WriteString(m.Segment.Register().String())
+		b.WriteByte(':')
+		segUsed = true
+	}
+	if absolute && m.Base == RegNone {
+		b.WriteString(hexImm(truncate(uint64(m.Disp), insn.AddressSize())))
+		return segUsed
+	}
+
+	b.WriteByte('[')
+	if m.Base != RegNone {
+		b.WriteString(m.Base.String())
+	}
+	if scale != 0 {
+		if m.Base != RegNone {
+			b.WriteByte('+')
+		}
+		b.WriteString(index)
+		if insn.AddressSize() != 16 {
+			b.WriteByte('*')
+			b.WriteString(strconv.Itoa(int(scale)))
+		}
+	}
+	if insn.Flags&(HasDisp8|HasDisp16|HasDisp32) != 0 || m.Base == RegNone {
+		// objdump prints RIP-relative displacements unsigned
+		if m.Disp < 0 && m.Base.Class() != ClassIP {
+			b.WriteString("-" + hexImm(uint64(-m.Disp)))
+		} else {
+			b.WriteString("+" + hexImm(truncate(uint64(m.Disp), insn.AddressSize())))
+		}
+	}
+	b.WriteByte(']')
+	return segUsed
+}
+
+// FormatIntel returns the instruction in Intel syntax, as printed by objdump -M intel
+// in lower case with operands separated by ", ". Branch targets are resolved against
+// addr, the address of the instruction. Instructions missing from the opcode table
+// are printed as "(bad)". Comparison predicates are folded into the mnemonic as
+// objdump does (cmpltps, vpcmpeqb), but prefixes objdump calls redundant are not
+// printed, such as the data16 of 66 8C with a memory operand.
+func (insn *Insn) FormatIntel(addr uint64) string {
+	f := insn.form()
+	if f == nil {
+		return "(bad)"
+	}
+	ops := insn.Operands()
+	evex := insn.encoding() == encEVEX
+	name := insn.intelMnemonic(f, ops)
+	if alias := insn.predicateMnemonic(f, name); alias != "" {
+		// The predicate replaces the immediate operand
+		name, ops = alias, ops[:len(ops)-1]
+	}
+
+	var b strings.Builder
+	segUsed := false
+	for i, op := range ops {
+		if i != 0 {
+			b.WriteString(", ")
+		}
+		s := f.ops[i]
+		switch op.Type {
+		case OpReg:
+			if s.am == amFixed && op.Reg == ST0 {
+				b.WriteString("st")
+			} else {
+				b.WriteString(op.Reg.String())
+			}
+		case OpMem:
+			if insn.intelMem(&b, f, s, op) {
+				segUsed = true
+			}
+		case OpImm:
+			if s.am == amOne {
+				b.WriteString("1")
+			} else {
+				b.WriteString(hexImm(truncate(uint64(op.Imm), int(op.Size))))
+			}
+		case OpRel:
+			// The instruction pointer wraps around within the operand size
+			target := addr + uint64(insn.Len()) + uint64(op.Imm)
+			target = addr - truncate(addr, int(op.Size)) + truncate(target, int(op.Size))
+			b.WriteString(hexImm(target))
+		case OpFar:
+			b.WriteString(hexImm(uint64(op.Selector)) + ":" + hexImm(uint64(op.Imm)))
+		}
+
+		if !evex {
+			continue
+		}
+		if i == 0 {
+			if mask := insn.Mask(); mask != RegNone {
+				b.WriteString("{" + mask.String() + "}")
+			}
+			if insn.EVEX.Z() != 0 {
+				b.WriteString("{z}")
+			}
+		}
+		// Rounding control follows the last register operand
+		if insn.EVEX.Bcst() != 0 && insn.ModRM.Mod() == 3 && op.Type == OpReg &&
+			(i+1 == len(ops) || ops[i+1].Type == OpImm) {
+			if saeOnly(f.mnem) {
+				b.WriteString("{sae}")
+			} else {
+				b.WriteString(roundingNames[insn.EVEX.LL()])
+			}
+		}
+	}
+
+	text := insn.textPrefixes(f, segUsed) + name
+	if b.Len() != 0 {
+		text += " " + b.String()
+	}
+	return text
+}
+
+// Format implements fmt.Formatter, printing the instruction in Intel syntax for the
+// %v and %s verbs. Branch targets are resolved against address 0; use FormatIntel
+// to resolve them against the address of the instruction.
+func (insn Insn) Format(f fmt.State, verb rune) {
+	switch verb {
+	case 'v', 's':
+		fmt.Fprint(f, insn.FormatIntel(0))
+	default:
+		fmt.Fprintf(f, "%%!%c(hde.Insn=%s)", verb, insn.FormatIntel(0))
+	}
+}
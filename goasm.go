@@ -0,0 +1,296 @@
+package hde
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symbolizer resolves an address to the name and start address of the symbol
+// containing it, or returns an empty name if the address is not in a symbol. It has
+// the signature of the lookup function go tool objdump passes to its disassembler.
+type Symbolizer func(addr uint64) (name string, base uint64)
+
+// goGPRNames are the Go assembler names of the general purpose registers by number,
+// which do not depend on the register size
+var goGPRNames = [16]string{
+	"AX", "CX", "DX", "BX", "SP", "BP", "SI", "DI",
+	"R8", "R9", "R10", "R11", "R12", "R13", "R14", "R15",
+}
+
+// goSuffixed are the mnemonics the Go assembler suffixes with the operand size
+var goSuffixed = map[Mnemonic]bool{
+	ADC: true, ADD: true, AND: true, BSF: true, BSR: true, BT: true, BTC: true, BTR: true,
+	BTS: true, CMP: true, CMPXCHG: true, CVTSI2SD: true, CVTSI2SS: true, CVTSD2SI: true,
+	CVTSS2SI: true, CVTTSD2SI: true, CVTTSS2SI: true, DEC: true, DIV: true, FLDENV: true,
+	FRSTOR: true, IDIV: true, IMUL: true, IN: true, INC: true, LEA: true, MOV: true,
+	MOVNTI: true, MUL: true, NEG: true, NOP: true, NOT: true, OR: true, OUT: true, POP: true,
+	POPA: true, POPCNT: true, PUSH: true, PUSHA: true, RCL: true, RCR: true, ROL: true,
+	ROR: true, SAR: true, SBB: true, SHL: true, SHLD: true, SHR: true, SHRD: true, SUB: true,
+	TEST: true, XADD: true, XCHG: true, XOR: true,
+}
+
+// goNames maps mnemonics whose Go spelling differs from the Intel one
+var goNames = map[Mnemonic]string{
+	INT1: "ICEBP",
+	RETF: "LRET",
+	XLAT: "XLATB",
+}
+
+// goRounding are the Go assembler suffixes of the EVEX static rounding modes
+var goRounding = [4]string{".RN_SAE", ".RD_SAE", ".RU_SAE", ".RZ_SAE"}
+
+// goReg returns the Go assembler name of a register
+func goReg(r Register) string {
+	switch r.Class() {
+	case ClassGPR:
+		if r >= AL && r <= BL || r >= AH && r <= BH {
+			return strings.ToUpper(r.String())
+		}
+		return goGPRNames[r.Num()]
+	case ClassIP:
+		return "IP"
+	case ClassX87:
+		return fmt.Sprintf("F%d", r.Num())
+	case ClassMMX:
+		return fmt.Sprintf("M%d", r.Num())
+	case ClassXMM:
+		return fmt.Sprintf("X%d", r.Num())
+	case ClassYMM:
+		return fmt.Sprintf("Y%d", r.Num())
+	case ClassZMM:
+		return fmt.Sprintf("Z%d", r.Num())
+	}
+	return strings.ToUpper(r.String())
+}
+
+// goSymbol formats addr as a symbol reference, if sym places it inside a symbol.
+// Unless inner is set, only the start of a symbol is named.
+func goSymbol(sym Symbolizer, addr uint64, inner bool) (string, bool) {
+	if sym == nil {
+		return "", false
+	}
+	name, base := sym(addr)
+	if name == "" || !inner && addr != base {
+		return "", false
+	}
+	if addr != base {
+		name += fmt.Sprintf("%+d", int64(addr-base))
+	}
+	return name + "(SB)", true
+}
+
+// goSegment returns the segment override the Go disassembler applies to memory
+// operands. In 64-bit mode it only honours FS and GS.
+func (insn *Insn) goSegment() Segment {
+	seg := insn.Flags.Segment()
+	if insn.isNullSegment(seg) {
+		return SegNone
+	}
+	return seg
+}
+
+// goMem formats a memory reference in Go assembler syntax
+func (insn *Insn) goMem(addr uint64, sym Symbolizer, s opSpec, op Operand) string {
+	m := op.Mem
+	seg := insn.goSegment()
+	if seg == SegNone && m.Disp != 0 && !insn.hasSIB() {
+		switch {
+		case m.Base == RegNone:
+			if s, ok := goSymbol(sym, truncate(uint64(m.Disp), insn.AddressSize()), true); ok {
+				return s
+			}
+		case m.Base.Class() == ClassIP:
+			target, _ := insn.MemTarget(addr)
+			if s, ok := goSymbol(sym, target, true); ok {
+				return s
+			}
+		}
+	}
+
+	var b strings.Builder
+	if seg != SegNone || s.am == amX || s.am == amY || s.am == amXlat {
+		b.WriteString(goReg(m.Segment.Register()) + ":")
+	}
+	switch {
+	case m.Disp == 0:
+		b.WriteByte('0')
+	case insn.Flags&HasDisp32 != 0 && insn.encoding() == encLegacy:
+		// The Go disassembler reads legacy 16 and 32-bit displacements unsigned
+		fmt.Fprintf(&b, "%#x", uint32(m.Disp))
+	case insn.Flags&HasDisp16 != 0:
+		fmt.Fprintf(&b, "%#x", uint16(m.Disp))
+	default:
+		fmt.Fprintf(&b, "%#x", m.Disp)
+	}
+	if m.Base != RegNone {
+		b.WriteString("(" + goReg(m.Base) + ")")
+	}
+	if m.Index != RegNone {
+		fmt.Fprintf(&b, "(%s*%d)", goReg(m.Index), m.Scale)
+	}
+	return b.String()
+}
+
+// isXchgNop reports whether the instruction is 66 90, which the Go disassembler
+// reads as a NOP rather than an exchange of AX with itself
+func (insn *Insn) isXchgNop(f *opForm) bool {
+	return f.mnem == XCHG && insn.Map() == MapPrimary && insn.Opcode == 0x90 && insn.REX.B() == 0
+}
+
+// goMnemonic returns the mnemonic of the instruction in Go assembler syntax
+func (insn *Insn) goMnemonic(f *opForm, ops []Operand) string {
+	name := strings.ToUpper(f.mnem.String())
+	if name, ok := goNames[f.mnem]; ok {
+		return name
+	}
+	switch {
+	case f.mnem == INT3:
+		return "INT"
+	case (f.mnem == CALL || f.mnem == JMP) && (f.ops[0].am == amA || f.ops[0].size == szP):
+		return "L" + name
+	case (f.mnem == MOVSD || f.mnem == CMPSD) && !f.hasMethod(amX, amY):
+		// The scalar double SSE forms are told apart from the string instructions
+		return name + "_XMM"
+	case insn.isXchgNop(f):
+		name = "NOP"
+	case !goSuffixed[f.mnem]:
+		return name
+	}
+
+	// The Go disassembler sizes by the memory operand, and otherwise by the operand
+	// size without the 64-bit default of stack operations
+	size := insn.operandSize(f.flags &^ ffD64)
+	for _, op := range ops {
+		if op.Type == OpMem && op.Size != 0 && op.Size <= 64 {
+			size = int(op.Size)
+			break
+		}
+	}
+	if len(ops) == 1 && ops[0].Type == OpReg && ops[0].Reg.Class() == ClassGPR && ops[0].Reg.Size() == 64 {
+		size = 64
+	}
+	switch size {
+	case 8:
+		name += "B"
+	case 16:
+		name += "W"
+	case 32:
+		name += "L"
+	case 64:
+		name += "Q"
+	}
+	return name
+}
+
+// FormatGo returns the instruction in Go assembler (Plan 9) syntax, as printed by go
+// tool objdump. Branch targets and RIP-relative operands are resolved against addr,
+// the address of the instruction, and named through sym, which may be nil.
+func (insn *Insn) FormatGo(addr uint64, sym Symbolizer) string {
+	f := insn.form()
+	if f == nil {
+		return "?"
+	}
+	ops := insn.Operands()
+	if insn.isXchgNop(f) {
+		ops = nil
+	}
+
+	args := make([]string, 0, len(ops)+1)
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		switch op.Type {
+		case OpReg:
+			args = append(args, goReg(op.Reg))
+		case OpMem:
+			args = append(args, insn.goMem(addr, sym, f.ops[i], op))
+		case OpImm:
+			// Operand-sized immediates are signed, byte and word immediates are not.
+			// 32-bit code prints all of them unsigned.
+			imm := op.Imm
+			if s := f.ops[i].size; s != szB && s != szW && op.Size < 64 {
+				imm = int64(truncate(uint64(imm), int(op.Size))<<(64-op.Size)) >> (64 - op.Size)
+			}
+			switch name, ok := goSymbol(sym, uint64(imm), true); {
+			case ok && (f.mnem == MOV || f.mnem == PUSH) && insn.operandSize(f.flags&^ffD64) == 32:
+				// Only 32-bit moves and pushes are taken to load addresses
+				args = append(args, "$"+name)
+			case !insn.Mode.long && !insn.Mode.short:
+				args = append(args, fmt.Sprintf("$%#x", uint32(imm)))
+			case int64(int32(imm)) == imm:
+				args = append(args, fmt.Sprintf("$%#x", imm))
+			default:
+				args = append(args, fmt.Sprintf("$%#x", uint64(imm)))
+			}
+		case OpRel:
+			target := addr + uint64(insn.Len()) + uint64(op.Imm)
+			if s, ok := goSymbol(sym, target, false); ok {
+				args = append(args, s)
+			} else {
+				args = append(args, fmt.Sprintf("%#x", target))
+			}
+		case OpFar:
+			args = append(args, fmt.Sprintf("$%#x", op.Imm), fmt.Sprintf("$%#x", op.Selector))
+		}
+	}
+	if f.mnem == INT3 {
+		args = append(args, "$0x3")
+	}
+	hasMem := false
+	for _, op := range ops {
+		hasMem = hasMem || op.Type == OpMem
+	}
+	evex := insn.encoding() == encEVEX
+	if mask := insn.Mask(); evex && mask != RegNone {
+		// The opmask follows the destination
+		args = append(args[:len(args)-1], goReg(mask), args[len(args)-1])
+	}
+	if f.mnem == CMP {
+		// Comparisons read left to right
+		args[0], args[1] = args[1], args[0]
+	}
+
+	// Repeat prefixes are written as separate instructions; of the others, only the
+	// lock and segment prefixes that have no effect on an operand are shown
+	var pfx string
+	switch {
+	case insn.Flags&HasRep != 0 && f.mp != mpF3:
+		pfx = "REP; "
+	case insn.Flags&HasRepNZ != 0 && f.mp != mpF2:
+		pfx = "REPNE; "
+	}
+	cond := f.hasMethod(amJ) && f.mnem != JMP && f.mnem != CALL
+	if seg := insn.Flags.Segment(); cond && (seg == SegCS || seg == SegDS) {
+		// Segment prefixes on conditional branches are branch hints
+		if seg == SegCS {
+			pfx += "PN "
+		} else {
+			pfx += "PT "
+		}
+	} else if seg != SegNone && (insn.goSegment() == SegNone || !hasMem) {
+		pfx += goReg(seg.Register()) + " "
+	} else if insn.Flags&HasLock != 0 {
+		pfx += "LOCK "
+	}
+	text := pfx + insn.goMnemonic(f, ops)
+	if evex {
+		for _, op := range ops {
+			if op.Type == OpMem && op.Mem.Broadcast {
+				text += ".BCST"
+			}
+		}
+		if insn.EVEX.Bcst() != 0 && insn.ModRM.Mod() == 3 {
+			if saeOnly(f.mnem) {
+				text += ".SAE"
+			} else {
+				text += goRounding[insn.EVEX.LL()]
+			}
+		}
+		if insn.EVEX.Z() != 0 {
+			text += ".Z"
+		}
+	}
+	if len(args) != 0 {
+		text += " " + strings.Join(args, ", ")
+	}
+	return text
+}
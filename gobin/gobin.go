@@ -0,0 +1,302 @@
+// Package gobin decodes Go binaries function by function, naming instructions by
+// the function and source line the pclntab of the binary maps them to. It reads
+// ELF, PE and Mach-O binaries, including ones stripped of their symbol tables.
+package gobin
+
+import (
+	"debug/elf"
+	"debug/gosym"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	hde "github.com/can1357/go-hde"
+	hdeelf "github.com/can1357/go-hde/elf"
+	hdemacho "github.com/can1357/go-hde/macho"
+	hdepe "github.com/can1357/go-hde/pe"
+)
+
+var (
+	// ErrFormat is returned for files that are not ELF, PE or Mach-O binaries
+	ErrFormat = errors.New("gobin: unknown binary format")
+	// ErrNoPCLN is returned for binaries without a Go pclntab
+	ErrNoPCLN = errors.New("gobin: no Go pclntab")
+)
+
+// pclnMagics are the magic numbers of the pclntab headers of Go 1.2, 1.16, 1.18 and 1.20
+var pclnMagics = []uint32{0xfffffffb, 0xfffffffa, 0xfffffff0, 0xfffffff1}
+
+// image is the part of the image types of the elf, pe and macho packages that
+// decodes code
+type image interface {
+	Decode(addr uint64) (hde.Insn, error)
+	Instructions(addr uint64) iter.Seq2[hde.Inst, error]
+	Close() error
+}
+
+// Inst is an instruction of a Go binary with the function and the source line it
+// was compiled from
+type Inst struct {
+	hde.Inst
+	Func *gosym.Func // Function holding the instruction
+	File string      // Source file, as recorded by the compiler
+	Line int         // Source line, or 0 for padding the pclntab has no line for
+}
+
+// Location returns the function, offset and source line of the instruction, in the
+// form main.handler+0x34 (handler.go:88)
+func (inst *Inst) Location() string {
+	return location(inst.Addr, inst.Func, inst.File, inst.Line)
+}
+
+// location formats a PC with its function and source line
+func location(pc uint64, fn *gosym.Func, file string, line int) string {
+	if fn == nil {
+		return fmt.Sprintf("%#x", pc)
+	}
+	s := fn.Name
+	if pc != fn.Entry {
+		s += fmt.Sprintf("+%#x", pc-fn.Entry)
+	}
+	if file != "" {
+		// Windows builds may record paths with backslashes
+		s += fmt.Sprintf(" (%s:%d)", path.Base(strings.ReplaceAll(file, `\`, "/")), line)
+	}
+	return s
+}
+
+// Binary is a Go binary with its pclntab
+type Binary struct {
+	Mode  *hde.Mode
+	Table *gosym.Table // Functions and source lines from the pclntab
+	img   image
+}
+
+// Open opens the named Go binary
+func Open(name string) (*Binary, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	b, err := New(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	b.img = closer{b.img, f}
+	return b, nil
+}
+
+// closer closes the file opened by Open along with the image
+type closer struct {
+	image
+	f io.Closer
+}
+
+func (c closer) Close() error {
+	c.image.Close()
+	return c.f.Close()
+}
+
+// New reads a Go binary from r, telling its format from its magic number
+func New(r io.ReaderAt) (*Binary, error) {
+	var magic [4]byte
+	if _, err := r.ReadAt(magic[:], 0); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFormat, err)
+	}
+	var (
+		b        = &Binary{}
+		sections []section
+		text     uint64
+		pcln     []byte
+		err      error
+	)
+	switch le := binary.LittleEndian.Uint32(magic[:]); {
+	case string(magic[:]) == elf.ELFMAG:
+		var img *hdeelf.Image
+		if img, err = hdeelf.NewImage(r); err != nil {
+			return nil, err
+		}
+		b.img, b.Mode = img, img.Mode
+		for _, s := range img.File.Sections {
+			sections = append(sections, section{s.Name, s.Addr, s})
+		}
+	case magic[0] == 'M' && magic[1] == 'Z':
+		var img *hdepe.Image
+		if img, err = hdepe.NewImage(r); err != nil {
+			return nil, err
+		}
+		b.img, b.Mode = img, img.Mode
+		for _, s := range img.File.Sections {
+			sections = append(sections, section{s.Name, img.ImageBase + uint64(s.VirtualAddress), s})
+		}
+		if pcln, err = pePCLN(img); err != nil {
+			img.Close()
+			return nil, err
+		}
+	case le == macho.Magic32 || le == macho.Magic64 || binary.BigEndian.Uint32(magic[:]) == macho.MagicFat:
+		var img *hdemacho.Image
+		if img, err = hdemacho.NewImage(r); err != nil {
+			return nil, err
+		}
+		b.img, b.Mode = img, img.Mode
+		for _, s := range img.File.Sections {
+			sections = append(sections, section{s.Name, s.Addr, s})
+		}
+	default:
+		return nil, fmt.Errorf("%w: magic %x", ErrFormat, magic)
+	}
+
+	for _, s := range sections {
+		switch s.name {
+		case ".text", "__text":
+			if text == 0 {
+				text = s.addr
+			}
+		case ".gopclntab", "__gopclntab":
+			if pcln, err = readSection(s); err != nil {
+				b.img.Close()
+				return nil, err
+			}
+		}
+	}
+	if pcln == nil {
+		// Stripped PE binaries and some external linkers leave the pclntab in a
+		// read-only data section without a symbol
+		if b.Table = scan(sections, text); b.Table == nil {
+			b.img.Close()
+			return nil, ErrNoPCLN
+		}
+		return b, nil
+	}
+	if b.Table, err = gosym.NewTable(nil, gosym.NewLineTable(pcln, text)); err != nil {
+		b.img.Close()
+		return nil, fmt.Errorf("gobin: reading the pclntab: %w", err)
+	}
+	return b, nil
+}
+
+// section is a section of any of the formats
+type section struct {
+	name string
+	addr uint64
+	r    interface{ Data() ([]byte, error) }
+}
+
+// readSection returns the contents of the section
+func readSection(s section) ([]byte, error) {
+	data, err := s.r.Data()
+	if err != nil {
+		return nil, fmt.Errorf("gobin: reading section %s: %w", s.name, err)
+	}
+	return data, nil
+}
+
+// pePCLN returns the pclntab of a PE binary from the runtime.pclntab and
+// runtime.epclntab symbols, or nil if the binary is stripped of them
+func pePCLN(img *hdepe.Image) ([]byte, error) {
+	var start, end *pe.Symbol
+	for _, sym := range img.File.Symbols {
+		switch sym.Name {
+		case "runtime.pclntab":
+			start = sym
+		case "runtime.epclntab":
+			end = sym
+		}
+	}
+	if start == nil || end == nil || start.SectionNumber != end.SectionNumber || start.SectionNumber < 1 ||
+		int(start.SectionNumber) > len(img.File.Sections) || start.Value > end.Value {
+		return nil, nil
+	}
+	data, err := img.File.Sections[start.SectionNumber-1].Data()
+	if err != nil {
+		return nil, fmt.Errorf("gobin: reading the pclntab section: %w", err)
+	}
+	if int(end.Value) > len(data) {
+		return nil, fmt.Errorf("%w: runtime.epclntab is past the end of its section", ErrNoPCLN)
+	}
+	return data[start.Value:end.Value], nil
+}
+
+// scan looks for a pclntab header in the sections, and returns the table of the
+// first one that parses into functions
+func scan(sections []section, text uint64) *gosym.Table {
+	for _, s := range sections {
+		data, err := s.r.Data()
+		if err != nil {
+			continue
+		}
+		for off := 0; off+8 <= len(data); off += 4 {
+			// The magic number, two zero bytes, the instruction size quantum and
+			// the pointer size
+			if !slices.Contains(pclnMagics, binary.LittleEndian.Uint32(data[off:])) ||
+				data[off+4] != 0 || data[off+5] != 0 || data[off+6] != 1 || data[off+7] != 4 && data[off+7] != 8 {
+				continue
+			}
+			tab, err := gosym.NewTable(nil, gosym.NewLineTable(data[off:], text))
+			if err == nil && len(tab.Funcs) != 0 {
+				return tab
+			}
+		}
+	}
+	return nil
+}
+
+// Close closes the file opened by Open
+func (b *Binary) Close() error {
+	return b.img.Close()
+}
+
+// Func returns the function holding the PC, or nil
+func (b *Binary) Func(pc uint64) *gosym.Func {
+	return b.Table.PCToFunc(pc)
+}
+
+// Location returns the function, offset and source line of the PC, in the form
+// main.handler+0x34 (handler.go:88), or the PC in hex if no function holds it
+func (b *Binary) Location(pc uint64) string {
+	file, line, fn := b.Table.PCToLine(pc)
+	return location(pc, fn, file, line)
+}
+
+// Symbolize returns the name and entry of the function holding the address. It is
+// a Symbolizer for Insn.FormatGo.
+func (b *Binary) Symbolize(addr uint64) (string, uint64) {
+	if fn := b.Table.PCToFunc(addr); fn != nil {
+		return fn.Name, fn.Entry
+	}
+	return "", 0
+}
+
+// Decode decodes the instruction at the PC
+func (b *Binary) Decode(pc uint64) (hde.Insn, error) {
+	return b.img.Decode(pc)
+}
+
+// Instructions returns an iterator over the instructions of the function, with the
+// source line of each. Decoding errors are yielded as Mode.Instructions does.
+func (b *Binary) Instructions(fn *gosym.Func) iter.Seq2[Inst, error] {
+	return func(yield func(Inst, error) bool) {
+		for inst, err := range b.img.Instructions(fn.Entry) {
+			if inst.Addr >= fn.End {
+				return
+			}
+			file, line, _ := b.Table.PCToLine(inst.Addr)
+			if file == "" {
+				// Padding between functions
+				line = 0
+			}
+			if !yield(Inst{inst, fn, file, line}, err) {
+				return
+			}
+		}
+	}
+}
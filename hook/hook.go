@@ -0,0 +1,193 @@
+// Package hook builds inline hooks: trampolines holding the relocated prologue of a
+// function, and the jumps that divert the function to a detour.
+package hook
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	hde "github.com/can1357/go-hde"
+)
+
+var (
+	// ErrTooShort is returned when the function ends or the code runs out before enough bytes are covered
+	ErrTooShort = errors.New("hook: function too short")
+	// ErrUnrelocatable is returned when a prologue instruction cannot be moved to the trampoline
+	ErrUnrelocatable = errors.New("hook: unrelocatable instruction")
+	// ErrMode is returned for 16-bit code, whose near branches cannot reach a trampoline
+	ErrMode = errors.New("hook: unsupported mode")
+)
+
+// Trampoline is the relocated prologue of a hooked function. Calling it runs the
+// instructions the hook overwrote and continues in the original function.
+type Trampoline struct {
+	Addr   uint64 // Address the trampoline is built to run at
+	Code   []byte // Relocated prologue followed by a jump back to the function
+	Stolen int    // Number of bytes of the function the prologue covers
+}
+
+// rel32 returns the rel32 displacement from the end of an instruction at next to
+// target, reporting whether it is in range. 32-bit code wraps around and reaches
+// every address.
+func rel32(mode *hde.Mode, next, target uint64) (int32, bool) {
+	d := int64(target - next)
+	if !mode.IsLong() {
+		return int32(d), true
+	}
+	return int32(d), d == int64(int32(d))
+}
+
+// appendRel32 appends an instruction ending in a rel32 to target, or reports that
+// target is out of range
+func appendRel32(b []byte, mode *hde.Mode, pc, target uint64, op byte) ([]byte, bool) {
+	d, ok := rel32(mode, pc+5, target)
+	if !ok {
+		return b, false
+	}
+	b = append(b, op)
+	return binary.LittleEndian.AppendUint32(b, uint32(d)), true
+}
+
+// appendJump appends a jump from pc to target: a rel32 JMP when it reaches, and an
+// absolute JMP through an inline pointer otherwise
+func appendJump(b []byte, mode *hde.Mode, pc, target uint64) []byte {
+	if b, ok := appendRel32(b, mode, pc, target, 0xe9); ok {
+		return b
+	}
+	b = append(b, 0xff, 0x25, 0, 0, 0, 0)
+	return binary.LittleEndian.AppendUint64(b, target)
+}
+
+// appendCall appends a call from pc to target: a rel32 CALL when it reaches, and an
+// absolute CALL through an inline pointer that is jumped over otherwise
+func appendCall(b []byte, mode *hde.Mode, pc, target uint64) []byte {
+	if b, ok := appendRel32(b, mode, pc, target, 0xe8); ok {
+		return b
+	}
+	b = append(b, 0xff, 0x15, 2, 0, 0, 0, 0xeb, 8)
+	return binary.LittleEndian.AppendUint64(b, target)
+}
+
+// Jump returns the shortest jump from an instruction at from to the address to,
+// as written over the start of a hooked function
+func Jump(mode *hde.Mode, from, to uint64) []byte {
+	return appendJump(nil, mode, from, to)
+}
+
+// relocate appends the instruction at pc, moved to the trampoline address at. The
+// prologue covers the function from start to end.
+func relocate(b []byte, mode *hde.Mode, insn *hde.Insn, raw []byte, pc, at, start, end uint64) ([]byte, error) {
+	fail := func(reason string) ([]byte, error) {
+		return nil, fmt.Errorf("%w at %#x: %s", ErrUnrelocatable, pc, reason)
+	}
+
+	target, ok := insn.MemTarget(pc)
+	if !ok {
+		if target, ok = insn.BranchTarget(pc); !ok {
+			return append(b, raw...), nil
+		}
+	}
+	if target >= start && target < end {
+		return fail("target inside the overwritten bytes")
+	}
+	enc, err := insn.Relocate(pc, at)
+	if err == nil {
+		return append(b, enc...), nil
+	} else if !errors.Is(err, hde.ErrOutOfRange) {
+		return nil, err
+	}
+
+	// Out of range of the rel32 forms, branches go through absolute jumps
+	switch flow := insn.Flow(); {
+	case insn.Flags&hde.IsRelative == 0:
+		return fail("operand out of range of the trampoline")
+	case flow == hde.Jump:
+		return appendJump(b, mode, at, target), nil
+	case flow == hde.Call:
+		return appendCall(b, mode, at, target), nil
+	case insn.IsJCC():
+		// The inverse condition skips the jump
+		jmp := appendJump(nil, mode, at+2, target)
+		b = append(b, 0x70|insn.OpcodeByte()&0xf^1, byte(len(jmp)))
+		return append(b, jmp...), nil
+	case flow == hde.CondBranch:
+		// LOOP, JCXZ and XBEGIN have no absolute form, so they branch to a jump
+		// over a jump
+		off, n := int(insn.ImmOffset), int(insn.Imm.Bits/8)
+		b = append(b, raw[:off]...)
+		b = append(b, 2)
+		b = append(b, make([]byte, n-1)...)
+		jmp := appendJump(nil, mode, at+uint64(off+n+2), target)
+		b = append(b, 0xeb, byte(len(jmp)))
+		return append(b, jmp...), nil
+	}
+	return fail("branch out of range of the trampoline")
+}
+
+// Build decodes the instructions at the start of the function in code, located at
+// addr, until they cover at least n bytes, and relocates them into a trampoline to
+// run at address at, followed by a jump back to the rest of the function. Relative
+// branches and RIP-relative operands are retargeted with Insn.Relocate, which widens
+// short jumps and conditional jumps to rel32 as needed; out of range, branches fall
+// back to absolute jumps. Prologues that return or jump away before n bytes yield
+// ErrTooShort; branches or operands into the overwritten bytes and RIP-relative
+// operands out of range of the trampoline yield ErrUnrelocatable.
+func Build(mode *hde.Mode, code []byte, addr uint64, n int, at uint64) (*Trampoline, error) {
+	if mode.Bits() == 16 {
+		return nil, ErrMode
+	}
+
+	// Find the instructions to move first, branches into them cannot be relocated
+	var insns []hde.Insn
+	stolen := 0
+	for stolen < n {
+		insn, err := mode.Decode(code[stolen:])
+		if errors.Is(err, hde.ErrLength) {
+			return nil, fmt.Errorf("%w: %d bytes available", ErrTooShort, len(code))
+		} else if err != nil {
+			return nil, fmt.Errorf("hook: decoding at %#x: %w", addr+uint64(stolen), err)
+		}
+		insns = append(insns, insn)
+		stolen += int(insn.Len())
+
+		switch insn.Flow() {
+		case hde.Jump, hde.IndirectJump, hde.Return, hde.Halt, hde.Trap:
+			if stolen < n {
+				return nil, fmt.Errorf("%w: ends at %#x", ErrTooShort, addr+uint64(stolen))
+			}
+		}
+	}
+
+	t := &Trampoline{Addr: at, Stolen: stolen}
+	end := addr + uint64(stolen)
+	off := 0
+	for i := range insns {
+		insn := &insns[i]
+		raw := code[off : off+int(insn.Len())]
+		var err error
+		t.Code, err = relocate(t.Code, mode, insn, raw, addr+uint64(off), at+uint64(len(t.Code)), addr, end)
+		if err != nil {
+			return nil, err
+		}
+		off += int(insn.Len())
+	}
+	t.Code = appendJump(t.Code, mode, at+uint64(len(t.Code)), end)
+	return t, nil
+}
+
+// Detour builds the trampoline for a hook diverting the function in code, located at
+// addr, to detour. It returns the trampoline to place at address at, and the patch
+// to write over the start of the function: a jump to detour padded with INT3 to the
+// instructions it overwrites.
+func Detour(mode *hde.Mode, code []byte, addr, detour, at uint64) (*Trampoline, []byte, error) {
+	patch := Jump(mode, addr, detour)
+	t, err := Build(mode, code, addr, len(patch), at)
+	if err != nil {
+		return nil, nil, err
+	}
+	for len(patch) < t.Stolen {
+		patch = append(patch, 0xcc)
+	}
+	return t, patch, nil
+}
@@ -35,6 +35,8 @@ const (
 	HasSegES    Flag = Flag(1) << (prefixSetShift + PreSegES)    // Indicates ES	 segment prefix. C: p_seg
 	HasSegFS    Flag = Flag(1) << (prefixSetShift + PreSegFS)    // Indicates FS segment prefix. C: p_seg
 	HasSegGS    Flag = Flag(1) << (prefixSetShift + PreSegGS)    // Indicates GS segment prefix. C: p_seg
+	HasVEX      Flag = Flag(1) << (prefixSetShift + PreVEX)      // Indicates VEX prefix present (C4/C5).
+	HasEVEX     Flag = Flag(1) << (prefixSetShift + PreEVEX)     // Indicates EVEX prefix present (62).
 )
 
 // Prefixes returns the set of prefixes present in the instruction
@@ -86,19 +88,72 @@ var flagFmt = bitfield.NewFormatter(map[Flag]string{
 	HasOpSize:   "OpSize",
 	HasAddrSize: "AddrSize",
 	HasLock:     "Lock",
+	HasVEX:      "VEX",
+	HasEVEX:     "EVEX",
 })
 
-// Insn represents a decoded x86 instruction
+// Insn represents a decoded x86 instruction. The methods resolving mnemonics,
+// operands and branch targets depend on the mode the instruction was decoded in,
+// so an Insn must come from Mode.Decode, or have Mode set when built by hand. An
+// Insn without a Mode, such as the one reported with an error for an address no
+// code is mapped at, has no form: its mnemonic is INVALID and it formats as (bad).
 type Insn struct {
-	Flags   Flag    // Instruction flags indicating prefixes and operand types
-	Length  uint8   // Length of the instruction
-	Opcode  uint8   // Primary opcode byte
-	Opcode2 uint8   // Secondary opcode byte (for 0F-prefixed instructions)
-	REX     REX     // REX prefix byte (64-bit mode only)
-	ModRM   ModRM   // ModR/M byte
-	SIB     SIB     // SIB byte
-	Imm     Literal // Immediate value
-	Disp    Literal // Displacement value
+	Flags        Flag                 // Instruction flags indicating prefixes and operand types
+	Length       uint8                // Length of the instruction
+	Prefixes     [MaxInsnLen - 1]byte // Legacy prefix bytes in encoding order, including duplicates
+	NumPrefixes  uint8                // Number of legacy prefix bytes in Prefixes
+	OpcodeOffset uint8                // Offset of the opcode, at the 0F escape of legacy multi-byte opcodes and after any VEX or EVEX prefix
+	ModRMOffset  uint8                // Offset of the ModR/M byte, or 0 if there is none
+	SIBOffset    uint8                // Offset of the SIB byte, or 0 if there is none
+	DispOffset   uint8                // Offset of Disp, or of the second immediate of far pointers and ENTER, or 0 if there is none
+	ImmOffset    uint8                // Offset of Imm, or 0 if there is none
+	Opcode       uint8                // Primary opcode byte
+	Opcode2      uint8                // Secondary opcode byte (for 0F-prefixed instructions)
+	Opcode3      uint8                // Tertiary opcode byte (for 0F 38 and 0F 3A-prefixed instructions)
+	REX          REX                  // REX prefix byte (64-bit mode only)
+	VEX          VEX                  // VEX prefix payload (VEX-encoded instructions only)
+	EVEX         EVEX                 // EVEX prefix payload (EVEX-encoded instructions only)
+	ModRM        ModRM                // ModR/M byte
+	SIB          SIB                  // SIB byte
+	Imm          Literal              // Immediate value
+	Disp         Literal              // Displacement value, or the second immediate of far pointers and ENTER
+	Mode         *Mode                // Mode the instruction was decoded in
+}
+
+// OpcodeMap identifies the opcode map an instruction belongs to
+type OpcodeMap uint8
+
+const (
+	MapPrimary OpcodeMap = iota // One-byte opcodes
+	Map0F                       // Two-byte opcodes (0F xx)
+	Map0F38                     // Three-byte opcodes (0F 38 xx)
+	Map0F3A                     // Three-byte opcodes (0F 3A xx)
+)
+
+// Map returns the opcode map of the instruction.
+// VEX and EVEX-encoded instructions report the map selected by their prefix.
+func (insn *Insn) Map() OpcodeMap {
+	if insn.Opcode != 0x0f {
+		return MapPrimary
+	}
+	switch insn.Opcode2 {
+	case 0x38:
+		return Map0F38
+	case 0x3a:
+		return Map0F3A
+	}
+	return Map0F
+}
+
+// OpcodeByte returns the final opcode byte, which identifies the instruction within its map
+func (insn *Insn) OpcodeByte() uint8 {
+	switch insn.Map() {
+	case MapPrimary:
+		return insn.Opcode
+	case Map0F:
+		return insn.Opcode2
+	}
+	return insn.Opcode3
 }
 
 // Len returns the length of the instruction
@@ -173,10 +228,17 @@ func (insn *Insn) IsRET() bool {
 	return false
 }
 
-// IsCALL returns true if this is a call instruction
+// IsCALL returns true if this is a call instruction, direct or through FF /2 and FF /3.
+// The far call 9A is invalid in 64-bit mode. Use Flow to tell direct and indirect
+// calls apart.
 func (insn *Insn) IsCALL() bool {
-	if insn.Opcode == 0xE8 || insn.Opcode == 0xFF || insn.Opcode == 0x15 {
+	switch insn.Opcode {
+	case 0xE8:
 		return true
+	case 0x9A:
+		return insn.Mode == nil || !insn.Mode.long
+	case 0xFF:
+		return insn.ModRM.Reg() == 2 || insn.ModRM.Reg() == 3
 	}
 	return false
 }
@@ -188,3 +250,40 @@ func (insn *Insn) IsINT() bool {
 	}
 	return false
 }
+
+// BranchTarget returns the destination of a relative jump, call, JCXZ or LOOP
+// located at pc. The displacement is sign-extended and added to the address of the
+// next instruction, and the result wraps within the operand size the way the
+// instruction pointer does, so 16-bit branches stay within their 64K segment.
+// It returns false for instructions without a relative operand.
+func (insn *Insn) BranchTarget(pc uint64) (uint64, bool) {
+	if insn.Flags&IsRelative == 0 {
+		return 0, false
+	}
+	rel, ok := insn.Imm.Int()
+	if !ok {
+		return 0, false
+	}
+	return truncate(pc+uint64(insn.Len())+uint64(rel), insn.branchSize()), true
+}
+
+// branchSize returns the width in bits the instruction pointer wraps at when taking
+// the relative branch of the instruction
+func (insn *Insn) branchSize() int {
+	if insn.Imm.Bits == 16 {
+		return 16
+	}
+	return insn.OperandSize()
+}
+
+// MemTarget returns the address referenced by a RIP-relative memory operand of the
+// instruction located at pc, encoded as ModR/M mod=00 rm=101 in 64-bit mode.
+// With the 67h prefix the operand is EIP-relative and the address wraps at 4G.
+// It returns false for instructions without a RIP-relative operand.
+func (insn *Insn) MemTarget(pc uint64) (uint64, bool) {
+	if insn.Mode == nil || !insn.Mode.long || insn.Flags&IsModRM == 0 || insn.ModRM.Mod() != 0 || insn.ModRM.RM() != 5 {
+		return 0, false
+	}
+	disp, _ := insn.Disp.Int()
+	return truncate(pc+uint64(insn.Len())+uint64(disp), insn.AddressSize()), true
+}
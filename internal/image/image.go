@@ -0,0 +1,132 @@
+// Package image maps the sections of executable files at their addresses and decodes
+// the code in them. The elf, macho and pe packages embed its Image in theirs.
+package image
+
+import (
+	"fmt"
+	"io"
+	"iter"
+	"os"
+
+	hde "github.com/can1357/go-hde"
+)
+
+// Image is a binary with its sections mapped at their addresses
+type Image[S any] struct {
+	Mode     *hde.Mode // Mode32 for 32-bit x86 code, Mode64 for x86-64 code
+	Sections []S       // Mapped sections in header order
+
+	spans    []span    // Address ranges of Sections
+	unmapped error     // Error returned for addresses outside of the executable sections
+	closer   io.Closer // File opened by Open
+}
+
+// span is the address range of a section
+type span struct {
+	addr uint64
+	data []byte
+	exec bool
+}
+
+// New returns an image of code in the mode without sections, reporting addresses
+// outside of its executable sections with the unmapped error
+func New[S any](mode *hde.Mode, unmapped error) Image[S] {
+	return Image[S]{Mode: mode, unmapped: unmapped}
+}
+
+// Map appends the section s, mapped at addr with the contents data. Only sections
+// marked exec are decoded. Map is not a method so that the image types embedding
+// Image do not export it.
+func Map[S any](img *Image[S], s S, addr uint64, data []byte, exec bool) {
+	img.Sections = append(img.Sections, s)
+	img.spans = append(img.spans, span{addr, data, exec})
+}
+
+// file is an image type embedding Image
+type file interface {
+	setCloser(c io.Closer)
+}
+
+// Open opens the named file and reads an image from it with newImage. Closing the
+// image closes the file.
+func Open[I file](name string, newImage func(io.ReaderAt) (I, error)) (I, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		var img I
+		return img, err
+	}
+	img, err := newImage(f)
+	if err != nil {
+		f.Close()
+		return img, err
+	}
+	img.setCloser(f)
+	return img, nil
+}
+
+func (img *Image[S]) setCloser(c io.Closer) {
+	img.closer = c
+}
+
+// Close closes the file opened by Open
+func (img *Image[S]) Close() error {
+	if img.closer == nil {
+		return nil
+	}
+	return img.closer.Close()
+}
+
+// find returns the index of the section holding the address, or -1
+func (img *Image[S]) find(addr uint64) int {
+	for i, s := range img.spans {
+		if addr >= s.addr && addr-s.addr < uint64(len(s.data)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Section returns the section holding the address, or the zero S
+func (img *Image[S]) Section(addr uint64) S {
+	i := img.find(addr)
+	if i < 0 {
+		var s S
+		return s
+	}
+	return img.Sections[i]
+}
+
+// code returns the code from the address to the end of its section
+func (img *Image[S]) code(addr uint64) ([]byte, error) {
+	i := img.find(addr)
+	switch {
+	case i < 0:
+		return nil, fmt.Errorf("%w: %#x", img.unmapped, addr)
+	case !img.spans[i].exec:
+		return nil, fmt.Errorf("%w: %#x is not in an executable section", img.unmapped, addr)
+	}
+	s := img.spans[i]
+	return s.data[addr-s.addr:], nil
+}
+
+// Decode decodes the instruction at the address
+func (img *Image[S]) Decode(addr uint64) (hde.Insn, error) {
+	code, err := img.code(addr)
+	if err != nil {
+		return hde.Insn{}, err
+	}
+	return img.Mode.Decode(code)
+}
+
+// Instructions returns an iterator over the instructions from the address to the end
+// of its section, as Mode.Instructions does. For an address outside of the executable
+// sections it yields a single Inst without a decoded instruction and the error.
+func (img *Image[S]) Instructions(addr uint64) iter.Seq2[hde.Inst, error] {
+	code, err := img.code(addr)
+	if err != nil {
+		return func(yield func(hde.Inst, error) bool) {
+			yield(hde.Inst{Addr: addr}, err)
+		}
+	}
+	return img.Mode.Instructions(code, addr)
+}
@@ -0,0 +1,263 @@
+// Package macho maps the executable sections of macOS Mach-O binaries, including the
+// x86-64 slice of universal binaries, and decodes their code from the entry point
+// and exported symbols.
+package macho
+
+import (
+	"cmp"
+	"debug/macho"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/flow"
+	"github.com/can1357/go-hde/internal/image"
+)
+
+var (
+	// ErrMachine is returned for binaries, or universal binaries without a slice,
+	// that are not x86 or x86-64 code
+	ErrMachine = errors.New("macho: unsupported machine type")
+	// ErrUnmapped is returned for addresses outside of the executable sections
+	ErrUnmapped = errors.New("macho: address not in an executable section")
+)
+
+const (
+	loadCmdMain = 0x80000028 // LC_MAIN
+
+	nStab = 0xe0 // N_STAB: debugging symbol
+	nType = 0x0e // N_TYPE: symbol type mask
+	nSect = 0x0e // N_SECT: defined in a section
+	nExt  = 0x01 // N_EXT: external symbol
+
+	indirectLocal = 0x80000000 // INDIRECT_SYMBOL_LOCAL
+	indirectAbs   = 0x40000000 // INDIRECT_SYMBOL_ABS
+
+	attrInstructions = 0x80000000 | 0x400 // S_ATTR_PURE_INSTRUCTIONS | S_ATTR_SOME_INSTRUCTIONS
+)
+
+// Section is an executable section mapped at its address
+type Section struct {
+	Seg  string // Segment name, such as __TEXT
+	Name string // Section name, such as __text or __stubs
+	Addr uint64 // Address of the first byte
+	Data []byte // Contents
+
+	indirect, stubSize uint32 // First indirect symbol and size of the entries of a stub section
+}
+
+// EntryKind identifies where an entry point of the binary comes from
+type EntryKind uint8
+
+const (
+	EntryPoint EntryKind = iota // The main entry point from LC_MAIN
+	Export                      // An exported function
+)
+
+var entryKindNames = [...]string{
+	EntryPoint: "EntryPoint",
+	Export:     "Export",
+}
+
+// String returns the name of the entry kind
+func (k EntryKind) String() string {
+	if int(k) >= len(entryKindNames) {
+		return fmt.Sprintf("EntryKind(%d)", k)
+	}
+	return entryKindNames[k]
+}
+
+// Entry is an address the binary is entered at
+type Entry struct {
+	Addr uint64    // Virtual address
+	Kind EntryKind // Where the entry point comes from
+	Name string    // Symbol name as stored, with its leading underscore
+}
+
+// Stub is an entry of the __stubs section, which jumps to an imported function
+type Stub struct {
+	Addr uint64 // Address of the stub
+	Name string // Imported symbol name, with its leading underscore
+}
+
+// Image is a Mach-O binary with its executable sections mapped at their addresses.
+// Sections lists them in header order, such as __TEXT,__text and __TEXT,__stubs.
+// Mode is Mode64 for x86-64 binaries and Mode32 for i386 ones. The embedded image
+// provides Section, Decode, Instructions and Close.
+type Image struct {
+	image.Image[*Section]
+	File *macho.File
+}
+
+// Open opens the named Mach-O file
+func Open(name string) (*Image, error) {
+	return image.Open(name, NewImage)
+}
+
+// NewImage reads a Mach-O binary from r and maps its executable sections. Universal
+// binaries are read from their x86-64 slice, or their i386 slice if they have none.
+func NewImage(r io.ReaderAt) (*Image, error) {
+	f, err := thin(r)
+	if err != nil {
+		return nil, err
+	}
+	var mode *hde.Mode
+	switch f.Cpu {
+	case macho.CpuAmd64:
+		mode = hde.Mode64
+	case macho.Cpu386:
+		mode = hde.Mode32
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrMachine, f.Cpu)
+	}
+	img := &Image{Image: image.New[*Section](mode, ErrUnmapped), File: f}
+
+	for i, s := range f.Sections {
+		if s.Flags&attrInstructions == 0 {
+			continue
+		}
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("macho: reading section %s,%s: %w", s.Seg, s.Name, err)
+		}
+		sec := &Section{Seg: s.Seg, Name: s.Name, Addr: s.Addr, Data: data}
+		sec.indirect, sec.stubSize = img.reserved(i)
+		image.Map(&img.Image, sec, s.Addr, data, true)
+	}
+	return img, nil
+}
+
+// thin returns the binary in r, or the x86-64 or i386 slice of a universal binary
+func thin(r io.ReaderAt) (*macho.File, error) {
+	ff, err := macho.NewFatFile(r)
+	if errors.Is(err, macho.ErrNotFat) {
+		return macho.NewFile(r)
+	} else if err != nil {
+		return nil, err
+	}
+	for _, cpu := range []macho.Cpu{macho.CpuAmd64, macho.Cpu386} {
+		for _, arch := range ff.Arches {
+			if arch.Cpu == cpu {
+				return arch.File, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: universal binary without an x86 slice", ErrMachine)
+}
+
+// reserved returns the reserved1 and reserved2 fields of the header of the section
+// at the index, which debug/macho does not decode. For stub sections they hold the
+// first indirect symbol and the size of a stub.
+func (img *Image) reserved(index int) (uint32, uint32) {
+	hdrSize, secSize, off := 56, 68, 60 // segment_command, section
+	if img.Mode.IsLong() {
+		hdrSize, secSize, off = 72, 80, 68 // segment_command_64, section_64
+	}
+	for _, l := range img.File.Loads {
+		seg, ok := l.(*macho.Segment)
+		if !ok {
+			continue
+		}
+		if index < int(seg.Nsect) {
+			start := hdrSize + index*secSize + off
+			if raw := seg.Raw(); start+8 <= len(raw) {
+				return img.File.ByteOrder.Uint32(raw[start:]), img.File.ByteOrder.Uint32(raw[start+4:])
+			}
+			return 0, 0
+		}
+		index -= int(seg.Nsect)
+	}
+	return 0, 0
+}
+
+// Lookup returns the executable section with the segment and section names, such as
+// __TEXT and __text, or nil
+func (img *Image) Lookup(seg, name string) *Section {
+	for _, s := range img.Sections {
+		if s.Seg == seg && s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// EntryPoint returns the address of the main entry point from LC_MAIN, or false for
+// binaries without one, such as dylibs
+func (img *Image) EntryPoint() (uint64, bool) {
+	bo := img.File.ByteOrder
+	for _, l := range img.File.Loads {
+		raw := l.Raw()
+		if len(raw) < 16 || bo.Uint32(raw) != loadCmdMain {
+			continue
+		}
+		// entryoff is a file offset into the segment holding the code
+		off := bo.Uint64(raw[8:])
+		for _, l := range img.File.Loads {
+			if seg, ok := l.(*macho.Segment); ok && seg.Filesz != 0 && off >= seg.Offset && off-seg.Offset < seg.Filesz {
+				return seg.Addr + off - seg.Offset, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Exports returns the external functions defined in the executable sections, from
+// the symbol table
+func (img *Image) Exports() []Entry {
+	if img.File.Symtab == nil {
+		return nil
+	}
+	var exports []Entry
+	for _, s := range img.File.Symtab.Syms {
+		if s.Type&nStab != 0 || s.Type&nType != nSect || s.Type&nExt == 0 || img.Section(s.Value) == nil {
+			continue
+		}
+		exports = append(exports, Entry{Addr: s.Value, Kind: Export, Name: s.Name})
+	}
+	slices.SortStableFunc(exports, func(a, b Entry) int { return cmp.Compare(a.Addr, b.Addr) })
+	return exports
+}
+
+// Entries returns the entry point and the exports of the binary
+func (img *Image) Entries() []Entry {
+	var entries []Entry
+	if addr, ok := img.EntryPoint(); ok && img.Section(addr) != nil {
+		entries = append(entries, Entry{Addr: addr, Kind: EntryPoint})
+	}
+	return append(entries, img.Exports()...)
+}
+
+// Stubs returns the entries of the __TEXT,__stubs section, named by the imported
+// symbols they jump to through the indirect symbol table
+func (img *Image) Stubs() []Stub {
+	s := img.Lookup("__TEXT", "__stubs")
+	if s == nil || s.stubSize == 0 || img.File.Dysymtab == nil || img.File.Symtab == nil {
+		return nil
+	}
+	indirect, syms := img.File.Dysymtab.IndirectSyms, img.File.Symtab.Syms
+	var stubs []Stub
+	for i := range uint32(len(s.Data)) / s.stubSize {
+		stub := Stub{Addr: s.Addr + uint64(i*s.stubSize)}
+		if j := s.indirect + i; int(j) < len(indirect) && indirect[j]&(indirectLocal|indirectAbs) == 0 && int(indirect[j]) < len(syms) {
+			stub.Name = syms[indirect[j]].Name
+		}
+		stubs = append(stubs, stub)
+	}
+	return stubs
+}
+
+// Disassemble decodes the executable sections by recursive descent from the entry
+// point and the exports of the binary
+func (img *Image) Disassemble() (*flow.Graph, error) {
+	var addrs []uint64
+	for _, e := range img.Entries() {
+		addrs = append(addrs, e.Addr)
+	}
+	segs := make([]flow.Segment, len(img.Sections))
+	for i, s := range img.Sections {
+		segs[i] = flow.Segment{Addr: s.Addr, Data: s.Data}
+	}
+	return flow.DisassembleSegments(img.Mode, segs, addrs...)
+}
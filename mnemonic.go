@@ -0,0 +1,2689 @@
+package hde
+
+import "fmt"
+
+// Mnemonic identifies the operation performed by an instruction
+type Mnemonic uint16
+
+// Instruction mnemonics
+const (
+	INVALID Mnemonic = iota // Unknown or undefined instruction
+	AAA
+	AAD
+	AAM
+	AAS
+	ADC
+	ADCX
+	ADD
+	ADDPD
+	ADDPS
+	ADDSD
+	ADDSS
+	ADDSUBPD
+	ADDSUBPS
+	ADOX
+	AESDEC
+	AESDECLAST
+	AESENC
+	AESENCLAST
+	AESIMC
+	AESKEYGENASSIST
+	AND
+	ANDN
+	ANDNPD
+	ANDNPS
+	ANDPD
+	ANDPS
+	ARPL
+	BEXTR
+	BLENDPD
+	BLENDPS
+	BLENDVPD
+	BLENDVPS
+	BLSI
+	BLSMSK
+	BLSR
+	BOUND
+	BSF
+	BSR
+	BSWAP
+	BT
+	BTC
+	BTR
+	BTS
+	BZHI
+	CALL
+	CBW
+	CDQ
+	CDQE
+	CLAC
+	CLC
+	CLD
+	CLFLUSH
+	CLFLUSHOPT
+	CLGI
+	CLI
+	CLTS
+	CLWB
+	CLZERO
+	CMC
+	CMOVA
+	CMOVAE
+	CMOVB
+	CMOVBE
+	CMOVE
+	CMOVG
+	CMOVGE
+	CMOVL
+	CMOVLE
+	CMOVNE
+	CMOVNO
+	CMOVNP
+	CMOVNS
+	CMOVO
+	CMOVP
+	CMOVS
+	CMP
+	CMPPD
+	CMPPS
+	CMPSB
+	CMPSD
+	CMPSQ
+	CMPSS
+	CMPSW
+	CMPXCHG
+	CMPXCHG16B
+	CMPXCHG8B
+	COMISD
+	COMISS
+	CPUID
+	CQO
+	CRC32
+	CVTDQ2PD
+	CVTDQ2PS
+	CVTPD2DQ
+	CVTPD2PI
+	CVTPD2PS
+	CVTPI2PD
+	CVTPI2PS
+	CVTPS2DQ
+	CVTPS2PD
+	CVTPS2PI
+	CVTSD2SI
+	CVTSD2SS
+	CVTSI2SD
+	CVTSI2SS
+	CVTSS2SD
+	CVTSS2SI
+	CVTTPD2DQ
+	CVTTPD2PI
+	CVTTPS2DQ
+	CVTTPS2PI
+	CVTTSD2SI
+	CVTTSS2SI
+	CWD
+	CWDE
+	DAA
+	DAS
+	DEC
+	DIV
+	DIVPD
+	DIVPS
+	DIVSD
+	DIVSS
+	DPPD
+	DPPS
+	EMMS
+	ENCLS
+	ENCLU
+	ENDBR32
+	ENDBR64
+	ENTER
+	EXTRACTPS
+	F2XM1
+	FABS
+	FADD
+	FADDP
+	FBLD
+	FBSTP
+	FCHS
+	FCMOVB
+	FCMOVBE
+	FCMOVE
+	FCMOVNB
+	FCMOVNBE
+	FCMOVNE
+	FCMOVNU
+	FCMOVU
+	FCOM
+	FCOMI
+	FCOMIP
+	FCOMP
+	FCOMPP
+	FCOS
+	FDECSTP
+	FDIV
+	FDIVP
+	FDIVR
+	FDIVRP
+	FEMMS
+	FFREE
+	FFREEP
+	FIADD
+	FICOM
+	FICOMP
+	FIDIV
+	FIDIVR
+	FILD
+	FIMUL
+	FINCSTP
+	FIST
+	FISTP
+	FISTTP
+	FISUB
+	FISUBR
+	FLD
+	FLD1
+	FLDCW
+	FLDENV
+	FLDL2E
+	FLDL2T
+	FLDLG2
+	FLDLN2
+	FLDPI
+	FLDZ
+	FMUL
+	FMULP
+	FNCLEX
+	FNINIT
+	FNOP
+	FNSAVE
+	FNSTCW
+	FNSTENV
+	FNSTSW
+	FPATAN
+	FPREM
+	FPREM1
+	FPTAN
+	FRNDINT
+	FRSTOR
+	FSCALE
+	FSIN
+	FSINCOS
+	FSQRT
+	FST
+	FSTP
+	FSUB
+	FSUBP
+	FSUBR
+	FSUBRP
+	FTST
+	FUCOM
+	FUCOMI
+	FUCOMIP
+	FUCOMP
+	FUCOMPP
+	FWAIT
+	FXAM
+	FXCH
+	FXRSTOR
+	FXRSTOR64
+	FXSAVE
+	FXSAVE64
+	FXTRACT
+	FYL2X
+	FYL2XP1
+	GETSEC
+	GF2P8AFFINEINVQB
+	GF2P8AFFINEQB
+	GF2P8MULB
+	HADDPD
+	HADDPS
+	HLT
+	HSUBPD
+	HSUBPS
+	IDIV
+	IMUL
+	IN
+	INC
+	INCSSPD
+	INCSSPQ
+	INSB
+	INSD
+	INSERTPS
+	INSW
+	INT
+	INT1
+	INT3
+	INTO
+	INVD
+	INVEPT
+	INVLPG
+	INVLPGA
+	INVPCID
+	INVVPID
+	IRET
+	IRETD
+	IRETQ
+	JA
+	JAE
+	JB
+	JBE
+	JCXZ
+	JE
+	JECXZ
+	JG
+	JGE
+	JL
+	JLE
+	JMP
+	JNE
+	JNO
+	JNP
+	JNS
+	JO
+	JP
+	JRCXZ
+	JS
+	KADDB
+	KADDD
+	KADDQ
+	KADDW
+	KANDB
+	KANDD
+	KANDNB
+	KANDND
+	KANDNQ
+	KANDNW
+	KANDQ
+	KANDW
+	KMOVB
+	KMOVD
+	KMOVQ
+	KMOVW
+	KNOTB
+	KNOTD
+	KNOTQ
+	KNOTW
+	KORB
+	KORD
+	KORQ
+	KORTESTB
+	KORTESTD
+	KORTESTQ
+	KORTESTW
+	KORW
+	KSHIFTLB
+	KSHIFTLD
+	KSHIFTLQ
+	KSHIFTLW
+	KSHIFTRB
+	KSHIFTRD
+	KSHIFTRQ
+	KSHIFTRW
+	KTESTB
+	KTESTD
+	KTESTQ
+	KTESTW
+	KUNPCKBW
+	KUNPCKDQ
+	KUNPCKWD
+	KXNORB
+	KXNORD
+	KXNORQ
+	KXNORW
+	KXORB
+	KXORD
+	KXORQ
+	KXORW
+	LAHF
+	LAR
+	LDDQU
+	LDMXCSR
+	LDS
+	LEA
+	LEAVE
+	LES
+	LFENCE
+	LFS
+	LGDT
+	LGS
+	LIDT
+	LLDT
+	LMSW
+	LODSB
+	LODSD
+	LODSQ
+	LODSW
+	LOOP
+	LOOPE
+	LOOPNE
+	LSL
+	LSS
+	LTR
+	LZCNT
+	MASKMOVDQU
+	MASKMOVQ
+	MAXPD
+	MAXPS
+	MAXSD
+	MAXSS
+	MFENCE
+	MINPD
+	MINPS
+	MINSD
+	MINSS
+	MONITOR
+	MONITORX
+	MOV
+	MOVAPD
+	MOVAPS
+	MOVBE
+	MOVD
+	MOVDDUP
+	MOVDQ2Q
+	MOVDQA
+	MOVDQU
+	MOVHLPS
+	MOVHPD
+	MOVHPS
+	MOVLHPS
+	MOVLPD
+	MOVLPS
+	MOVMSKPD
+	MOVMSKPS
+	MOVNTDQ
+	MOVNTDQA
+	MOVNTI
+	MOVNTPD
+	MOVNTPS
+	MOVNTQ
+	MOVQ
+	MOVQ2DQ
+	MOVSB
+	MOVSD
+	MOVSHDUP
+	MOVSLDUP
+	MOVSQ
+	MOVSS
+	MOVSW
+	MOVSX
+	MOVSXD
+	MOVUPD
+	MOVUPS
+	MOVZX
+	MPSADBW
+	MUL
+	MULPD
+	MULPS
+	MULSD
+	MULSS
+	MULX
+	MWAIT
+	MWAITX
+	NEG
+	NOP
+	NOT
+	OR
+	ORPD
+	ORPS
+	OUT
+	OUTSB
+	OUTSD
+	OUTSW
+	PABSB
+	PABSD
+	PABSW
+	PACKSSDW
+	PACKSSWB
+	PACKUSDW
+	PACKUSWB
+	PADDB
+	PADDD
+	PADDQ
+	PADDSB
+	PADDSW
+	PADDUSB
+	PADDUSW
+	PADDW
+	PALIGNR
+	PAND
+	PANDN
+	PAUSE
+	PAVGB
+	PAVGW
+	PBLENDVB
+	PBLENDW
+	PCLMULQDQ
+	PCMPEQB
+	PCMPEQD
+	PCMPEQQ
+	PCMPEQW
+	PCMPESTRI
+	PCMPESTRM
+	PCMPGTB
+	PCMPGTD
+	PCMPGTQ
+	PCMPGTW
+	PCMPISTRI
+	PCMPISTRM
+	PDEP
+	PEXT
+	PEXTRB
+	PEXTRD
+	PEXTRQ
+	PEXTRW
+	PHADDD
+	PHADDSW
+	PHADDW
+	PHMINPOSUW
+	PHSUBD
+	PHSUBSW
+	PHSUBW
+	PINSRB
+	PINSRD
+	PINSRQ
+	PINSRW
+	PMADDUBSW
+	PMADDWD
+	PMAXSB
+	PMAXSD
+	PMAXSW
+	PMAXUB
+	PMAXUD
+	PMAXUW
+	PMINSB
+	PMINSD
+	PMINSW
+	PMINUB
+	PMINUD
+	PMINUW
+	PMOVMSKB
+	PMOVSXBD
+	PMOVSXBQ
+	PMOVSXBW
+	PMOVSXDQ
+	PMOVSXWD
+	PMOVSXWQ
+	PMOVZXBD
+	PMOVZXBQ
+	PMOVZXBW
+	PMOVZXDQ
+	PMOVZXWD
+	PMOVZXWQ
+	PMULDQ
+	PMULHRSW
+	PMULHUW
+	PMULHW
+	PMULLD
+	PMULLW
+	PMULUDQ
+	POP
+	POPA
+	POPAD
+	POPCNT
+	POPF
+	POPFD
+	POPFQ
+	POR
+	PREFETCH
+	PREFETCHNTA
+	PREFETCHT0
+	PREFETCHT1
+	PREFETCHT2
+	PREFETCHW
+	PREFETCHWT1
+	PSADBW
+	PSHUFB
+	PSHUFD
+	PSHUFHW
+	PSHUFLW
+	PSHUFW
+	PSIGNB
+	PSIGND
+	PSIGNW
+	PSLLD
+	PSLLDQ
+	PSLLQ
+	PSLLW
+	PSRAD
+	PSRAW
+	PSRLD
+	PSRLDQ
+	PSRLQ
+	PSRLW
+	PSUBB
+	PSUBD
+	PSUBQ
+	PSUBSB
+	PSUBSW
+	PSUBUSB
+	PSUBUSW
+	PSUBW
+	PTEST
+	PUNPCKHBW
+	PUNPCKHDQ
+	PUNPCKHQDQ
+	PUNPCKHWD
+	PUNPCKLBW
+	PUNPCKLDQ
+	PUNPCKLQDQ
+	PUNPCKLWD
+	PUSH
+	PUSHA
+	PUSHAD
+	PUSHF
+	PUSHFD
+	PUSHFQ
+	PXOR
+	RCL
+	RCPPS
+	RCPSS
+	RCR
+	RDFSBASE
+	RDGSBASE
+	RDMSR
+	RDPID
+	RDPKRU
+	RDPMC
+	RDRAND
+	RDSEED
+	RDTSC
+	RDTSCP
+	RET
+	RETF
+	ROL
+	ROR
+	RORX
+	ROUNDPD
+	ROUNDPS
+	ROUNDSD
+	ROUNDSS
+	RSM
+	RSQRTPS
+	RSQRTSS
+	SAHF
+	SAL
+	SALC
+	SAR
+	SARX
+	SBB
+	SCASB
+	SCASD
+	SCASQ
+	SCASW
+	SERIALIZE
+	SETA
+	SETAE
+	SETB
+	SETBE
+	SETE
+	SETG
+	SETGE
+	SETL
+	SETLE
+	SETNE
+	SETNO
+	SETNP
+	SETNS
+	SETO
+	SETP
+	SETS
+	SFENCE
+	SGDT
+	SHA1MSG1
+	SHA1MSG2
+	SHA1NEXTE
+	SHA1RNDS4
+	SHA256MSG1
+	SHA256MSG2
+	SHA256RNDS2
+	SHL
+	SHLD
+	SHLX
+	SHR
+	SHRD
+	SHRX
+	SHUFPD
+	SHUFPS
+	SIDT
+	SKINIT
+	SLDT
+	SMSW
+	SQRTPD
+	SQRTPS
+	SQRTSD
+	SQRTSS
+	STAC
+	STC
+	STD
+	STGI
+	STI
+	STMXCSR
+	STOSB
+	STOSD
+	STOSQ
+	STOSW
+	STR
+	SUB
+	SUBPD
+	SUBPS
+	SUBSD
+	SUBSS
+	SWAPGS
+	SYSCALL
+	SYSENTER
+	SYSEXIT
+	SYSRET
+	TEST
+	TPAUSE
+	TZCNT
+	UCOMISD
+	UCOMISS
+	UD0
+	UD1
+	UD2
+	UMONITOR
+	UMWAIT
+	UNPCKHPD
+	UNPCKHPS
+	UNPCKLPD
+	UNPCKLPS
+	VADDPD
+	VADDPS
+	VADDSD
+	VADDSS
+	VADDSUBPD
+	VADDSUBPS
+	VAESDEC
+	VAESDECLAST
+	VAESENC
+	VAESENCLAST
+	VAESIMC
+	VAESKEYGENASSIST
+	VALIGND
+	VALIGNQ
+	VANDNPD
+	VANDNPS
+	VANDPD
+	VANDPS
+	VBLENDMPD
+	VBLENDMPS
+	VBLENDPD
+	VBLENDPS
+	VBLENDVPD
+	VBLENDVPS
+	VBROADCASTF128
+	VBROADCASTF32X2
+	VBROADCASTF32X4
+	VBROADCASTF32X8
+	VBROADCASTF64X2
+	VBROADCASTF64X4
+	VBROADCASTI128
+	VBROADCASTI32X2
+	VBROADCASTI32X4
+	VBROADCASTI32X8
+	VBROADCASTI64X2
+	VBROADCASTI64X4
+	VBROADCASTSD
+	VBROADCASTSS
+	VCMPPD
+	VCMPPS
+	VCMPSD
+	VCMPSS
+	VCOMISD
+	VCOMISS
+	VCOMPRESSPD
+	VCOMPRESSPS
+	VCVTDQ2PD
+	VCVTDQ2PS
+	VCVTPD2DQ
+	VCVTPD2PS
+	VCVTPD2QQ
+	VCVTPD2UDQ
+	VCVTPD2UQQ
+	VCVTPH2PS
+	VCVTPS2DQ
+	VCVTPS2PD
+	VCVTPS2PH
+	VCVTPS2QQ
+	VCVTPS2UDQ
+	VCVTPS2UQQ
+	VCVTQQ2PD
+	VCVTQQ2PS
+	VCVTSD2SI
+	VCVTSD2SS
+	VCVTSD2USI
+	VCVTSI2SD
+	VCVTSI2SS
+	VCVTSS2SD
+	VCVTSS2SI
+	VCVTSS2USI
+	VCVTTPD2DQ
+	VCVTTPD2QQ
+	VCVTTPD2UDQ
+	VCVTTPD2UQQ
+	VCVTTPS2DQ
+	VCVTTPS2QQ
+	VCVTTPS2UDQ
+	VCVTTPS2UQQ
+	VCVTTSD2SI
+	VCVTTSD2USI
+	VCVTTSS2SI
+	VCVTTSS2USI
+	VCVTUDQ2PD
+	VCVTUDQ2PS
+	VCVTUQQ2PD
+	VCVTUQQ2PS
+	VCVTUSI2SD
+	VCVTUSI2SS
+	VDBPSADBW
+	VDIVPD
+	VDIVPS
+	VDIVSD
+	VDIVSS
+	VDPPD
+	VDPPS
+	VERR
+	VERW
+	VEXP2PD
+	VEXP2PS
+	VEXPANDPD
+	VEXPANDPS
+	VEXTRACTF128
+	VEXTRACTF32X4
+	VEXTRACTF32X8
+	VEXTRACTF64X2
+	VEXTRACTF64X4
+	VEXTRACTI128
+	VEXTRACTI32X4
+	VEXTRACTI32X8
+	VEXTRACTI64X2
+	VEXTRACTI64X4
+	VEXTRACTPS
+	VFIXUPIMMPD
+	VFIXUPIMMPS
+	VFIXUPIMMSD
+	VFIXUPIMMSS
+	VFMADD132PD
+	VFMADD132PS
+	VFMADD132SD
+	VFMADD132SS
+	VFMADD213PD
+	VFMADD213PS
+	VFMADD213SD
+	VFMADD213SS
+	VFMADD231PD
+	VFMADD231PS
+	VFMADD231SD
+	VFMADD231SS
+	VFMADDSUB132PD
+	VFMADDSUB132PS
+	VFMADDSUB213PD
+	VFMADDSUB213PS
+	VFMADDSUB231PD
+	VFMADDSUB231PS
+	VFMSUB132PD
+	VFMSUB132PS
+	VFMSUB132SD
+	VFMSUB132SS
+	VFMSUB213PD
+	VFMSUB213PS
+	VFMSUB213SD
+	VFMSUB213SS
+	VFMSUB231PD
+	VFMSUB231PS
+	VFMSUB231SD
+	VFMSUB231SS
+	VFMSUBADD132PD
+	VFMSUBADD132PS
+	VFMSUBADD213PD
+	VFMSUBADD213PS
+	VFMSUBADD231PD
+	VFMSUBADD231PS
+	VFNMADD132PD
+	VFNMADD132PS
+	VFNMADD132SD
+	VFNMADD132SS
+	VFNMADD213PD
+	VFNMADD213PS
+	VFNMADD213SD
+	VFNMADD213SS
+	VFNMADD231PD
+	VFNMADD231PS
+	VFNMADD231SD
+	VFNMADD231SS
+	VFNMSUB132PD
+	VFNMSUB132PS
+	VFNMSUB132SD
+	VFNMSUB132SS
+	VFNMSUB213PD
+	VFNMSUB213PS
+	VFNMSUB213SD
+	VFNMSUB213SS
+	VFNMSUB231PD
+	VFNMSUB231PS
+	VFNMSUB231SD
+	VFNMSUB231SS
+	VFPCLASSPD
+	VFPCLASSPS
+	VFPCLASSSD
+	VFPCLASSSS
+	VGATHERDPD
+	VGATHERDPS
+	VGATHERQPD
+	VGATHERQPS
+	VGETEXPPD
+	VGETEXPPS
+	VGETEXPSD
+	VGETEXPSS
+	VGETMANTPD
+	VGETMANTPS
+	VGETMANTSD
+	VGETMANTSS
+	VGF2P8AFFINEINVQB
+	VGF2P8AFFINEQB
+	VGF2P8MULB
+	VHADDPD
+	VHADDPS
+	VHSUBPD
+	VHSUBPS
+	VINSERTF128
+	VINSERTF32X4
+	VINSERTF32X8
+	VINSERTF64X2
+	VINSERTF64X4
+	VINSERTI128
+	VINSERTI32X4
+	VINSERTI32X8
+	VINSERTI64X2
+	VINSERTI64X4
+	VINSERTPS
+	VLDDQU
+	VLDMXCSR
+	VMASKMOVDQU
+	VMASKMOVPD
+	VMASKMOVPS
+	VMAXPD
+	VMAXPS
+	VMAXSD
+	VMAXSS
+	VMCALL
+	VMCLEAR
+	VMFUNC
+	VMINPD
+	VMINPS
+	VMINSD
+	VMINSS
+	VMLAUNCH
+	VMLOAD
+	VMMCALL
+	VMOVAPD
+	VMOVAPS
+	VMOVD
+	VMOVDDUP
+	VMOVDQA
+	VMOVDQA32
+	VMOVDQA64
+	VMOVDQU
+	VMOVDQU16
+	VMOVDQU32
+	VMOVDQU64
+	VMOVDQU8
+	VMOVHLPS
+	VMOVHPD
+	VMOVHPS
+	VMOVLHPS
+	VMOVLPD
+	VMOVLPS
+	VMOVMSKPD
+	VMOVMSKPS
+	VMOVNTDQ
+	VMOVNTDQA
+	VMOVNTPD
+	VMOVNTPS
+	VMOVQ
+	VMOVSD
+	VMOVSHDUP
+	VMOVSLDUP
+	VMOVSS
+	VMOVUPD
+	VMOVUPS
+	VMPSADBW
+	VMPTRLD
+	VMPTRST
+	VMREAD
+	VMRESUME
+	VMRUN
+	VMSAVE
+	VMULPD
+	VMULPS
+	VMULSD
+	VMULSS
+	VMWRITE
+	VMXOFF
+	VMXON
+	VORPD
+	VORPS
+	VPABSB
+	VPABSD
+	VPABSQ
+	VPABSW
+	VPACKSSDW
+	VPACKSSWB
+	VPACKUSDW
+	VPACKUSWB
+	VPADDB
+	VPADDD
+	VPADDQ
+	VPADDSB
+	VPADDSW
+	VPADDUSB
+	VPADDUSW
+	VPADDW
+	VPALIGNR
+	VPAND
+	VPANDD
+	VPANDN
+	VPANDND
+	VPANDNQ
+	VPANDQ
+	VPAVGB
+	VPAVGW
+	VPBLENDD
+	VPBLENDMB
+	VPBLENDMD
+	VPBLENDMQ
+	VPBLENDMW
+	VPBLENDVB
+	VPBLENDW
+	VPBROADCASTB
+	VPBROADCASTD
+	VPBROADCASTMB2Q
+	VPBROADCASTMW2D
+	VPBROADCASTQ
+	VPBROADCASTW
+	VPCLMULQDQ
+	VPCMPB
+	VPCMPD
+	VPCMPEQB
+	VPCMPEQD
+	VPCMPEQQ
+	VPCMPEQW
+	VPCMPESTRI
+	VPCMPESTRM
+	VPCMPGTB
+	VPCMPGTD
+	VPCMPGTQ
+	VPCMPGTW
+	VPCMPISTRI
+	VPCMPISTRM
+	VPCMPQ
+	VPCMPUB
+	VPCMPUD
+	VPCMPUQ
+	VPCMPUW
+	VPCMPW
+	VPCOMPRESSB
+	VPCOMPRESSD
+	VPCOMPRESSQ
+	VPCOMPRESSW
+	VPCONFLICTD
+	VPCONFLICTQ
+	VPDPBUSD
+	VPDPBUSDS
+	VPDPWSSD
+	VPDPWSSDS
+	VPERM2F128
+	VPERM2I128
+	VPERMB
+	VPERMD
+	VPERMI2B
+	VPERMI2D
+	VPERMI2PD
+	VPERMI2PS
+	VPERMI2Q
+	VPERMI2W
+	VPERMILPD
+	VPERMILPS
+	VPERMPD
+	VPERMPS
+	VPERMQ
+	VPERMT2B
+	VPERMT2D
+	VPERMT2PD
+	VPERMT2PS
+	VPERMT2Q
+	VPERMT2W
+	VPERMW
+	VPEXPANDB
+	VPEXPANDD
+	VPEXPANDQ
+	VPEXPANDW
+	VPEXTRB
+	VPEXTRD
+	VPEXTRQ
+	VPEXTRW
+	VPGATHERDD
+	VPGATHERDQ
+	VPGATHERQD
+	VPGATHERQQ
+	VPHADDD
+	VPHADDSW
+	VPHADDW
+	VPHMINPOSUW
+	VPHSUBD
+	VPHSUBSW
+	VPHSUBW
+	VPINSRB
+	VPINSRD
+	VPINSRQ
+	VPINSRW
+	VPLZCNTD
+	VPLZCNTQ
+	VPMADD52HUQ
+	VPMADD52LUQ
+	VPMADDUBSW
+	VPMADDWD
+	VPMASKMOVD
+	VPMASKMOVQ
+	VPMAXSB
+	VPMAXSD
+	VPMAXSQ
+	VPMAXSW
+	VPMAXUB
+	VPMAXUD
+	VPMAXUQ
+	VPMAXUW
+	VPMINSB
+	VPMINSD
+	VPMINSQ
+	VPMINSW
+	VPMINUB
+	VPMINUD
+	VPMINUQ
+	VPMINUW
+	VPMOVB2M
+	VPMOVD2M
+	VPMOVDB
+	VPMOVDW
+	VPMOVM2B
+	VPMOVM2D
+	VPMOVM2Q
+	VPMOVM2W
+	VPMOVMSKB
+	VPMOVQ2M
+	VPMOVQB
+	VPMOVQD
+	VPMOVQW
+	VPMOVSDB
+	VPMOVSDW
+	VPMOVSQB
+	VPMOVSQD
+	VPMOVSQW
+	VPMOVSWB
+	VPMOVSXBD
+	VPMOVSXBQ
+	VPMOVSXBW
+	VPMOVSXDQ
+	VPMOVSXWD
+	VPMOVSXWQ
+	VPMOVUSDB
+	VPMOVUSDW
+	VPMOVUSQB
+	VPMOVUSQD
+	VPMOVUSQW
+	VPMOVUSWB
+	VPMOVW2M
+	VPMOVWB
+	VPMOVZXBD
+	VPMOVZXBQ
+	VPMOVZXBW
+	VPMOVZXDQ
+	VPMOVZXWD
+	VPMOVZXWQ
+	VPMULDQ
+	VPMULHRSW
+	VPMULHUW
+	VPMULHW
+	VPMULLD
+	VPMULLQ
+	VPMULLW
+	VPMULTISHIFTQB
+	VPMULUDQ
+	VPOPCNTB
+	VPOPCNTD
+	VPOPCNTQ
+	VPOPCNTW
+	VPOR
+	VPORD
+	VPORQ
+	VPROLD
+	VPROLQ
+	VPROLVD
+	VPROLVQ
+	VPRORD
+	VPRORQ
+	VPRORVD
+	VPRORVQ
+	VPSADBW
+	VPSCATTERDD
+	VPSCATTERDQ
+	VPSCATTERQD
+	VPSCATTERQQ
+	VPSHLDD
+	VPSHLDQ
+	VPSHLDVD
+	VPSHLDVQ
+	VPSHLDVW
+	VPSHLDW
+	VPSHRDD
+	VPSHRDQ
+	VPSHRDVD
+	VPSHRDVQ
+	VPSHRDVW
+	VPSHRDW
+	VPSHUFB
+	VPSHUFBITQMB
+	VPSHUFD
+	VPSHUFHW
+	VPSHUFLW
+	VPSIGNB
+	VPSIGND
+	VPSIGNW
+	VPSLLD
+	VPSLLDQ
+	VPSLLQ
+	VPSLLVD
+	VPSLLVQ
+	VPSLLVW
+	VPSLLW
+	VPSRAD
+	VPSRAQ
+	VPSRAVD
+	VPSRAVQ
+	VPSRAVW
+	VPSRAW
+	VPSRLD
+	VPSRLDQ
+	VPSRLQ
+	VPSRLVD
+	VPSRLVQ
+	VPSRLVW
+	VPSRLW
+	VPSUBB
+	VPSUBD
+	VPSUBQ
+	VPSUBSB
+	VPSUBSW
+	VPSUBUSB
+	VPSUBUSW
+	VPSUBW
+	VPTERNLOGD
+	VPTERNLOGQ
+	VPTEST
+	VPTESTMB
+	VPTESTMD
+	VPTESTMQ
+	VPTESTMW
+	VPTESTNMB
+	VPTESTNMD
+	VPTESTNMQ
+	VPTESTNMW
+	VPUNPCKHBW
+	VPUNPCKHDQ
+	VPUNPCKHQDQ
+	VPUNPCKHWD
+	VPUNPCKLBW
+	VPUNPCKLDQ
+	VPUNPCKLQDQ
+	VPUNPCKLWD
+	VPXOR
+	VPXORD
+	VPXORQ
+	VRANGEPD
+	VRANGEPS
+	VRANGESD
+	VRANGESS
+	VRCP14PD
+	VRCP14PS
+	VRCP14SD
+	VRCP14SS
+	VRCP28PD
+	VRCP28PS
+	VRCP28SD
+	VRCP28SS
+	VRCPPS
+	VRCPSS
+	VREDUCEPD
+	VREDUCEPS
+	VREDUCESD
+	VREDUCESS
+	VRNDSCALEPD
+	VRNDSCALEPS
+	VRNDSCALESD
+	VRNDSCALESS
+	VROUNDPD
+	VROUNDPS
+	VROUNDSD
+	VROUNDSS
+	VRSQRT14PD
+	VRSQRT14PS
+	VRSQRT14SD
+	VRSQRT14SS
+	VRSQRT28PD
+	VRSQRT28PS
+	VRSQRT28SD
+	VRSQRT28SS
+	VRSQRTPS
+	VRSQRTSS
+	VSCALEFPD
+	VSCALEFPS
+	VSCALEFSD
+	VSCALEFSS
+	VSCATTERDPD
+	VSCATTERDPS
+	VSCATTERQPD
+	VSCATTERQPS
+	VSHUFF32X4
+	VSHUFF64X2
+	VSHUFI32X4
+	VSHUFI64X2
+	VSHUFPD
+	VSHUFPS
+	VSQRTPD
+	VSQRTPS
+	VSQRTSD
+	VSQRTSS
+	VSTMXCSR
+	VSUBPD
+	VSUBPS
+	VSUBSD
+	VSUBSS
+	VTESTPD
+	VTESTPS
+	VUCOMISD
+	VUCOMISS
+	VUNPCKHPD
+	VUNPCKHPS
+	VUNPCKLPD
+	VUNPCKLPS
+	VXORPD
+	VXORPS
+	VZEROALL
+	VZEROUPPER
+	WBINVD
+	WRFSBASE
+	WRGSBASE
+	WRMSR
+	WRPKRU
+	XABORT
+	XADD
+	XBEGIN
+	XCHG
+	XEND
+	XGETBV
+	XLAT
+	XOR
+	XORPD
+	XORPS
+	XRSTOR
+	XRSTOR64
+	XRSTORS
+	XRSTORS64
+	XSAVE
+	XSAVE64
+	XSAVEC
+	XSAVEC64
+	XSAVEOPT
+	XSAVEOPT64
+	XSAVES
+	XSAVES64
+	XSETBV
+	XTEST
+)
+
+// String returns the lowercase mnemonic, as written in Intel syntax
+func (m Mnemonic) String() string {
+	if int(m) >= len(mnemonicNames) {
+		return fmt.Sprintf("Mnemonic(%d)", m)
+	}
+	return mnemonicNames[m]
+}
+
+var mnemonicNames = [...]string{
+	INVALID:           "invalid",
+	AAA:               "aaa",
+	AAD:               "aad",
+	AAM:               "aam",
+	AAS:               "aas",
+	ADC:               "adc",
+	ADCX:              "adcx",
+	ADD:               "add",
+	ADDPD:             "addpd",
+	ADDPS:             "addps",
+	ADDSD:             "addsd",
+	ADDSS:             "addss",
+	ADDSUBPD:          "addsubpd",
+	ADDSUBPS:          "addsubps",
+	ADOX:              "adox",
+	AESDEC:            "aesdec",
+	AESDECLAST:        "aesdeclast",
+	AESENC:            "aesenc",
+	AESENCLAST:        "aesenclast",
+	AESIMC:            "aesimc",
+	AESKEYGENASSIST:   "aeskeygenassist",
+	AND:               "and",
+	ANDN:              "andn",
+	ANDNPD:            "andnpd",
+	ANDNPS:            "andnps",
+	ANDPD:             "andpd",
+	ANDPS:             "andps",
+	ARPL:              "arpl",
+	BEXTR:             "bextr",
+	BLENDPD:           "blendpd",
+	BLENDPS:           "blendps",
+	BLENDVPD:          "blendvpd",
+	BLENDVPS:          "blendvps",
+	BLSI:              "blsi",
+	BLSMSK:            "blsmsk",
+	BLSR:              "blsr",
+	BOUND:             "bound",
+	BSF:               "bsf",
+	BSR:               "bsr",
+	BSWAP:             "bswap",
+	BT:                "bt",
+	BTC:               "btc",
+	BTR:               "btr",
+	BTS:               "bts",
+	BZHI:              "bzhi",
+	CALL:              "call",
+	CBW:               "cbw",
+	CDQ:               "cdq",
+	CDQE:              "cdqe",
+	CLAC:              "clac",
+	CLC:               "clc",
+	CLD:               "cld",
+	CLFLUSH:           "clflush",
+	CLFLUSHOPT:        "clflushopt",
+	CLGI:              "clgi",
+	CLI:               "cli",
+	CLTS:              "clts",
+	CLWB:              "clwb",
+	CLZERO:            "clzero",
+	CMC:               "cmc",
+	CMOVA:             "cmova",
+	CMOVAE:            "cmovae",
+	CMOVB:             "cmovb",
+	CMOVBE:            "cmovbe",
+	CMOVE:             "cmove",
+	CMOVG:             "cmovg",
+	CMOVGE:            "cmovge",
+	CMOVL:             "cmovl",
+	CMOVLE:            "cmovle",
+	CMOVNE:            "cmovne",
+	CMOVNO:            "cmovno",
+	CMOVNP:            "cmovnp",
+	CMOVNS:            "cmovns",
+	CMOVO:             "cmovo",
+	CMOVP:             "cmovp",
+	CMOVS:             "cmovs",
+	CMP:               "cmp",
+	CMPPD:             "cmppd",
+	CMPPS:             "cmpps",
+	CMPSB:             "cmpsb",
+	CMPSD:             "cmpsd",
+	CMPSQ:             "cmpsq",
+	CMPSS:             "cmpss",
+	CMPSW:             "cmpsw",
+	CMPXCHG:           "cmpxchg",
+	CMPXCHG16B:        "cmpxchg16b",
+	CMPXCHG8B:         "cmpxchg8b",
+	COMISD:            "comisd",
+	COMISS:            "comiss",
+	CPUID:             "cpuid",
+	CQO:               "cqo",
+	CRC32:             "crc32",
+	CVTDQ2PD:          "cvtdq2pd",
+	CVTDQ2PS:          "cvtdq2ps",
+	CVTPD2DQ:          "cvtpd2dq",
+	CVTPD2PI:          "cvtpd2pi",
+	CVTPD2PS:          "cvtpd2ps",
+	CVTPI2PD:          "cvtpi2pd",
+	CVTPI2PS:          "cvtpi2ps",
+	CVTPS2DQ:          "cvtps2dq",
+	CVTPS2PD:          "cvtps2pd",
+	CVTPS2PI:          "cvtps2pi",
+	CVTSD2SI:          "cvtsd2si",
+	CVTSD2SS:          "cvtsd2ss",
+	CVTSI2SD:          "cvtsi2sd",
+	CVTSI2SS:          "cvtsi2ss",
+	CVTSS2SD:          "cvtss2sd",
+	CVTSS2SI:          "cvtss2si",
+	CVTTPD2DQ:         "cvttpd2dq",
+	CVTTPD2PI:         "cvttpd2pi",
+	CVTTPS2DQ:         "cvttps2dq",
+	CVTTPS2PI:         "cvttps2pi",
+	CVTTSD2SI:         "cvttsd2si",
+	CVTTSS2SI:         "cvttss2si",
+	CWD:               "cwd",
+	CWDE:              "cwde",
+	DAA:               "daa",
+	DAS:               "das",
+	DEC:               "dec",
+	DIV:               "div",
+	DIVPD:             "divpd",
+	DIVPS:             "divps",
+	DIVSD:             "divsd",
+	DIVSS:             "divss",
+	DPPD:              "dppd",
+	DPPS:              "dpps",
+	EMMS:              "emms",
+	ENCLS:             "encls",
+	ENCLU:             "enclu",
+	ENDBR32:           "endbr32",
+	ENDBR64:           "endbr64",
+	ENTER:             "enter",
+	EXTRACTPS:         "extractps",
+	F2XM1:             "f2xm1",
+	FABS:              "fabs",
+	FADD:              "fadd",
+	FADDP:             "faddp",
+	FBLD:              "fbld",
+	FBSTP:             "fbstp",
+	FCHS:              "fchs",
+	FCMOVB:            "fcmovb",
+	FCMOVBE:           "fcmovbe",
+	FCMOVE:            "fcmove",
+	FCMOVNB:           "fcmovnb",
+	FCMOVNBE:          "fcmovnbe",
+	FCMOVNE:           "fcmovne",
+	FCMOVNU:           "fcmovnu",
+	FCMOVU:            "fcmovu",
+	FCOM:              "fcom",
+	FCOMI:             "fcomi",
+	FCOMIP:            "fcomip",
+	FCOMP:             "fcomp",
+	FCOMPP:            "fcompp",
+	FCOS:              "fcos",
+	FDECSTP:           "fdecstp",
+	FDIV:              "fdiv",
+	FDIVP:             "fdivp",
+	FDIVR:             "fdivr",
+	FDIVRP:            "fdivrp",
+	FEMMS:             "femms",
+	FFREE:             "ffree",
+	FFREEP:            "ffreep",
+	FIADD:             "fiadd",
+	FICOM:             "ficom",
+	FICOMP:            "ficomp",
+	FIDIV:             "fidiv",
+	FIDIVR:            "fidivr",
+	FILD:              "fild",
+	FIMUL:             "fimul",
+	FINCSTP:           "fincstp",
+	FIST:              "fist",
+	FISTP:             "fistp",
+	FISTTP:            "fisttp",
+	FISUB:             "fisub",
+	FISUBR:            "fisubr",
+	FLD:               "fld",
+	FLD1:              "fld1",
+	FLDCW:             "fldcw",
+	FLDENV:            "fldenv",
+	FLDL2E:            "fldl2e",
+	FLDL2T:            "fldl2t",
+	FLDLG2:            "fldlg2",
+	FLDLN2:            "fldln2",
+	FLDPI:             "fldpi",
+	FLDZ:              "fldz",
+	FMUL:              "fmul",
+	FMULP:             "fmulp",
+	FNCLEX:            "fnclex",
+	FNINIT:            "fninit",
+	FNOP:              "fnop",
+	FNSAVE:            "fnsave",
+	FNSTCW:            "fnstcw",
+	FNSTENV:           "fnstenv",
+	FNSTSW:            "fnstsw",
+	FPATAN:            "fpatan",
+	FPREM:             "fprem",
+	FPREM1:            "fprem1",
+	FPTAN:             "fptan",
+	FRNDINT:           "frndint",
+	FRSTOR:            "frstor",
+	FSCALE:            "fscale",
+	FSIN:              "fsin",
+	FSINCOS:           "fsincos",
+	FSQRT:             "fsqrt",
+	FST:               "fst",
+	FSTP:              "fstp",
+	FSUB:              "fsub",
+	FSUBP:             "fsubp",
+	FSUBR:             "fsubr",
+	FSUBRP:            "fsubrp",
+	FTST:              "ftst",
+	FUCOM:             "fucom",
+	FUCOMI:            "fucomi",
+	FUCOMIP:           "fucomip",
+	FUCOMP:            "fucomp",
+	FUCOMPP:           "fucompp",
+	FWAIT:             "fwait",
+	FXAM:              "fxam",
+	FXCH:              "fxch",
+	FXRSTOR:           "fxrstor",
+	FXRSTOR64:         "fxrstor64",
+	FXSAVE:            "fxsave",
+	FXSAVE64:          "fxsave64",
+	FXTRACT:           "fxtract",
+	FYL2X:             "fyl2x",
+	FYL2XP1:           "fyl2xp1",
+	GETSEC:            "getsec",
+	GF2P8AFFINEINVQB:  "gf2p8affineinvqb",
+	GF2P8AFFINEQB:     "gf2p8affineqb",
+	GF2P8MULB:         "gf2p8mulb",
+	HADDPD:            "haddpd",
+	HADDPS:            "haddps",
+	HLT:               "hlt",
+	HSUBPD:            "hsubpd",
+	HSUBPS:            "hsubps",
+	IDIV:              "idiv",
+	IMUL:              "imul",
+	IN:                "in",
+	INC:               "inc",
+	INCSSPD:           "incsspd",
+	INCSSPQ:           "incsspq",
+	INSB:              "insb",
+	INSD:              "insd",
+	INSERTPS:          "insertps",
+	INSW:              "insw",
+	INT:               "int",
+	INT1:              "int1",
+	INT3:              "int3",
+	INTO:              "into",
+	INVD:              "invd",
+	INVEPT:            "invept",
+	INVLPG:            "invlpg",
+	INVLPGA:           "invlpga",
+	INVPCID:           "invpcid",
+	INVVPID:           "invvpid",
+	IRET:              "iret",
+	IRETD:             "iretd",
+	IRETQ:             "iretq",
+	JA:                "ja",
+	JAE:               "jae",
+	JB:                "jb",
+	JBE:               "jbe",
+	JCXZ:              "jcxz",
+	JE:                "je",
+	JECXZ:             "jecxz",
+	JG:                "jg",
+	JGE:               "jge",
+	JL:                "jl",
+	JLE:               "jle",
+	JMP:               "jmp",
+	JNE:               "jne",
+	JNO:               "jno",
+	JNP:               "jnp",
+	JNS:               "jns",
+	JO:                "jo",
+	JP:                "jp",
+	JRCXZ:             "jrcxz",
+	JS:                "js",
+	KADDB:             "kaddb",
+	KADDD:             "kaddd",
+	KADDQ:             "kaddq",
+	KADDW:             "kaddw",
+	KANDB:             "kandb",
+	KANDD:             "kandd",
+	KANDNB:            "kandnb",
+	KANDND:            "kandnd",
+	KANDNQ:            "kandnq",
+	KANDNW:            "kandnw",
+	KANDQ:             "kandq",
+	KANDW:             "kandw",
+	KMOVB:             "kmovb",
+	KMOVD:             "kmovd",
+	KMOVQ:             "kmovq",
+	KMOVW:             "kmovw",
+	KNOTB:             "knotb",
+	KNOTD:             "knotd",
+	KNOTQ:             "knotq",
+	KNOTW:             "knotw",
+	KORB:              "korb",
+	KORD:              "kord",
+	KORQ:              "korq",
+	KORTESTB:          "kortestb",
+	KORTESTD:          "kortestd",
+	KORTESTQ:          "kortestq",
+	KORTESTW:          "kortestw",
+	KORW:              "korw",
+	KSHIFTLB:          "kshiftlb",
+	KSHIFTLD:          "kshiftld",
+	KSHIFTLQ:          "kshiftlq",
+	KSHIFTLW:          "kshiftlw",
+	KSHIFTRB:          "kshiftrb",
+	KSHIFTRD:          "kshiftrd",
+	KSHIFTRQ:          "kshiftrq",
+	KSHIFTRW:          "kshiftrw",
+	KTESTB:            "ktestb",
+	KTESTD:            "ktestd",
+	KTESTQ:            "ktestq",
+	KTESTW:            "ktestw",
+	KUNPCKBW:          "kunpckbw",
+	KUNPCKDQ:          "kunpckdq",
+	KUNPCKWD:          "kunpckwd",
+	KXNORB:            "kxnorb",
+	KXNORD:            "kxnord",
+	KXNORQ:            "kxnorq",
+	KXNORW:            "kxnorw",
+	KXORB:             "kxorb",
+	KXORD:             "kxord",
+	KXORQ:             "kxorq",
+	KXORW:             "kxorw",
+	LAHF:              "lahf",
+	LAR:               "lar",
+	LDDQU:             "lddqu",
+	LDMXCSR:           "ldmxcsr",
+	LDS:               "lds",
+	LEA:               "lea",
+	LEAVE:             "leave",
+	LES:               "les",
+	LFENCE:            "lfence",
+	LFS:               "lfs",
+	LGDT:              "lgdt",
+	LGS:               "lgs",
+	LIDT:              "lidt",
+	LLDT:              "lldt",
+	LMSW:              "lmsw",
+	LODSB:             "lodsb",
+	LODSD:             "lodsd",
+	LODSQ:             "lodsq",
+	LODSW:             "lodsw",
+	LOOP:              "loop",
+	LOOPE:             "loope",
+	LOOPNE:            "loopne",
+	LSL:               "lsl",
+	LSS:               "lss",
+	LTR:               "ltr",
+	LZCNT:             "lzcnt",
+	MASKMOVDQU:        "maskmovdqu",
+	MASKMOVQ:          "maskmovq",
+	MAXPD:             "maxpd",
+	MAXPS:             "maxps",
+	MAXSD:             "maxsd",
+	MAXSS:             "maxss",
+	MFENCE:            "mfence",
+	MINPD:             "minpd",
+	MINPS:             "minps",
+	MINSD:             "minsd",
+	MINSS:             "minss",
+	MONITOR:           "monitor",
+	MONITORX:          "monitorx",
+	MOV:               "mov",
+	MOVAPD:            "movapd",
+	MOVAPS:            "movaps",
+	MOVBE:             "movbe",
+	MOVD:              "movd",
+	MOVDDUP:           "movddup",
+	MOVDQ2Q:           "movdq2q",
+	MOVDQA:            "movdqa",
+	MOVDQU:            "movdqu",
+	MOVHLPS:           "movhlps",
+	MOVHPD:            "movhpd",
+	MOVHPS:            "movhps",
+	MOVLHPS:           "movlhps",
+	MOVLPD:            "movlpd",
+	MOVLPS:            "movlps",
+	MOVMSKPD:          "movmskpd",
+	MOVMSKPS:          "movmskps",
+	MOVNTDQ:           "movntdq",
+	MOVNTDQA:          "movntdqa",
+	MOVNTI:            "movnti",
+	MOVNTPD:           "movntpd",
+	MOVNTPS:           "movntps",
+	MOVNTQ:            "movntq",
+	MOVQ:              "movq",
+	MOVQ2DQ:           "movq2dq",
+	MOVSB:             "movsb",
+	MOVSD:             "movsd",
+	MOVSHDUP:          "movshdup",
+	MOVSLDUP:          "movsldup",
+	MOVSQ:             "movsq",
+	MOVSS:             "movss",
+	MOVSW:             "movsw",
+	MOVSX:             "movsx",
+	MOVSXD:            "movsxd",
+	MOVUPD:            "movupd",
+	MOVUPS:            "movups",
+	MOVZX:             "movzx",
+	MPSADBW:           "mpsadbw",
+	MUL:               "mul",
+	MULPD:             "mulpd",
+	MULPS:             "mulps",
+	MULSD:             "mulsd",
+	MULSS:             "mulss",
+	MULX:              "mulx",
+	MWAIT:             "mwait",
+	MWAITX:            "mwaitx",
+	NEG:               "neg",
+	NOP:               "nop",
+	NOT:               "not",
+	OR:                "or",
+	ORPD:              "orpd",
+	ORPS:              "orps",
+	OUT:               "out",
+	OUTSB:             "outsb",
+	OUTSD:             "outsd",
+	OUTSW:             "outsw",
+	PABSB:             "pabsb",
+	PABSD:             "pabsd",
+	PABSW:             "pabsw",
+	PACKSSDW:          "packssdw",
+	PACKSSWB:          "packsswb",
+	PACKUSDW:          "packusdw",
+	PACKUSWB:          "packuswb",
+	PADDB:             "paddb",
+	PADDD:             "paddd",
+	PADDQ:             "paddq",
+	PADDSB:            "paddsb",
+	PADDSW:            "paddsw",
+	PADDUSB:           "paddusb",
+	PADDUSW:           "paddusw",
+	PADDW:             "paddw",
+	PALIGNR:           "palignr",
+	PAND:              "pand",
+	PANDN:             "pandn",
+	PAUSE:             "pause",
+	PAVGB:             "pavgb",
+	PAVGW:             "pavgw",
+	PBLENDVB:          "pblendvb",
+	PBLENDW:           "pblendw",
+	PCLMULQDQ:         "pclmulqdq",
+	PCMPEQB:           "pcmpeqb",
+	PCMPEQD:           "pcmpeqd",
+	PCMPEQQ:           "pcmpeqq",
+	PCMPEQW:           "pcmpeqw",
+	PCMPESTRI:         "pcmpestri",
+	PCMPESTRM:         "pcmpestrm",
+	PCMPGTB:           "pcmpgtb",
+	PCMPGTD:           "pcmpgtd",
+	PCMPGTQ:           "pcmpgtq",
+	PCMPGTW:           "pcmpgtw",
+	PCMPISTRI:         "pcmpistri",
+	PCMPISTRM:         "pcmpistrm",
+	PDEP:              "pdep",
+	PEXT:              "pext",
+	PEXTRB:            "pextrb",
+	PEXTRD:            "pextrd",
+	PEXTRQ:            "pextrq",
+	PEXTRW:            "pextrw",
+	PHADDD:            "phaddd",
+	PHADDSW:           "phaddsw",
+	PHADDW:            "phaddw",
+	PHMINPOSUW:        "phminposuw",
+	PHSUBD:            "phsubd",
+	PHSUBSW:           "phsubsw",
+	PHSUBW:            "phsubw",
+	PINSRB:            "pinsrb",
+	PINSRD:            "pinsrd",
+	PINSRQ:            "pinsrq",
+	PINSRW:            "pinsrw",
+	PMADDUBSW:         "pmaddubsw",
+	PMADDWD:           "pmaddwd",
+	PMAXSB:            "pmaxsb",
+	PMAXSD:            "pmaxsd",
+	PMAXSW:            "pmaxsw",
+	PMAXUB:            "pmaxub",
+	PMAXUD:            "pmaxud",
+	PMAXUW:            "pmaxuw",
+	PMINSB:            "pminsb",
+	PMINSD:            "pminsd",
+	PMINSW:            "pminsw",
+	PMINUB:            "pminub",
+	PMINUD:            "pminud",
+	PMINUW:            "pminuw",
+	PMOVMSKB:          "pmovmskb",
+	PMOVSXBD:          "pmovsxbd",
+	PMOVSXBQ:          "pmovsxbq",
+	PMOVSXBW:          "pmovsxbw",
+	PMOVSXDQ:          "pmovsxdq",
+	PMOVSXWD:          "pmovsxwd",
+	PMOVSXWQ:          "pmovsxwq",
+	PMOVZXBD:          "pmovzxbd",
+	PMOVZXBQ:          "pmovzxbq",
+	PMOVZXBW:          "pmovzxbw",
+	PMOVZXDQ:          "pmovzxdq",
+	PMOVZXWD:          "pmovzxwd",
+	PMOVZXWQ:          "pmovzxwq",
+	PMULDQ:            "pmuldq",
+	PMULHRSW:          "pmulhrsw",
+	PMULHUW:           "pmulhuw",
+	PMULHW:            "pmulhw",
+	PMULLD:            "pmulld",
+	PMULLW:            "pmullw",
+	PMULUDQ:           "pmuludq",
+	POP:               "pop",
+	POPA:              "popa",
+	POPAD:             "popad",
+	POPCNT:            "popcnt",
+	POPF:              "popf",
+	POPFD:             "popfd",
+	POPFQ:             "popfq",
+	POR:               "por",
+	PREFETCH:          "prefetch",
+	PREFETCHNTA:       "prefetchnta",
+	PREFETCHT0:        "prefetcht0",
+	PREFETCHT1:        "prefetcht1",
+	PREFETCHT2:        "prefetcht2",
+	PREFETCHW:         "prefetchw",
+	PREFETCHWT1:       "prefetchwt1",
+	PSADBW:            "psadbw",
+	PSHUFB:            "pshufb",
+	PSHUFD:            "pshufd",
+	PSHUFHW:           "pshufhw",
+	PSHUFLW:           "pshuflw",
+	PSHUFW:            "pshufw",
+	PSIGNB:            "psignb",
+	PSIGND:            "psignd",
+	PSIGNW:            "psignw",
+	PSLLD:             "pslld",
+	PSLLDQ:            "pslldq",
+	PSLLQ:             "psllq",
+	PSLLW:             "psllw",
+	PSRAD:             "psrad",
+	PSRAW:             "psraw",
+	PSRLD:             "psrld",
+	PSRLDQ:            "psrldq",
+	PSRLQ:             "psrlq",
+	PSRLW:             "psrlw",
+	PSUBB:             "psubb",
+	PSUBD:             "psubd",
+	PSUBQ:             "psubq",
+	PSUBSB:            "psubsb",
+	PSUBSW:            "psubsw",
+	PSUBUSB:           "psubusb",
+	PSUBUSW:           "psubusw",
+	PSUBW:             "psubw",
+	PTEST:             "ptest",
+	PUNPCKHBW:         "punpckhbw",
+	PUNPCKHDQ:         "punpckhdq",
+	PUNPCKHQDQ:        "punpckhqdq",
+	PUNPCKHWD:         "punpckhwd",
+	PUNPCKLBW:         "punpcklbw",
+	PUNPCKLDQ:         "punpckldq",
+	PUNPCKLQDQ:        "punpcklqdq",
+	PUNPCKLWD:         "punpcklwd",
+	PUSH:              "push",
+	PUSHA:             "pusha",
+	PUSHAD:            "pushad",
+	PUSHF:             "pushf",
+	PUSHFD:            "pushfd",
+	PUSHFQ:            "pushfq",
+	PXOR:              "pxor",
+	RCL:               "rcl",
+	RCPPS:             "rcpps",
+	RCPSS:             "rcpss",
+	RCR:               "rcr",
+	RDFSBASE:          "rdfsbase",
+	RDGSBASE:          "rdgsbase",
+	RDMSR:             "rdmsr",
+	RDPID:             "rdpid",
+	RDPKRU:            "rdpkru",
+	RDPMC:             "rdpmc",
+	RDRAND:            "rdrand",
+	RDSEED:            "rdseed",
+	RDTSC:             "rdtsc",
+	RDTSCP:            "rdtscp",
+	RET:               "ret",
+	RETF:              "retf",
+	ROL:               "rol",
+	ROR:               "ror",
+	RORX:              "rorx",
+	ROUNDPD:           "roundpd",
+	ROUNDPS:           "roundps",
+	ROUNDSD:           "roundsd",
+	ROUNDSS:           "roundss",
+	RSM:               "rsm",
+	RSQRTPS:           "rsqrtps",
+	RSQRTSS:           "rsqrtss",
+	SAHF:              "sahf",
+	SAL:               "sal",
+	SALC:              "salc",
+	SAR:               "sar",
+	SARX:              "sarx",
+	SBB:               "sbb",
+	SCASB:             "scasb",
+	SCASD:             "scasd",
+	SCASQ:             "scasq",
+	SCASW:             "scasw",
+	SERIALIZE:         "serialize",
+	SETA:              "seta",
+	SETAE:             "setae",
+	SETB:              "setb",
+	SETBE:             "setbe",
+	SETE:              "sete",
+	SETG:              "setg",
+	SETGE:             "setge",
+	SETL:              "setl",
+	SETLE:             "setle",
+	SETNE:             "setne",
+	SETNO:             "setno",
+	SETNP:             "setnp",
+	SETNS:             "setns",
+	SETO:              "seto",
+	SETP:              "setp",
+	SETS:              "sets",
+	SFENCE:            "sfence",
+	SGDT:              "sgdt",
+	SHA1MSG1:          "sha1msg1",
+	SHA1MSG2:          "sha1msg2",
+	SHA1NEXTE:         "sha1nexte",
+	SHA1RNDS4:         "sha1rnds4",
+	SHA256MSG1:        "sha256msg1",
+	SHA256MSG2:        "sha256msg2",
+	SHA256RNDS2:       "sha256rnds2",
+	SHL:               "shl",
+	SHLD:              "shld",
+	SHLX:              "shlx",
+	SHR:               "shr",
+	SHRD:              "shrd",
+	SHRX:              "shrx",
+	SHUFPD:            "shufpd",
+	SHUFPS:            "shufps",
+	SIDT:              "sidt",
+	SKINIT:            "skinit",
+	SLDT:              "sldt",
+	SMSW:              "smsw",
+	SQRTPD:            "sqrtpd",
+	SQRTPS:            "sqrtps",
+	SQRTSD:            "sqrtsd",
+	SQRTSS:            "sqrtss",
+	STAC:              "stac",
+	STC:               "stc",
+	STD:               "std",
+	STGI:              "stgi",
+	STI:               "sti",
+	STMXCSR:           "stmxcsr",
+	STOSB:             "stosb",
+	STOSD:             "stosd",
+	STOSQ:             "stosq",
+	STOSW:             "stosw",
+	STR:               "str",
+	SUB:               "sub",
+	SUBPD:             "subpd",
+	SUBPS:             "subps",
+	SUBSD:             "subsd",
+	SUBSS:             "subss",
+	SWAPGS:            "swapgs",
+	SYSCALL:           "syscall",
+	SYSENTER:          "sysenter",
+	SYSEXIT:           "sysexit",
+	SYSRET:            "sysret",
+	TEST:              "test",
+	TPAUSE:            "tpause",
+	TZCNT:             "tzcnt",
+	UCOMISD:           "ucomisd",
+	UCOMISS:           "ucomiss",
+	UD0:               "ud0",
+	UD1:               "ud1",
+	UD2:               "ud2",
+	UMONITOR:          "umonitor",
+	UMWAIT:            "umwait",
+	UNPCKHPD:          "unpckhpd",
+	UNPCKHPS:          "unpckhps",
+	UNPCKLPD:          "unpcklpd",
+	UNPCKLPS:          "unpcklps",
+	VADDPD:            "vaddpd",
+	VADDPS:            "vaddps",
+	VADDSD:            "vaddsd",
+	VADDSS:            "vaddss",
+	VADDSUBPD:         "vaddsubpd",
+	VADDSUBPS:         "vaddsubps",
+	VAESDEC:           "vaesdec",
+	VAESDECLAST:       "vaesdeclast",
+	VAESENC:           "vaesenc",
+	VAESENCLAST:       "vaesenclast",
+	VAESIMC:           "vaesimc",
+	VAESKEYGENASSIST:  "vaeskeygenassist",
+	VALIGND:           "valignd",
+	VALIGNQ:           "valignq",
+	VANDNPD:           "vandnpd",
+	VANDNPS:           "vandnps",
+	VANDPD:            "vandpd",
+	VANDPS:            "vandps",
+	VBLENDMPD:         "vblendmpd",
+	VBLENDMPS:         "vblendmps",
+	VBLENDPD:          "vblendpd",
+	VBLENDPS:          "vblendps",
+	VBLENDVPD:         "vblendvpd",
+	VBLENDVPS:         "vblendvps",
+	VBROADCASTF128:    "vbroadcastf128",
+	VBROADCASTF32X2:   "vbroadcastf32x2",
+	VBROADCASTF32X4:   "vbroadcastf32x4",
+	VBROADCASTF32X8:   "vbroadcastf32x8",
+	VBROADCASTF64X2:   "vbroadcastf64x2",
+	VBROADCASTF64X4:   "vbroadcastf64x4",
+	VBROADCASTI128:    "vbroadcasti128",
+	VBROADCASTI32X2:   "vbroadcasti32x2",
+	VBROADCASTI32X4:   "vbroadcasti32x4",
+	VBROADCASTI32X8:   "vbroadcasti32x8",
+	VBROADCASTI64X2:   "vbroadcasti64x2",
+	VBROADCASTI64X4:   "vbroadcasti64x4",
+	VBROADCASTSD:      "vbroadcastsd",
+	VBROADCASTSS:      "vbroadcastss",
+	VCMPPD:            "vcmppd",
+	VCMPPS:            "vcmpps",
+	VCMPSD:            "vcmpsd",
+	VCMPSS:            "vcmpss",
+	VCOMISD:           "vcomisd",
+	VCOMISS:           "vcomiss",
+	VCOMPRESSPD:       "vcompresspd",
+	VCOMPRESSPS:       "vcompressps",
+	VCVTDQ2PD:         "vcvtdq2pd",
+	VCVTDQ2PS:         "vcvtdq2ps",
+	VCVTPD2DQ:         "vcvtpd2dq",
+	VCVTPD2PS:         "vcvtpd2ps",
+	VCVTPD2QQ:         "vcvtpd2qq",
+	VCVTPD2UDQ:        "vcvtpd2udq",
+	VCVTPD2UQQ:        "vcvtpd2uqq",
+	VCVTPH2PS:         "vcvtph2ps",
+	VCVTPS2DQ:         "vcvtps2dq",
+	VCVTPS2PD:         "vcvtps2pd",
+	VCVTPS2PH:         "vcvtps2ph",
+	VCVTPS2QQ:         "vcvtps2qq",
+	VCVTPS2UDQ:        "vcvtps2udq",
+	VCVTPS2UQQ:        "vcvtps2uqq",
+	VCVTQQ2PD:         "vcvtqq2pd",
+	VCVTQQ2PS:         "vcvtqq2ps",
+	VCVTSD2SI:         "vcvtsd2si",
+	VCVTSD2SS:         "vcvtsd2ss",
+	VCVTSD2USI:        "vcvtsd2usi",
+	VCVTSI2SD:         "vcvtsi2sd",
+	VCVTSI2SS:         "vcvtsi2ss",
+	VCVTSS2SD:         "vcvtss2sd",
+	VCVTSS2SI:         "vcvtss2si",
+	VCVTSS2USI:        "vcvtss2usi",
+	VCVTTPD2DQ:        "vcvttpd2dq",
+	VCVTTPD2QQ:        "vcvttpd2qq",
+	VCVTTPD2UDQ:       "vcvttpd2udq",
+	VCVTTPD2UQQ:       "vcvttpd2uqq",
+	VCVTTPS2DQ:        "vcvttps2dq",
+	VCVTTPS2QQ:        "vcvttps2qq",
+	VCVTTPS2UDQ:       "vcvttps2udq",
+	VCVTTPS2UQQ:       "vcvttps2uqq",
+	VCVTTSD2SI:        "vcvttsd2si",
+	VCVTTSD2USI:       "vcvttsd2usi",
+	VCVTTSS2SI:        "vcvttss2si",
+	VCVTTSS2USI:       "vcvttss2usi",
+	VCVTUDQ2PD:        "vcvtudq2pd",
+	VCVTUDQ2PS:        "vcvtudq2ps",
+	VCVTUQQ2PD:        "vcvtuqq2pd",
+	VCVTUQQ2PS:        "vcvtuqq2ps",
+	VCVTUSI2SD:        "vcvtusi2sd",
+	VCVTUSI2SS:        "vcvtusi2ss",
+	VDBPSADBW:         "vdbpsadbw",
+	VDIVPD:            "vdivpd",
+	VDIVPS:            "vdivps",
+	VDIVSD:            "vdivsd",
+	VDIVSS:            "vdivss",
+	VDPPD:             "vdppd",
+	VDPPS:             "vdpps",
+	VERR:              "verr",
+	VERW:              "verw",
+	VEXP2PD:           "vexp2pd",
+	VEXP2PS:           "vexp2ps",
+	VEXPANDPD:         "vexpandpd",
+	VEXPANDPS:         "vexpandps",
+	VEXTRACTF128:      "vextractf128",
+	VEXTRACTF32X4:     "vextractf32x4",
+	VEXTRACTF32X8:     "vextractf32x8",
+	VEXTRACTF64X2:     "vextractf64x2",
+	VEXTRACTF64X4:     "vextractf64x4",
+	VEXTRACTI128:      "vextracti128",
+	VEXTRACTI32X4:     "vextracti32x4",
+	VEXTRACTI32X8:     "vextracti32x8",
+	VEXTRACTI64X2:     "vextracti64x2",
+	VEXTRACTI64X4:     "vextracti64x4",
+	VEXTRACTPS:        "vextractps",
+	VFIXUPIMMPD:       "vfixupimmpd",
+	VFIXUPIMMPS:       "vfixupimmps",
+	VFIXUPIMMSD:       "vfixupimmsd",
+	VFIXUPIMMSS:       "vfixupimmss",
+	VFMADD132PD:       "vfmadd132pd",
+	VFMADD132PS:       "vfmadd132ps",
+	VFMADD132SD:       "vfmadd132sd",
+	VFMADD132SS:       "vfmadd132ss",
+	VFMADD213PD:       "vfmadd213pd",
+	VFMADD213PS:       "vfmadd213ps",
+	VFMADD213SD:       "vfmadd213sd",
+	VFMADD213SS:       "vfmadd213ss",
+	VFMADD231PD:       "vfmadd231pd",
+	VFMADD231PS:       "vfmadd231ps",
+	VFMADD231SD:       "vfmadd231sd",
+	VFMADD231SS:       "vfmadd231ss",
+	VFMADDSUB132PD:    "vfmaddsub132pd",
+	VFMADDSUB132PS:    "vfmaddsub132ps",
+	VFMADDSUB213PD:    "vfmaddsub213pd",
+	VFMADDSUB213PS:    "vfmaddsub213ps",
+	VFMADDSUB231PD:    "vfmaddsub231pd",
+	VFMADDSUB231PS:    "vfmaddsub231ps",
+	VFMSUB132PD:       "vfmsub132pd",
+	VFMSUB132PS:       "vfmsub132ps",
+	VFMSUB132SD:       "vfmsub132sd",
+	VFMSUB132SS:       "vfmsub132ss",
+	VFMSUB213PD:       "vfmsub213pd",
+	VFMSUB213PS:       "vfmsub213ps",
+	VFMSUB213SD:       "vfmsub213sd",
+	VFMSUB213SS:       "vfmsub213ss",
+	VFMSUB231PD:       "vfmsub231pd",
+	VFMSUB231PS:       "vfmsub231ps",
+	VFMSUB231SD:       "vfmsub231sd",
+	VFMSUB231SS:       "vfmsub231ss",
+	VFMSUBADD132PD:    "vfmsubadd132pd",
+	VFMSUBADD132PS:    "vfmsubadd132ps",
+	VFMSUBADD213PD:    "vfmsubadd213pd",
+	VFMSUBADD213PS:    "vfmsubadd213ps",
+	VFMSUBADD231PD:    "vfmsubadd231pd",
+	VFMSUBADD231PS:    "vfmsubadd231ps",
+	VFNMADD132PD:      "vfnmadd132pd",
+	VFNMADD132PS:      "vfnmadd132ps",
+	VFNMADD132SD:      "vfnmadd132sd",
+	VFNMADD132SS:      "vfnmadd132ss",
+	VFNMADD213PD:      "vfnmadd213pd",
+	VFNMADD213PS:      "vfnmadd213ps",
+	VFNMADD213SD:      "vfnmadd213sd",
+	VFNMADD213SS:      "vfnmadd213ss",
+	VFNMADD231PD:      "vfnmadd231pd",
+	VFNMADD231PS:      "vfnmadd231ps",
+	VFNMADD231SD:      "vfnmadd231sd",
+	VFNMADD231SS:      "vfnmadd231ss",
+	VFNMSUB132PD:      "vfnmsub132pd",
+	VFNMSUB132PS:      "vfnmsub132ps",
+	VFNMSUB132SD:      "vfnmsub132sd",
+	VFNMSUB132SS:      "vfnmsub132ss",
+	VFNMSUB213PD:      "vfnmsub213pd",
+	VFNMSUB213PS:      "vfnmsub213ps",
+	VFNMSUB213SD:      "vfnmsub213sd",
+	VFNMSUB213SS:      "vfnmsub213ss",
+	VFNMSUB231PD:      "vfnmsub231pd",
+	VFNMSUB231PS:      "vfnmsub231ps",
+	VFNMSUB231SD:      "vfnmsub231sd",
+	VFNMSUB231SS:      "vfnmsub231ss",
+	VFPCLASSPD:        "vfpclasspd",
+	VFPCLASSPS:        "vfpclassps",
+	VFPCLASSSD:        "vfpclasssd",
+	VFPCLASSSS:        "vfpclassss",
+	VGATHERDPD:        "vgatherdpd",
+	VGATHERDPS:        "vgatherdps",
+	VGATHERQPD:        "vgatherqpd",
+	VGATHERQPS:        "vgatherqps",
+	VGETEXPPD:         "vgetexppd",
+	VGETEXPPS:         "vgetexpps",
+	VGETEXPSD:         "vgetexpsd",
+	VGETEXPSS:         "vgetexpss",
+	VGETMANTPD:        "vgetmantpd",
+	VGETMANTPS:        "vgetmantps",
+	VGETMANTSD:        "vgetmantsd",
+	VGETMANTSS:        "vgetmantss",
+	VGF2P8AFFINEINVQB: "vgf2p8affineinvqb",
+	VGF2P8AFFINEQB:    "vgf2p8affineqb",
+	VGF2P8MULB:        "vgf2p8mulb",
+	VHADDPD:           "vhaddpd",
+	VHADDPS:           "vhaddps",
+	VHSUBPD:           "vhsubpd",
+	VHSUBPS:           "vhsubps",
+	VINSERTF128:       "vinsertf128",
+	VINSERTF32X4:      "vinsertf32x4",
+	VINSERTF32X8:      "vinsertf32x8",
+	VINSERTF64X2:      "vinsertf64x2",
+	VINSERTF64X4:      "vinsertf64x4",
+	VINSERTI128:       "vinserti128",
+	VINSERTI32X4:      "vinserti32x4",
+	VINSERTI32X8:      "vinserti32x8",
+	VINSERTI64X2:      "vinserti64x2",
+	VINSERTI64X4:      "vinserti64x4",
+	VINSERTPS:         "vinsertps",
+	VLDDQU:            "vlddqu",
+	VLDMXCSR:          "vldmxcsr",
+	VMASKMOVDQU:       "vmaskmovdqu",
+	VMASKMOVPD:        "vmaskmovpd",
+	VMASKMOVPS:        "vmaskmovps",
+	VMAXPD:            "vmaxpd",
+	VMAXPS:            "vmaxps",
+	VMAXSD:            "vmaxsd",
+	VMAXSS:            "vmaxss",
+	VMCALL:            "vmcall",
+	VMCLEAR:           "vmclear",
+	VMFUNC:            "vmfunc",
+	VMINPD:            "vminpd",
+	VMINPS:            "vminps",
+	VMINSD:            "vminsd",
+	VMINSS:            "vminss",
+	VMLAUNCH:          "vmlaunch",
+	VMLOAD:            "vmload",
+	VMMCALL:           "vmmcall",
+	VMOVAPD:           "vmovapd",
+	VMOVAPS:           "vmovaps",
+	VMOVD:             "vmovd",
+	VMOVDDUP:          "vmovddup",
+	VMOVDQA:           "vmovdqa",
+	VMOVDQA32:         "vmovdqa32",
+	VMOVDQA64:         "vmovdqa64",
+	VMOVDQU:           "vmovdqu",
+	VMOVDQU16:         "vmovdqu16",
+	VMOVDQU32:         "vmovdqu32",
+	VMOVDQU64:         "vmovdqu64",
+	VMOVDQU8:          "vmovdqu8",
+	VMOVHLPS:          "vmovhlps",
+	VMOVHPD:           "vmovhpd",
+	VMOVHPS:           "vmovhps",
+	VMOVLHPS:          "vmovlhps",
+	VMOVLPD:           "vmovlpd",
+	VMOVLPS:           "vmovlps",
+	VMOVMSKPD:         "vmovmskpd",
+	VMOVMSKPS:         "vmovmskps",
+	VMOVNTDQ:          "vmovntdq",
+	VMOVNTDQA:         "vmovntdqa",
+	VMOVNTPD:          "vmovntpd",
+	VMOVNTPS:          "vmovntps",
+	VMOVQ:             "vmovq",
+	VMOVSD:            "vmovsd",
+	VMOVSHDUP:         "vmovshdup",
+	VMOVSLDUP:         "vmovsldup",
+	VMOVSS:            "vmovss",
+	VMOVUPD:           "vmovupd",
+	VMOVUPS:           "vmovups",
+	VMPSADBW:          "vmpsadbw",
+	VMPTRLD:           "vmptrld",
+	VMPTRST:           "vmptrst",
+	VMREAD:            "vmread",
+	VMRESUME:          "vmresume",
+	VMRUN:             "vmrun",
+	VMSAVE:            "vmsave",
+	VMULPD:            "vmulpd",
+	VMULPS:            "vmulps",
+	VMULSD:            "vmulsd",
+	VMULSS:            "vmulss",
+	VMWRITE:           "vmwrite",
+	VMXOFF:            "vmxoff",
+	VMXON:             "vmxon",
+	VORPD:             "vorpd",
+	VORPS:             "vorps",
+	VPABSB:            "vpabsb",
+	VPABSD:            "vpabsd",
+	VPABSQ:            "vpabsq",
+	VPABSW:            "vpabsw",
+	VPACKSSDW:         "vpackssdw",
+	VPACKSSWB:         "vpacksswb",
+	VPACKUSDW:         "vpackusdw",
+	VPACKUSWB:         "vpackuswb",
+	VPADDB:            "vpaddb",
+	VPADDD:            "vpaddd",
+	VPADDQ:            "vpaddq",
+	VPADDSB:           "vpaddsb",
+	VPADDSW:           "vpaddsw",
+	VPADDUSB:          "vpaddusb",
+	VPADDUSW:          "vpaddusw",
+	VPADDW:            "vpaddw",
+	VPALIGNR:          "vpalignr",
+	VPAND:             "vpand",
+	VPANDD:            "vpandd",
+	VPANDN:            "vpandn",
+	VPANDND:           "vpandnd",
+	VPANDNQ:           "vpandnq",
+	VPANDQ:            "vpandq",
+	VPAVGB:            "vpavgb",
+	VPAVGW:            "vpavgw",
+	VPBLENDD:          "vpblendd",
+	VPBLENDMB:         "vpblendmb",
+	VPBLENDMD:         "vpblendmd",
+	VPBLENDMQ:         "vpblendmq",
+	VPBLENDMW:         "vpblendmw",
+	VPBLENDVB:         "vpblendvb",
+	VPBLENDW:          "vpblendw",
+	VPBROADCASTB:      "vpbroadcastb",
+	VPBROADCASTD:      "vpbroadcastd",
+	VPBROADCASTMB2Q:   "vpbroadcastmb2q",
+	VPBROADCASTMW2D:   "vpbroadcastmw2d",
+	VPBROADCASTQ:      "vpbroadcastq",
+	VPBROADCASTW:      "vpbroadcastw",
+	VPCLMULQDQ:        "vpclmulqdq",
+	VPCMPB:            "vpcmpb",
+	VPCMPD:            "vpcmpd",
+	VPCMPEQB:          "vpcmpeqb",
+	VPCMPEQD:          "vpcmpeqd",
+	VPCMPEQQ:          "vpcmpeqq",
+	VPCMPEQW:          "vpcmpeqw",
+	VPCMPESTRI:        "vpcmpestri",
+	VPCMPESTRM:        "vpcmpestrm",
+	VPCMPGTB:          "vpcmpgtb",
+	VPCMPGTD:          "vpcmpgtd",
+	VPCMPGTQ:          "vpcmpgtq",
+	VPCMPGTW:          "vpcmpgtw",
+	VPCMPISTRI:        "vpcmpistri",
+	VPCMPISTRM:        "vpcmpistrm",
+	VPCMPQ:            "vpcmpq",
+	VPCMPUB:           "vpcmpub",
+	VPCMPUD:           "vpcmpud",
+	VPCMPUQ:           "vpcmpuq",
+	VPCMPUW:           "vpcmpuw",
+	VPCMPW:            "vpcmpw",
+	VPCOMPRESSB:       "vpcompressb",
+	VPCOMPRESSD:       "vpcompressd",
+	VPCOMPRESSQ:       "vpcompressq",
+	VPCOMPRESSW:       "vpcompressw",
+	VPCONFLICTD:       "vpconflictd",
+	VPCONFLICTQ:       "vpconflictq",
+	VPDPBUSD:          "vpdpbusd",
+	VPDPBUSDS:         "vpdpbusds",
+	VPDPWSSD:          "vpdpwssd",
+	VPDPWSSDS:         "vpdpwssds",
+	VPERM2F128:        "vperm2f128",
+	VPERM2I128:        "vperm2i128",
+	VPERMB:            "vpermb",
+	VPERMD:            "vpermd",
+	VPERMI2B:          "vpermi2b",
+	VPERMI2D:          "vpermi2d",
+	VPERMI2PD:         "vpermi2pd",
+	VPERMI2PS:         "vpermi2ps",
+	VPERMI2Q:          "vpermi2q",
+	VPERMI2W:          "vpermi2w",
+	VPERMILPD:         "vpermilpd",
+	VPERMILPS:         "vpermilps",
+	VPERMPD:           "vpermpd",
+	VPERMPS:           "vpermps",
+	VPERMQ:            "vpermq",
+	VPERMT2B:          "vpermt2b",
+	VPERMT2D:          "vpermt2d",
+	VPERMT2PD:         "vpermt2pd",
+	VPERMT2PS:         "vpermt2ps",
+	VPERMT2Q:          "vpermt2q",
+	VPERMT2W:          "vpermt2w",
+	VPERMW:            "vpermw",
+	VPEXPANDB:         "vpexpandb",
+	VPEXPANDD:         "vpexpandd",
+	VPEXPANDQ:         "vpexpandq",
+	VPEXPANDW:         "vpexpandw",
+	VPEXTRB:           "vpextrb",
+	VPEXTRD:           "vpextrd",
+	VPEXTRQ:           "vpextrq",
+	VPEXTRW:           "vpextrw",
+	VPGATHERDD:        "vpgatherdd",
+	VPGATHERDQ:        "vpgatherdq",
+	VPGATHERQD:        "vpgatherqd",
+	VPGATHERQQ:        "vpgatherqq",
+	VPHADDD:           "vphaddd",
+	VPHADDSW:          "vphaddsw",
+	VPHADDW:           "vphaddw",
+	VPHMINPOSUW:       "vphminposuw",
+	VPHSUBD:           "vphsubd",
+	VPHSUBSW:          "vphsubsw",
+	VPHSUBW:           "vphsubw",
+	VPINSRB:           "vpinsrb",
+	VPINSRD:           "vpinsrd",
+	VPINSRQ:           "vpinsrq",
+	VPINSRW:           "vpinsrw",
+	VPLZCNTD:          "vplzcntd",
+	VPLZCNTQ:          "vplzcntq",
+	VPMADD52HUQ:       "vpmadd52huq",
+	VPMADD52LUQ:       "vpmadd52luq",
+	VPMADDUBSW:        "vpmaddubsw",
+	VPMADDWD:          "vpmaddwd",
+	VPMASKMOVD:        "vpmaskmovd",
+	VPMASKMOVQ:        "vpmaskmovq",
+	VPMAXSB:           "vpmaxsb",
+	VPMAXSD:           "vpmaxsd",
+	VPMAXSQ:           "vpmaxsq",
+	VPMAXSW:           "vpmaxsw",
+	VPMAXUB:           "vpmaxub",
+	VPMAXUD:           "vpmaxud",
+	VPMAXUQ:           "vpmaxuq",
+	VPMAXUW:           "vpmaxuw",
+	VPMINSB:           "vpminsb",
+	VPMINSD:           "vpminsd",
+	VPMINSQ:           "vpminsq",
+	VPMINSW:           "vpminsw",
+	VPMINUB:           "vpminub",
+	VPMINUD:           "vpminud",
+	VPMINUQ:           "vpminuq",
+	VPMINUW:           "vpminuw",
+	VPMOVB2M:          "vpmovb2m",
+	VPMOVD2M:          "vpmovd2m",
+	VPMOVDB:           "vpmovdb",
+	VPMOVDW:           "vpmovdw",
+	VPMOVM2B:          "vpmovm2b",
+	VPMOVM2D:          "vpmovm2d",
+	VPMOVM2Q:          "vpmovm2q",
+	VPMOVM2W:          "vpmovm2w",
+	VPMOVMSKB:         "vpmovmskb",
+	VPMOVQ2M:          "vpmovq2m",
+	VPMOVQB:           "vpmovqb",
+	VPMOVQD:           "vpmovqd",
+	VPMOVQW:           "vpmovqw",
+	VPMOVSDB:          "vpmovsdb",
+	VPMOVSDW:          "vpmovsdw",
+	VPMOVSQB:          "vpmovsqb",
+	VPMOVSQD:          "vpmovsqd",
+	VPMOVSQW:          "vpmovsqw",
+	VPMOVSWB:          "vpmovswb",
+	VPMOVSXBD:         "vpmovsxbd",
+	VPMOVSXBQ:         "vpmovsxbq",
+	VPMOVSXBW:         "vpmovsxbw",
+	VPMOVSXDQ:         "vpmovsxdq",
+	VPMOVSXWD:         "vpmovsxwd",
+	VPMOVSXWQ:         "vpmovsxwq",
+	VPMOVUSDB:         "vpmovusdb",
+	VPMOVUSDW:         "vpmovusdw",
+	VPMOVUSQB:         "vpmovusqb",
+	VPMOVUSQD:         "vpmovusqd",
+	VPMOVUSQW:         "vpmovusqw",
+	VPMOVUSWB:         "vpmovuswb",
+	VPMOVW2M:          "vpmovw2m",
+	VPMOVWB:           "vpmovwb",
+	VPMOVZXBD:         "vpmovzxbd",
+	VPMOVZXBQ:         "vpmovzxbq",
+	VPMOVZXBW:         "vpmovzxbw",
+	VPMOVZXDQ:         "vpmovzxdq",
+	VPMOVZXWD:         "vpmovzxwd",
+	VPMOVZXWQ:         "vpmovzxwq",
+	VPMULDQ:           "vpmuldq",
+	VPMULHRSW:         "vpmulhrsw",
+	VPMULHUW:          "vpmulhuw",
+	VPMULHW:           "vpmulhw",
+	VPMULLD:           "vpmulld",
+	VPMULLQ:           "vpmullq",
+	VPMULLW:           "vpmullw",
+	VPMULTISHIFTQB:    "vpmultishiftqb",
+	VPMULUDQ:          "vpmuludq",
+	VPOPCNTB:          "vpopcntb",
+	VPOPCNTD:          "vpopcntd",
+	VPOPCNTQ:          "vpopcntq",
+	VPOPCNTW:          "vpopcntw",
+	VPOR:              "vpor",
+	VPORD:             "vpord",
+	VPORQ:             "vporq",
+	VPROLD:            "vprold",
+	VPROLQ:            "vprolq",
+	VPROLVD:           "vprolvd",
+	VPROLVQ:           "vprolvq",
+	VPRORD:            "vprord",
+	VPRORQ:            "vprorq",
+	VPRORVD:           "vprorvd",
+	VPRORVQ:           "vprorvq",
+	VPSADBW:           "vpsadbw",
+	VPSCATTERDD:       "vpscatterdd",
+	VPSCATTERDQ:       "vpscatterdq",
+	VPSCATTERQD:       "vpscatterqd",
+	VPSCATTERQQ:       "vpscatterqq",
+	VPSHLDD:           "vpshldd",
+	VPSHLDQ:           "vpshldq",
+	VPSHLDVD:          "vpshldvd",
+	VPSHLDVQ:          "vpshldvq",
+	VPSHLDVW:          "vpshldvw",
+	VPSHLDW:           "vpshldw",
+	VPSHRDD:           "vpshrdd",
+	VPSHRDQ:           "vpshrdq",
+	VPSHRDVD:          "vpshrdvd",
+	VPSHRDVQ:          "vpshrdvq",
+	VPSHRDVW:          "vpshrdvw",
+	VPSHRDW:           "vpshrdw",
+	VPSHUFB:           "vpshufb",
+	VPSHUFBITQMB:      "vpshufbitqmb",
+	VPSHUFD:           "vpshufd",
+	VPSHUFHW:          "vpshufhw",
+	VPSHUFLW:          "vpshuflw",
+	VPSIGNB:           "vpsignb",
+	VPSIGND:           "vpsignd",
+	VPSIGNW:           "vpsignw",
+	VPSLLD:            "vpslld",
+	VPSLLDQ:           "vpslldq",
+	VPSLLQ:            "vpsllq",
+	VPSLLVD:           "vpsllvd",
+	VPSLLVQ:           "vpsllvq",
+	VPSLLVW:           "vpsllvw",
+	VPSLLW:            "vpsllw",
+	VPSRAD:            "vpsrad",
+	VPSRAQ:            "vpsraq",
+	VPSRAVD:           "vpsravd",
+	VPSRAVQ:           "vpsravq",
+	VPSRAVW:           "vpsravw",
+	VPSRAW:            "vpsraw",
+	VPSRLD:            "vpsrld",
+	VPSRLDQ:           "vpsrldq",
+	VPSRLQ:            "vpsrlq",
+	VPSRLVD:           "vpsrlvd",
+	VPSRLVQ:           "vpsrlvq",
+	VPSRLVW:           "vpsrlvw",
+	VPSRLW:            "vpsrlw",
+	VPSUBB:            "vpsubb",
+	VPSUBD:            "vpsubd",
+	VPSUBQ:            "vpsubq",
+	VPSUBSB:           "vpsubsb",
+	VPSUBSW:           "vpsubsw",
+	VPSUBUSB:          "vpsubusb",
+	VPSUBUSW:          "vpsubusw",
+	VPSUBW:            "vpsubw",
+	VPTERNLOGD:        "vpternlogd",
+	VPTERNLOGQ:        "vpternlogq",
+	VPTEST:            "vptest",
+	VPTESTMB:          "vptestmb",
+	VPTESTMD:          "vptestmd",
+	VPTESTMQ:          "vptestmq",
+	VPTESTMW:          "vptestmw",
+	VPTESTNMB:         "vptestnmb",
+	VPTESTNMD:         "vptestnmd",
+	VPTESTNMQ:         "vptestnmq",
+	VPTESTNMW:         "vptestnmw",
+	VPUNPCKHBW:        "vpunpckhbw",
+	VPUNPCKHDQ:        "vpunpckhdq",
+	VPUNPCKHQDQ:       "vpunpckhqdq",
+	VPUNPCKHWD:        "vpunpckhwd",
+	VPUNPCKLBW:        "vpunpcklbw",
+	VPUNPCKLDQ:        "vpunpckldq",
+	VPUNPCKLQDQ:       "vpunpcklqdq",
+	VPUNPCKLWD:        "vpunpcklwd",
+	VPXOR:             "vpxor",
+	VPXORD:            "vpxord",
+	VPXORQ:            "vpxorq",
+	VRANGEPD:          "vrangepd",
+	VRANGEPS:          "vrangeps",
+	VRANGESD:          "vrangesd",
+	VRANGESS:          "vrangess",
+	VRCP14PD:          "vrcp14pd",
+	VRCP14PS:          "vrcp14ps",
+	VRCP14SD:          "vrcp14sd",
+	VRCP14SS:          "vrcp14ss",
+	VRCP28PD:          "vrcp28pd",
+	VRCP28PS:          "vrcp28ps",
+	VRCP28SD:          "vrcp28sd",
+	VRCP28SS:          "vrcp28ss",
+	VRCPPS:            "vrcpps",
+	VRCPSS:            "vrcpss",
+	VREDUCEPD:         "vreducepd",
+	VREDUCEPS:         "vreduceps",
+	VREDUCESD:         "vreducesd",
+	VREDUCESS:         "vreducess",
+	VRNDSCALEPD:       "vrndscalepd",
+	VRNDSCALEPS:       "vrndscaleps",
+	VRNDSCALESD:       "vrndscalesd",
+	VRNDSCALESS:       "vrndscaless",
+	VROUNDPD:          "vroundpd",
+	VROUNDPS:          "vroundps",
+	VROUNDSD:          "vroundsd",
+	VROUNDSS:          "vroundss",
+	VRSQRT14PD:        "vrsqrt14pd",
+	VRSQRT14PS:        "vrsqrt14ps",
+	VRSQRT14SD:        "vrsqrt14sd",
+	VRSQRT14SS:        "vrsqrt14ss",
+	VRSQRT28PD:        "vrsqrt28pd",
+	VRSQRT28PS:        "vrsqrt28ps",
+	VRSQRT28SD:        "vrsqrt28sd",
+	VRSQRT28SS:        "vrsqrt28ss",
+	VRSQRTPS:          "vrsqrtps",
+	VRSQRTSS:          "vrsqrtss",
+	VSCALEFPD:         "vscalefpd",
+	VSCALEFPS:         "vscalefps",
+	VSCALEFSD:         "vscalefsd",
+	VSCALEFSS:         "vscalefss",
+	VSCATTERDPD:       "vscatterdpd",
+	VSCATTERDPS:       "vscatterdps",
+	VSCATTERQPD:       "vscatterqpd",
+	VSCATTERQPS:       "vscatterqps",
+	VSHUFF32X4:        "vshuff32x4",
+	VSHUFF64X2:        "vshuff64x2",
+	VSHUFI32X4:        "vshufi32x4",
+	VSHUFI64X2:        "vshufi64x2",
+	VSHUFPD:           "vshufpd",
+	VSHUFPS:           "vshufps",
+	VSQRTPD:           "vsqrtpd",
+	VSQRTPS:           "vsqrtps",
+	VSQRTSD:           "vsqrtsd",
+	VSQRTSS:           "vsqrtss",
+	VSTMXCSR:          "vstmxcsr",
+	VSUBPD:            "vsubpd",
+	VSUBPS:            "vsubps",
+	VSUBSD:            "vsubsd",
+	VSUBSS:            "vsubss",
+	VTESTPD:           "vtestpd",
+	VTESTPS:           "vtestps",
+	VUCOMISD:          "vucomisd",
+	VUCOMISS:          "vucomiss",
+	VUNPCKHPD:         "vunpckhpd",
+	VUNPCKHPS:         "vunpckhps",
+	VUNPCKLPD:         "vunpcklpd",
+	VUNPCKLPS:         "vunpcklps",
+	VXORPD:            "vxorpd",
+	VXORPS:            "vxorps",
+	VZEROALL:          "vzeroall",
+	VZEROUPPER:        "vzeroupper",
+	WBINVD:            "wbinvd",
+	WRFSBASE:          "wrfsbase",
+	WRGSBASE:          "wrgsbase",
+	WRMSR:             "wrmsr",
+	WRPKRU:            "wrpkru",
+	XABORT:            "xabort",
+	XADD:              "xadd",
+	XBEGIN:            "xbegin",
+	XCHG:              "xchg",
+	XEND:              "xend",
+	XGETBV:            "xgetbv",
+	XLAT:              "xlat",
+	XOR:               "xor",
+	XORPD:             "xorpd",
+	XORPS:             "xorps",
+	XRSTOR:            "xrstor",
+	XRSTOR64:          "xrstor64",
+	XRSTORS:           "xrstors",
+	XRSTORS64:         "xrstors64",
+	XSAVE:             "xsave",
+	XSAVE64:           "xsave64",
+	XSAVEC:            "xsavec",
+	XSAVEC64:          "xsavec64",
+	XSAVEOPT:          "xsaveopt",
+	XSAVEOPT64:        "xsaveopt64",
+	XSAVES:            "xsaves",
+	XSAVES64:          "xsaves64",
+	XSETBV:            "xsetbv",
+	XTEST:             "xtest",
+}
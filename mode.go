@@ -10,8 +10,13 @@ type Mode struct {
 	dtOp2LockOk  int
 	dtOpOnlyMem  int
 	dtOp2OnlyMem int
+	dtOpcodes3A  int
+	dtPrefixes38 int
+	dtPrefixes3A int
 	long         bool
+	short        bool
 	table        []cflag
+	table3       []cflag // Three-byte opcode maps (0F 38 and 0F 3A) and their prefix tables
 }
 
 // IsLong returns true if this mode supports 64-bit instructions
@@ -19,6 +24,17 @@ func (m *Mode) IsLong() bool {
 	return m.long
 }
 
+// Bits returns the default address size of the mode in bits
+func (m *Mode) Bits() int {
+	switch {
+	case m.long:
+		return 64
+	case m.short:
+		return 16
+	}
+	return 32
+}
+
 // Constants for instruction flags in the mode table
 type cflag = byte
 
@@ -43,7 +59,11 @@ var Mode64 = &Mode{
 	dtOp2LockOk:  0x1c6,
 	dtOpOnlyMem:  0x1d8,
 	dtOp2OnlyMem: 0x1e7,
+	dtOpcodes3A:  0x4c,
+	dtPrefixes38: 0x9b,
+	dtPrefixes3A: 0x105,
 	long:         true,
+	table3:       table3,
 	table: []cflag{
 		0xa5, 0xaa, 0xa5, 0xb8, 0xa5, 0xaa, 0xa5, 0xaa, 0xa5, 0xb8, 0xa5, 0xb8, 0xa5, 0xb8, 0xa5,
 		0xb8, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xac, 0xc0, 0xcc, 0xc0, 0xa1, 0xa1,
@@ -94,7 +114,11 @@ var Mode32 = &Mode{
 	dtOp2LockOk:  0x1b9,
 	dtOpOnlyMem:  0x1cb,
 	dtOp2OnlyMem: 0x1da,
+	dtOpcodes3A:  0x4c,
+	dtPrefixes38: 0x9b,
+	dtPrefixes3A: 0x105,
 	long:         false,
+	table3:       table3,
 	table: []cflag{
 		0xa3, 0xa8, 0xa3, 0xa8, 0xa3, 0xa8, 0xa3, 0xa8, 0xa3, 0xa8, 0xa3, 0xa8, 0xa3, 0xa8, 0xa3,
 		0xa8, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xaa, 0xac, 0xaa, 0xb2, 0xaa, 0x9f, 0x9f,
@@ -133,3 +157,49 @@ var Mode32 = &Mode{
 		0xe7, 0x08, 0x00, 0xf0, 0x02, 0x00,
 	},
 }
+
+// Mode16 is the 16-bit mode table, used for real mode and 16-bit protected mode
+var Mode16 = &Mode{
+	dtOpcodes:    0x4a,
+	dtFPUReg:     0xf1,
+	dtFPUModRM:   0xf8,
+	dtPrefixes:   0x130,
+	dtOpLockOk:   0x1a1,
+	dtOp2LockOk:  0x1b9,
+	dtOpOnlyMem:  0x1cb,
+	dtOp2OnlyMem: 0x1da,
+	dtOpcodes3A:  0x4c,
+	dtPrefixes38: 0x9b,
+	dtPrefixes3A: 0x105,
+	long:         false,
+	short:        true,
+	table3:       table3,
+	table:        Mode32.table,
+}
+
+// table3 is the three-byte opcode table shared by all modes
+var table3 = []cflag{
+	0x40, 0x40, 0x40, 0x44, 0x43, 0x48, 0x44, 0x41, 0x40, 0x42, 0x40, 0x44, 0x40, 0x48, 0x40,
+	0x40, 0x42, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44,
+	0x44, 0x44, 0x41, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44,
+	0x44, 0x44, 0x44, 0x44, 0x44, 0x40, 0x48, 0x44, 0x44, 0x45, 0x40, 0x44, 0x44, 0x44, 0x44,
+	0x42, 0x47, 0x42, 0x44, 0x01, 0x01, 0x01, 0x01, 0xff, 0xff, 0xff, 0xff, 0x01, 0x01, 0xff,
+	0x01, 0x40, 0x40, 0x44, 0x44, 0x40, 0x44, 0x40, 0x40, 0x45, 0x40, 0x40, 0x40, 0x40, 0x40,
+	0x40, 0x40, 0x45, 0x47, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x44, 0x40, 0x40, 0x40, 0x40,
+	0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40,
+	0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x4b, 0x40, 0x40, 0x40, 0x41, 0x40, 0x40, 0x40,
+	0x40, 0x40, 0x40, 0x40, 0x40, 0xff, 0xff, 0xff, 0xff, 0x03, 0x03, 0x03, 0x03, 0xff, 0xff,
+	0xff, 0x03, 0xff, 0x03, 0x03, 0x40, 0x40, 0x40, 0x44, 0x48, 0x4c, 0x44, 0x41, 0x4f, 0x51,
+	0x4f, 0x44, 0x4f, 0x4c, 0x4f, 0x4f, 0x51, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44,
+	0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x50, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44,
+	0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x44, 0x55, 0x57, 0x44, 0x44, 0x45,
+	0x5b, 0x44, 0x44, 0x44, 0x44, 0x5f, 0x62, 0x66, 0x44, 0x06, 0x06, 0x06, 0x06, 0x00, 0x00,
+	0x00, 0x00, 0x07, 0x00, 0x00, 0x00, 0x07, 0x07, 0x00, 0x07, 0x07, 0x07, 0x07, 0x00, 0x00,
+	0x0e, 0x0e, 0x0e, 0x0e, 0x00, 0x07, 0x03, 0x03, 0x03, 0x03, 0x04, 0x04, 0x00, 0x00, 0x07,
+	0x02, 0x00, 0x01, 0x0e, 0x00, 0x00, 0x40, 0x40, 0x44, 0x45, 0x40, 0x44, 0x40, 0x40, 0x49,
+	0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x49, 0x4b, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40,
+	0x44, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40,
+	0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x4f, 0x40, 0x40,
+	0x40, 0x41, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x40, 0x00, 0x00, 0x00, 0x00, 0x07,
+	0x07, 0x07, 0x07, 0x06, 0x07, 0x07, 0x07, 0x00, 0x00, 0x00, 0x0e, 0x00, 0x07, 0x07,
+}
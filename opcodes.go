@@ -0,0 +1,444 @@
+package hde
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Encoding classes of the opcode table
+const (
+	encLegacy = iota
+	encVEX
+	encEVEX
+	numEncodings
+)
+
+// Mandatory prefixes, numbered like the VEX/EVEX pp field
+const (
+	mpNone int8 = iota // No mandatory prefix (NP)
+	mp66               // 66
+	mpF3               // F3
+	mpF2               // F2
+	mpAny  int8 = -1   // Prefix is not part of the opcode
+)
+
+// ModR/M mod constraints
+const (
+	modAny int8 = iota
+	modMem      // mod != 3
+	modReg      // mod == 3
+)
+
+// formFlag holds the mode and operand size attributes of an instruction form
+type formFlag uint8
+
+const (
+	ffI64 formFlag = 1 << iota // Invalid in 64-bit mode
+	ffX64                      // Valid in 64-bit mode only
+	ffD64                      // Operand size defaults to 64 bits in 64-bit mode
+	ffF64                      // Operand size is forced to 64 bits in 64-bit mode
+	ffB0                       // REX.B must be clear (90 is NOP, not XCHG r8)
+)
+
+// opForm is a single instruction form of the opcode table
+type opForm struct {
+	key   uint16    // Encoding class, opcode map and opcode
+	mnem  Mnemonic  // Mnemonic of the form
+	mp    int8      // Mandatory prefix, or mpAny
+	modrm int16     // Exact ModR/M byte, or -1
+	reg   int8      // ModR/M reg field, or -1
+	mod   int8      // ModR/M mod constraint
+	w     int8      // REX.W, VEX.W or EVEX.W, or -1
+	l     int8      // VEX.L or EVEX.L'L, or -1
+	osz   uint8     // Effective operand size, or 0
+	asz   uint8     // Effective address size, or 0
+	flags formFlag  // Mode and operand size attributes
+	rank  uint8     // Number of constraints, more specific forms are matched first
+	ops   [4]opSpec // Operands in Intel order
+}
+
+// formKey returns the index of an opcode in the form table
+func formKey(enc int, opmap OpcodeMap, op uint8) uint16 {
+	return uint16(enc)<<10 | uint16(opmap)<<8 | uint16(op)
+}
+
+var (
+	forms     []opForm                     // All forms, sorted by key and rank
+	formIndex [numEncodings<<10 + 1]uint16 // Start of each key in forms
+	formsOnce sync.Once                    // Guards parseForms
+)
+
+// parseForms builds the form table from opcodeTable. It runs on the first
+// mnemonic or operand lookup, so that decoding lengths alone does not pay for it.
+func parseForms() {
+	byName := make(map[string]Mnemonic, len(mnemonicNames))
+	for m, name := range mnemonicNames {
+		byName[name] = Mnemonic(m)
+	}
+	for n, line := range strings.Split(opcodeTable, "\n") {
+		if err := parseForm(line, byName); err != nil {
+			panic(fmt.Sprintf("hde: opcode table line %d: %v", n, err))
+		}
+	}
+	sort.SliceStable(forms, func(i, j int) bool {
+		if forms[i].key != forms[j].key {
+			return forms[i].key < forms[j].key
+		}
+		return forms[i].rank > forms[j].rank
+	})
+	i := 0
+	for key := range formIndex {
+		for i < len(forms) && int(forms[i].key) < key {
+			i++
+		}
+		formIndex[key] = uint16(i)
+	}
+}
+
+// condNames lists the condition code suffixes in encoding order
+var condNames = [16]string{"o", "no", "b", "ae", "e", "ne", "be", "a", "s", "ns", "p", "np", "l", "ge", "le", "g"}
+
+// parseForm parses one line of the opcode table and appends its forms
+func parseForm(line string, byName map[string]Mnemonic) error {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	f := opForm{mp: mpAny, modrm: -1, reg: -1, w: -1, l: -1}
+	encs := []int{encLegacy}
+	deriveLegacy := false
+	switch fields[0] {
+	case "LVE":
+		encs, deriveLegacy = []int{encLegacy, encVEX, encEVEX}, true
+	case "LV":
+		encs, deriveLegacy = []int{encLegacy, encVEX}, true
+	case "VE":
+		encs = []int{encVEX, encEVEX}
+	case "V":
+		encs = []int{encVEX}
+	case "E":
+		encs = []int{encEVEX}
+	default:
+		fields = append([]string{""}, fields...)
+	}
+	fields = fields[1:]
+
+	// Opcode bytes, optionally preceded by the mandatory prefix
+	var (
+		opmap         = MapPrimary
+		op            uint8
+		nop           int
+		plusR, plusCC bool
+	)
+	if len(fields) > 1 {
+		switch fields[0] {
+		case "NP":
+			f.mp, fields = mpNone, fields[1:]
+		case "66":
+			f.mp, fields = mp66, fields[1:]
+		case "F3":
+			f.mp, fields = mpF3, fields[1:]
+		case "F2":
+			f.mp, fields = mpF2, fields[1:]
+		}
+	}
+	for len(fields) > 0 {
+		tok := fields[0]
+		tok, plusR = strings.CutSuffix(tok, "+r")
+		if !plusR {
+			tok, plusCC = strings.CutSuffix(tok, "+cc")
+		}
+		b, err := strconv.ParseUint(tok, 16, 8)
+		if len(tok) != 2 || err != nil || tok != strings.ToLower(tok) {
+			break
+		}
+		fields = fields[1:]
+		switch {
+		case nop == 0 && b == 0x0f:
+			opmap = Map0F
+		case nop == 1 && opmap == Map0F && b == 0x38:
+			opmap = Map0F38
+		case nop == 1 && opmap == Map0F && b == 0x3a:
+			opmap = Map0F3A
+		case opmap == MapPrimary && nop == 0, opmap == Map0F && nop == 1, opmap > Map0F && nop == 2:
+			op = uint8(b)
+		default:
+			f.modrm = int16(b)
+			f.rank += 3
+		}
+		nop++
+		if plusR || plusCC {
+			break
+		}
+	}
+	if f.mp != mpAny {
+		f.rank++
+	}
+
+	// Constraints, followed by the mnemonic and its operands
+constraints:
+	for ; len(fields) > 0; fields = fields[1:] {
+		tok := fields[0]
+		switch {
+		case len(tok) == 2 && tok[0] == '/':
+			f.reg = int8(tok[1] - '0')
+		case tok == "mem":
+			f.mod = modMem
+		case tok == "reg":
+			f.mod = modReg
+		case tok == "W0" || tok == "W1":
+			f.w = int8(tok[1] - '0')
+		case tok == "L0" || tok == "L1" || tok == "L2":
+			f.l = int8(tok[1] - '0')
+		case tok == "o16" || tok == "o32" || tok == "o64":
+			n, _ := strconv.Atoi(tok[1:])
+			f.osz = uint8(n)
+		case tok == "a16" || tok == "a32" || tok == "a64":
+			n, _ := strconv.Atoi(tok[1:])
+			f.asz = uint8(n)
+		case tok == "i64":
+			f.flags |= ffI64
+			continue
+		case tok == "x64":
+			f.flags |= ffX64
+			continue
+		case tok == "d64":
+			f.flags |= ffD64
+			continue
+		case tok == "f64":
+			f.flags |= ffF64
+			continue
+		case tok == "B0":
+			f.flags |= ffB0
+		default:
+			break constraints
+		}
+		f.rank++
+	}
+	if len(fields) == 0 || len(fields) > 2 || nop == 0 {
+		return fmt.Errorf("malformed line %q", line)
+	}
+	var ops []opSpec
+	if len(fields) == 2 {
+		for _, tok := range strings.Split(fields[1], ",") {
+			s, err := parseOperand(tok)
+			if err != nil {
+				return err
+			}
+			ops = append(ops, s)
+		}
+		if len(ops) > len(f.ops) {
+			return fmt.Errorf("too many operands in %q", line)
+		}
+	}
+
+	// Expand the opcode ranges and encodings
+	name := fields[0]
+	count := 1
+	if plusR {
+		count = 8
+	} else if plusCC {
+		count = 16
+	}
+	for i := range count {
+		mname := name
+		if plusCC {
+			mname = strings.Replace(name, "{cc}", condNames[i], 1)
+		}
+		for _, enc := range encs {
+			f := f
+			f.key = formKey(enc, opmap, op+uint8(i))
+			ename := mname
+			if enc == encLegacy && deriveLegacy {
+				ename = strings.TrimPrefix(ename, "v")
+			}
+			m, ok := byName[ename]
+			if !ok {
+				return fmt.Errorf("unknown mnemonic %q", ename)
+			}
+			f.mnem = m
+			n := 0
+			for _, s := range ops {
+				// Legacy forms of VEX instructions have no vvvv operand
+				if enc == encLegacy && deriveLegacy && s.am == amH {
+					continue
+				}
+				f.ops[n] = s
+				n++
+			}
+			forms = append(forms, f)
+		}
+	}
+	return nil
+}
+
+// encoding returns the encoding class of the instruction
+func (insn *Insn) encoding() int {
+	switch {
+	case insn.Flags&HasEVEX != 0:
+		return encEVEX
+	case insn.Flags&HasVEX != 0:
+		return encVEX
+	}
+	return encLegacy
+}
+
+// mandatoryPrefix returns the prefix that selects between forms of the opcode.
+// F2 and F3 take precedence over 66, which then acts as an operand size override.
+func (insn *Insn) mandatoryPrefix() int8 {
+	switch insn.encoding() {
+	case encVEX:
+		return int8(insn.VEX.PP())
+	case encEVEX:
+		return int8(insn.EVEX.PP())
+	}
+	switch {
+	case insn.Flags&HasRepNZ != 0:
+		return mpF2
+	case insn.Flags&HasRep != 0:
+		return mpF3
+	case insn.Flags&HasOpSize != 0:
+		return mp66
+	}
+	return mpNone
+}
+
+// vexW returns the W bit of the REX, VEX or EVEX prefix
+func (insn *Insn) vexW() uint8 {
+	switch insn.encoding() {
+	case encVEX:
+		return insn.VEX.W()
+	case encEVEX:
+		return insn.EVEX.W()
+	}
+	return insn.REX.W()
+}
+
+// vectorLength returns the VEX.L or EVEX.L'L field
+func (insn *Insn) vectorLength() uint8 {
+	switch insn.encoding() {
+	case encVEX:
+		return insn.VEX.L()
+	case encEVEX:
+		return insn.EVEX.LL()
+	}
+	return 0
+}
+
+// operandSize returns the effective operand size for the given form flags
+func (insn *Insn) operandSize(flags formFlag) int {
+	mode := insn.Mode
+	opSize := insn.Flags&HasOpSize != 0
+	switch {
+	case mode.long:
+		switch {
+		case insn.vexW() != 0 && insn.encoding() != encLegacy:
+			return 64
+		case insn.REX.W() != 0, flags&ffF64 != 0:
+			return 64
+		case opSize:
+			return 16
+		case flags&ffD64 != 0:
+			return 64
+		}
+		return 32
+	case opSize != mode.short:
+		return 16
+	}
+	return 32
+}
+
+// OperandSize returns the effective operand size of the instruction in bits.
+// It accounts for the mode, the 66h prefix, REX.W and instructions that default
+// to 64-bit operands in 64-bit mode such as PUSH and near branches.
+func (insn *Insn) OperandSize() int {
+	var flags formFlag
+	if f := insn.form(); f != nil {
+		flags = f.flags
+	}
+	return insn.operandSize(flags)
+}
+
+// AddressSize returns the effective address size of the instruction in bits
+func (insn *Insn) AddressSize() int {
+	mode := insn.Mode
+	addrSize := insn.Flags&HasAddrSize != 0
+	switch {
+	case mode.long:
+		if addrSize {
+			return 32
+		}
+		return 64
+	case addrSize != mode.short:
+		return 16
+	}
+	return 32
+}
+
+// form returns the opcode table entry matching the instruction, or nil
+func (insn *Insn) form() *opForm {
+	if insn.Mode == nil {
+		return nil
+	}
+	formsOnce.Do(parseForms)
+	key := formKey(insn.encoding(), insn.Map(), insn.OpcodeByte())
+	candidates := forms[formIndex[key]:formIndex[key+1]]
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	long := insn.Mode.long
+	mp := insn.mandatoryPrefix()
+	w := int8(insn.vexW())
+	l := int8(insn.vectorLength())
+	hasModRM := insn.Flags&IsModRM != 0
+	for i := range candidates {
+		f := &candidates[i]
+		switch {
+		case f.mp != mpAny && f.mp != mp,
+			f.w >= 0 && f.w != w,
+			f.l >= 0 && f.l != l,
+			f.flags&ffI64 != 0 && long,
+			f.flags&ffX64 != 0 && !long,
+			f.flags&ffB0 != 0 && insn.REX.B() != 0:
+			continue
+		}
+		if f.modrm >= 0 || f.reg >= 0 || f.mod != modAny {
+			if !hasModRM {
+				continue
+			}
+			switch {
+			case f.modrm >= 0 && f.modrm != int16(insn.ModRM),
+				f.reg >= 0 && uint8(f.reg) != insn.ModRM.Reg(),
+				f.mod == modMem && insn.ModRM.Mod() == 3,
+				f.mod == modReg && insn.ModRM.Mod() != 3:
+				continue
+			}
+		}
+		if f.osz != 0 && int(f.osz) != insn.operandSize(f.flags) {
+			continue
+		}
+		if f.asz != 0 && int(f.asz) != insn.AddressSize() {
+			continue
+		}
+		return f
+	}
+	return nil
+}
+
+// Mnemonic returns the mnemonic of the instruction.
+// It is resolved from the opcode, the ModR/M reg extension, the mandatory prefix
+// and the operand size, and is INVALID if the encoding does not name an instruction.
+func (insn *Insn) Mnemonic() Mnemonic {
+	if f := insn.form(); f != nil {
+		return f.mnem
+	}
+	return INVALID
+}
@@ -0,0 +1,550 @@
+package hde
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OperandType identifies the kind of an instruction operand
+type OperandType uint8
+
+const (
+	OpNone OperandType = iota // No operand
+	OpReg                     // Register
+	OpMem                     // Memory reference
+	OpImm                     // Immediate value
+	OpRel                     // Branch displacement relative to the next instruction
+	OpFar                     // Far pointer immediate (selector:offset)
+)
+
+var operandTypeNames = [...]string{
+	OpNone: "None",
+	OpReg:  "Reg",
+	OpMem:  "Mem",
+	OpImm:  "Imm",
+	OpRel:  "Rel",
+	OpFar:  "Far",
+}
+
+// String returns the name of the operand type
+func (t OperandType) String() string {
+	if int(t) >= len(operandTypeNames) {
+		return fmt.Sprintf("OperandType(%d)", t)
+	}
+	return operandTypeNames[t]
+}
+
+// RegClass identifies the register file a register belongs to
+type RegClass uint8
+
+const (
+	ClassNone RegClass = iota // No register
+	ClassGPR                  // General purpose registers
+	ClassIP                   // Instruction pointer, the base of RIP-relative memory operands
+	ClassSeg                  // Segment registers
+	ClassCR                   // Control registers
+	ClassDR                   // Debug registers
+	ClassX87                  // x87 register stack, ST(0) to ST(7)
+	ClassMMX                  // MMX registers
+	ClassXMM                  // 128-bit vector registers
+	ClassYMM                  // 256-bit vector registers
+	ClassZMM                  // 512-bit vector registers
+	ClassMask                 // AVX-512 opmask registers
+)
+
+// Mem describes a memory operand
+type Mem struct {
+	Segment   Segment  // Segment override, or the default segment of the base register
+	Base      Register // Base register, sized by the address size
+	Index     Register // Index register, a vector register for VSIB addressing
+	Scale     uint8    // Scale factor of the index register
+	Disp      int64    // Displacement or absolute offset, with disp8*N scaling applied
+	Broadcast bool     // EVEX embedded broadcast of a single element
+}
+
+// Operand is a decoded instruction operand
+type Operand struct {
+	Type     OperandType // Kind of the operand
+	Size     uint16      // Access size in bits, 0 if the instruction does not define one (LEA)
+	Reg      Register    // Register, for OpReg
+	Mem      Mem         // Memory reference, for OpMem
+	Imm      int64       // Immediate value, branch displacement or far pointer offset
+	Selector uint16      // Far pointer segment selector, for OpFar
+}
+
+// addrMethod is the addressing method of an operand in the opcode table
+type addrMethod uint8
+
+const (
+	amNone  addrMethod = iota
+	amE                // ModR/M r/m, general purpose register or memory
+	amG                // ModR/M reg, general purpose register
+	amM                // ModR/M r/m, memory only
+	amR                // ModR/M r/m, general purpose register only
+	amI                // Immediate
+	amIs               // Immediate, sign-extended to the operand size
+	amJ                // Relative branch displacement
+	amO                // Memory offset without ModR/M (moffs)
+	amA                // Far pointer immediate
+	amS                // ModR/M reg, segment register
+	amC                // ModR/M reg, control register
+	amD                // ModR/M reg, debug register
+	amV                // ModR/M reg, vector register
+	amW                // ModR/M r/m, vector register or memory
+	amU                // ModR/M r/m, vector register only
+	amH                // VEX.vvvv, vector register
+	amL                // Immediate bits 7:4, vector register
+	amB                // VEX.vvvv, general purpose register
+	amP                // ModR/M reg, MMX register
+	amQ                // ModR/M r/m, MMX register or memory
+	amN                // ModR/M r/m, MMX register only
+	amKG               // ModR/M reg, mask register
+	amKH               // VEX.vvvv, mask register
+	amKR               // ModR/M r/m, mask register only
+	amKE               // ModR/M r/m, mask register or memory
+	amMV               // ModR/M r/m, VSIB memory
+	amX                // String source at DS:rSI
+	amY                // String destination at ES:rDI
+	amZ                // Low three opcode bits, general purpose register
+	amSTi              // ModR/M r/m, x87 register
+	amXlat             // XLAT table at DS:rBX
+	amFixed            // Implied register
+	amOne              // Implied constant 1
+)
+
+// opSize is the size code of an operand in the opcode table
+type opSize uint8
+
+const (
+	szNone opSize = iota
+	szB           // Byte
+	szW           // Word
+	szD           // Doubleword
+	szQ           // Quadword
+	szT           // 80-bit x87 extended precision or packed BCD
+	szDQ          // 128 bits
+	szQQ          // 256 bits
+	szV           // Operand size
+	szZ           // Word for 16-bit operand size, doubleword otherwise
+	szY           // Quadword for 64-bit operand size, doubleword otherwise
+	szX           // Vector length
+	szH           // Half the vector length
+	szQR          // Quarter of the vector length
+	szOC          // Eighth of the vector length
+	szXQ          // Vector length, or a quadword for 128-bit vectors (MOVDDUP)
+	szP           // Far pointer, a selector and an offset of the operand size
+	szS           // Descriptor table register (SGDT, LIDT)
+	szE           // x87 environment (FLDENV, FNSTENV)
+	szST          // x87 state (FRSTOR, FNSAVE)
+	szA           // Pair of operand size bounds (BOUND)
+)
+
+// opSpec is an operand of an instruction form
+type opSpec struct {
+	am    addrMethod // Addressing method
+	size  opSize     // Size of the operand
+	msize opSize     // Size of the memory form if it differs (Rd/Mb), or szNone
+	reg   Register   // Register for amFixed, resized to the operand size
+}
+
+// opSizes maps the size suffixes of the opcode table to size codes
+var opSizes = map[string]opSize{
+	"": szNone, "b": szB, "w": szW, "d": szD, "q": szQ, "dq": szDQ, "qq": szQQ,
+	"v": szV, "z": szZ, "y": szY, "x": szX, "h": szH, "qr": szQR, "oc": szOC, "xq": szXQ,
+	"p": szP, "s": szS, "e": szE, "st": szST, "a": szA, "er": szT, "bcd": szT,
+	"ps": szX, "pd": szX, "ss": szD, "sd": szQ, "pi": szQ, "sr": szD, "dr": szQ,
+}
+
+// opMethods maps the addressing method prefixes of the opcode table, two letter methods first
+var opMethods = []struct {
+	name string
+	am   addrMethod
+}{
+	{"KG", amKG}, {"KH", amKH}, {"KR", amKR}, {"KE", amKE}, {"MV", amMV},
+	{"E", amE}, {"G", amG}, {"M", amM}, {"R", amR}, {"I", amI}, {"J", amJ}, {"O", amO},
+	{"A", amA}, {"S", amS}, {"C", amC}, {"D", amD}, {"V", amV}, {"W", amW}, {"U", amU},
+	{"H", amH}, {"L", amL}, {"B", amB}, {"P", amP}, {"Q", amQ}, {"N", amN}, {"X", amX},
+	{"Y", amY}, {"Z", amZ},
+}
+
+// opFixed lists the operands of the opcode table outside of the method and size scheme
+var opFixed = map[string]opSpec{
+	"AL":   {am: amFixed, size: szB, reg: AL},
+	"CL":   {am: amFixed, size: szB, reg: CL},
+	"AX":   {am: amFixed, size: szW, reg: AX},
+	"DX":   {am: amFixed, size: szW, reg: DX},
+	"rAX":  {am: amFixed, size: szV, reg: RAX},
+	"eAX":  {am: amFixed, size: szZ, reg: EAX},
+	"ES":   {am: amFixed, size: szW, reg: ES},
+	"CS":   {am: amFixed, size: szW, reg: CS},
+	"SS":   {am: amFixed, size: szW, reg: SS},
+	"DS":   {am: amFixed, size: szW, reg: DS},
+	"FS":   {am: amFixed, size: szW, reg: FS},
+	"GS":   {am: amFixed, size: szW, reg: GS},
+	"ST":   {am: amFixed, size: szT, reg: ST0},
+	"XMM0": {am: amFixed, size: szDQ, reg: XMM0},
+	"STi":  {am: amSTi, size: szT},
+	"Xlat": {am: amXlat, size: szB},
+	"1":    {am: amOne, size: szB},
+	"Ibs":  {am: amIs, size: szV},
+}
+
+// parseOperand parses an operand of the opcode table, such as Ev, Vx or Rd/Mw
+func parseOperand(tok string) (opSpec, error) {
+	if s, ok := opFixed[tok]; ok {
+		return s, nil
+	}
+	reg, mem, split := strings.Cut(tok, "/")
+	if split {
+		r, err := parseOperand(reg)
+		if err != nil {
+			return r, err
+		}
+		m, err := parseOperand(mem)
+		if err != nil || m.am != amM {
+			return r, fmt.Errorf("bad memory form in operand %q", tok)
+		}
+		switch r.am {
+		case amR:
+			r.am = amE
+		case amU:
+			r.am = amW
+		default:
+			return r, fmt.Errorf("bad register form in operand %q", tok)
+		}
+		r.msize = m.size
+		return r, nil
+	}
+	for _, m := range opMethods {
+		if len(tok) < len(m.name) || tok[:len(m.name)] != m.name {
+			continue
+		}
+		if sz, ok := opSizes[tok[len(m.name):]]; ok {
+			return opSpec{am: m.am, size: sz}, nil
+		}
+	}
+	return opSpec{}, fmt.Errorf("unknown operand %q", tok)
+}
+
+// vectorBits returns the vector length of the instruction in bits
+func (insn *Insn) vectorBits() int {
+	switch insn.encoding() {
+	case encVEX:
+		return 128 << insn.VEX.L()
+	case encEVEX:
+		// With a register operand, b selects static rounding and implies 512-bit vectors
+		if insn.EVEX.Bcst() != 0 && insn.ModRM.Mod() == 3 {
+			return 512
+		}
+		return 128 << min(insn.EVEX.LL(), 2)
+	}
+	return 128
+}
+
+// bits returns the size of an operand in bits
+func (insn *Insn) bits(sz opSize, osz, vl int) int {
+	switch sz {
+	case szB:
+		return 8
+	case szW:
+		return 16
+	case szD:
+		return 32
+	case szQ:
+		return 64
+	case szT:
+		return 80
+	case szDQ:
+		return 128
+	case szQQ:
+		return 256
+	case szV:
+		return osz
+	case szZ:
+		return min(osz, 32)
+	case szY:
+		return max(osz, 32)
+	case szX:
+		return vl
+	case szH:
+		return vl / 2
+	case szQR:
+		return vl / 4
+	case szOC:
+		return vl / 8
+	case szXQ:
+		if vl == 128 {
+			return 64
+		}
+		return vl
+	case szP:
+		return osz + 16
+	case szS:
+		if insn.Mode.long {
+			return 80
+		}
+		return 48
+	case szE:
+		if osz == 16 {
+			return 14 * 8
+		}
+		return 28 * 8
+	case szST:
+		if osz == 16 {
+			return 94 * 8
+		}
+		return 108 * 8
+	case szA:
+		return osz * 2
+	}
+	return 0
+}
+
+// regExt returns the register extension bits (REX.R, REX.X, REX.B) of the instruction.
+// They are ignored outside of 64-bit mode.
+func (insn *Insn) regExt() (r, x, b uint8) {
+	if !insn.Mode.long {
+		return 0, 0, 0
+	}
+	switch insn.encoding() {
+	case encVEX:
+		return insn.VEX.R(), insn.VEX.X(), insn.VEX.B()
+	case encEVEX:
+		return insn.EVEX.R(), insn.EVEX.X(), insn.EVEX.B()
+	}
+	return insn.REX.R(), insn.REX.X(), insn.REX.B()
+}
+
+// vvvv returns the register specified by VEX.vvvv or EVEX.V'vvvv
+func (insn *Insn) vvvv() uint8 {
+	switch insn.encoding() {
+	case encVEX:
+		if !insn.Mode.long {
+			return insn.VEX.V() & 7
+		}
+		return insn.VEX.V()
+	case encEVEX:
+		if !insn.Mode.long {
+			return insn.EVEX.V() & 7
+		}
+		return insn.EVEX.V() | insn.EVEX.V2()<<4
+	}
+	return 0
+}
+
+// gpr returns the general purpose register of the given size and number. Without
+// a REX prefix, byte registers 4 to 7 are AH, CH, DH and BH.
+func (insn *Insn) gpr(num uint8, bits int) Register {
+	if bits == 8 && num >= 4 && num < 8 && insn.Flags&HasREX == 0 {
+		return AH + Register(num-4)
+	}
+	return regOf(ClassGPR, num, bits)
+}
+
+// segment returns the segment override of the instruction, or def
+func (insn *Insn) segment(def Segment) Segment {
+	if seg := insn.Flags.Segment(); seg != SegNone {
+		return seg
+	}
+	return def
+}
+
+// memory returns the memory reference encoded by the ModR/M, SIB and displacement
+func (insn *Insn) memory() Mem {
+	_, x, b := insn.regExt()
+	var m Mem
+	m.Disp, _ = insn.Displacement()
+	mod, rm := insn.ModRM.Mod(), insn.ModRM.RM()
+	asz := insn.AddressSize()
+	if asz == 16 {
+		// [BX+SI], [BX+DI], [BP+SI], [BP+DI], [SI], [DI], [BP], [BX]
+		bases := [8]Register{BX, BX, BP, BP, SI, DI, BP, BX}
+		switch {
+		case rm < 4:
+			m.Base, m.Index, m.Scale = bases[rm], SI+Register(rm&1), 1
+		case rm != 6 || mod != 0:
+			m.Base = bases[rm]
+		}
+	} else {
+		switch {
+		case rm == 4:
+			if base := insn.SIB.Base(); base != 5 || mod != 0 {
+				m.Base = regOf(ClassGPR, base|b<<3, asz)
+			}
+			if index := insn.SIB.Index() | x<<3; index != 4 {
+				m.Index = regOf(ClassGPR, index, asz)
+				m.Scale = 1 << insn.SIB.Scale()
+			}
+		case rm == 5 && mod == 0:
+			if insn.Mode.long {
+				m.Base = regOf(ClassIP, 0, asz)
+			}
+		default:
+			m.Base = regOf(ClassGPR, rm|b<<3, asz)
+		}
+	}
+	m.Segment = SegDS
+	switch m.Base {
+	case SP, BP, ESP, EBP, RSP, RBP:
+		m.Segment = SegSS
+	}
+	m.Segment = insn.segment(m.Segment)
+	return m
+}
+
+// stringMem returns the memory reference of a string instruction operand at seg:base
+func (insn *Insn) stringMem(seg Segment, base Register) Mem {
+	return Mem{Segment: seg, Base: regOf(ClassGPR, base.Num(), insn.AddressSize())}
+}
+
+// Operands returns the operands of the instruction in Intel order, destination first.
+// Implied operands that the assembler does not spell out, such as the accumulator
+// of MUL, are omitted. It returns nil if the instruction has no operands or is not
+// a known instruction.
+func (insn *Insn) Operands() []Operand {
+	f := insn.form()
+	if f == nil || f.ops[0].am == amNone {
+		return nil
+	}
+	var ops []Operand
+	for i := range f.ops {
+		if f.ops[i].am == amNone {
+			break
+		}
+		ops = append(ops, insn.operand(f, i))
+	}
+	return ops
+}
+
+// operand resolves the i-th operand of the instruction form f
+func (insn *Insn) operand(f *opForm, i int) Operand {
+	s := f.ops[i]
+	osz := insn.operandSize(f.flags)
+	vl := insn.vectorBits()
+	r, x, b := insn.regExt()
+	reg := insn.ModRM.Reg() | r<<3
+	rm := insn.ModRM.RM() | b<<3
+	isReg := insn.ModRM.Mod() == 3
+	evex := insn.encoding() == encEVEX
+	if evex && insn.Mode.long {
+		reg |= insn.EVEX.R2() << 4
+	}
+
+	op := Operand{Size: uint16(insn.bits(s.size, osz, vl))}
+	switch s.am {
+	case amE, amM, amR:
+		if s.am == amR || s.am == amE && isReg {
+			op.Type, op.Reg = OpReg, insn.gpr(rm, int(op.Size))
+			break
+		}
+		op.Type, op.Mem = OpMem, insn.memory()
+	case amG:
+		op.Type, op.Reg = OpReg, insn.gpr(insn.ModRM.Reg()|r<<3, int(op.Size))
+	case amB:
+		op.Type, op.Reg = OpReg, regOf(ClassGPR, insn.vvvv(), int(op.Size))
+	case amZ:
+		op.Type, op.Reg = OpReg, insn.gpr(insn.OpcodeByte()&7|b<<3, int(op.Size))
+	case amV:
+		op.Type, op.Reg = OpReg, regOf(ClassXMM, reg, int(op.Size))
+	case amH:
+		op.Type, op.Reg = OpReg, regOf(ClassXMM, insn.vvvv(), int(op.Size))
+	case amL:
+		num := uint8(insn.Imm.Value) >> 4
+		if !insn.Mode.long {
+			num &= 7
+		}
+		op.Type, op.Reg = OpReg, regOf(ClassXMM, num, int(op.Size))
+	case amW, amU:
+		if s.am == amU || isReg {
+			num := rm
+			if evex && insn.Mode.long {
+				num |= x << 4
+			}
+			op.Type, op.Reg = OpReg, regOf(ClassXMM, num, int(op.Size))
+			break
+		}
+		op.Type, op.Mem = OpMem, insn.memory()
+		if evex && insn.EVEX.Bcst() != 0 {
+			op.Size, op.Mem.Broadcast = 32<<insn.EVEX.W(), true
+		}
+	case amMV:
+		op.Type, op.Mem = OpMem, insn.memory()
+		index := insn.SIB.Index() | x<<3
+		ivl := vl
+		if evex && insn.Mode.long {
+			index |= insn.EVEX.V2() << 4
+		}
+		if s.size == szD && insn.vexW() != 0 {
+			ivl /= 2 // Doubleword indices of quadword elements
+		}
+		op.Mem.Index = regOf(ClassXMM, index, ivl)
+		op.Mem.Scale = 1 << insn.SIB.Scale()
+		op.Size = uint16(32 << insn.vexW())
+	case amP, amN, amQ:
+		if s.am == amP {
+			op.Type, op.Reg = OpReg, regOf(ClassMMX, insn.ModRM.Reg(), 64)
+		} else if s.am == amN || isReg {
+			op.Type, op.Reg = OpReg, regOf(ClassMMX, insn.ModRM.RM(), 64)
+		} else {
+			op.Type, op.Mem = OpMem, insn.memory()
+		}
+	case amKG:
+		op.Type, op.Reg = OpReg, regOf(ClassMask, insn.ModRM.Reg(), 64)
+	case amKH:
+		op.Type, op.Reg = OpReg, regOf(ClassMask, insn.vvvv(), 64)
+	case amKR, amKE:
+		if s.am == amKR || isReg {
+			op.Type, op.Reg = OpReg, regOf(ClassMask, insn.ModRM.RM(), 64)
+		} else {
+			op.Type, op.Mem = OpMem, insn.memory()
+		}
+	case amS:
+		op.Type, op.Reg = OpReg, regOf(ClassSeg, insn.ModRM.Reg(), 16)
+	case amC:
+		op.Type, op.Reg = OpReg, regOf(ClassCR, insn.ModRM.Reg()|r<<3, 64)
+	case amD:
+		op.Type, op.Reg = OpReg, regOf(ClassDR, insn.ModRM.Reg()|r<<3, 64)
+	case amSTi:
+		op.Type, op.Reg = OpReg, regOf(ClassX87, insn.ModRM.RM(), 80)
+	case amFixed:
+		op.Type, op.Reg = OpReg, regOf(s.reg.Class(), s.reg.Num(), int(op.Size))
+	case amO:
+		op.Type = OpMem
+		op.Mem = Mem{Segment: insn.segment(SegDS), Disp: int64(insn.Imm.Value)}
+	case amX:
+		op.Type, op.Mem = OpMem, insn.stringMem(insn.segment(SegDS), RSI)
+	case amXlat:
+		op.Type, op.Mem = OpMem, insn.stringMem(insn.segment(SegDS), RBX)
+	case amY:
+		op.Type, op.Mem = OpMem, insn.stringMem(SegES, RDI)
+	case amI, amIs, amJ:
+		// The second immediate of ENTER is stored in Disp
+		lit := insn.Imm
+		for _, prev := range f.ops[:i] {
+			if prev.am == amI || prev.am == amIs {
+				lit = insn.Disp
+			}
+		}
+		switch {
+		case s.am == amJ:
+			op.Type, op.Size = OpRel, uint16(osz)
+			op.Imm, _ = lit.Int()
+		case s.am == amIs || s.size == szZ:
+			op.Type, op.Size = OpImm, uint16(osz)
+			op.Imm, _ = lit.Int()
+		default:
+			op.Type, op.Imm = OpImm, int64(lit.Value)
+		}
+	case amA:
+		op.Type = OpFar
+		op.Imm = int64(insn.Imm.Value)
+		op.Selector = uint16(insn.Disp.Value)
+		op.Size = uint16(insn.Imm.Bits) + 16
+	case amOne:
+		op.Type, op.Imm = OpImm, 1
+	}
+	if op.Type == OpMem && s.msize != szNone {
+		op.Size = uint16(insn.bits(s.msize, osz, vl))
+	}
+	return op
+}
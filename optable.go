@@ -0,0 +1,1526 @@
+package hde
+
+// opcodeTable lists the instruction forms known to the decoder, one per line:
+//
+//	[encodings] [prefix] opcode [constraints] mnemonic [operands]
+//
+// Encodings are L (legacy), V (VEX) and E (EVEX) and default to L. Lines that
+// combine L with V or E name the VEX form; the legacy mnemonic drops the 'v'
+// and the legacy operands drop the vvvv (H) operand.
+// The prefix is the mandatory NP, 66, F3 or F2 prefix, or the VEX/EVEX pp field.
+// The opcode is written as its map bytes followed by the opcode byte, which may
+// be a range (+r for 8 registers, +cc for 16 conditions substituted for {cc}),
+// and optionally by a complete ModR/M byte.
+//
+// Constraints select among forms sharing an opcode:
+//
+//	/0../7       ModR/M reg field
+//	mem, reg     ModR/M memory or register form
+//	W0, W1       REX.W, VEX.W or EVEX.W
+//	L0, L1, L2   VEX.L or EVEX.L'L
+//	o16..o64     Effective operand size
+//	a16..a64     Effective address size
+//	B0           REX.B clear
+//	i64, x64     Invalid in 64-bit mode, valid in 64-bit mode only
+//	d64, f64     Operand size defaults or is forced to 64 bits in 64-bit mode
+//
+// Operands follow the notation of the Intel SDM opcode map (appendix A), an
+// addressing method followed by a size, such as Ev, Gz or Vx. Register and
+// memory forms of differing sizes are written as Rd/Mb. Implicit operands are
+// named directly, such as AL, rAX, DX, ST, STi, XMM0 or 1.
+const opcodeTable = `
+# One-byte opcode map
+00 add Eb,Gb
+01 add Ev,Gv
+02 add Gb,Eb
+03 add Gv,Ev
+04 add AL,Ib
+05 add rAX,Iz
+06 i64 push ES
+07 i64 pop ES
+08 or Eb,Gb
+09 or Ev,Gv
+0a or Gb,Eb
+0b or Gv,Ev
+0c or AL,Ib
+0d or rAX,Iz
+0e i64 push CS
+10 adc Eb,Gb
+11 adc Ev,Gv
+12 adc Gb,Eb
+13 adc Gv,Ev
+14 adc AL,Ib
+15 adc rAX,Iz
+16 i64 push SS
+17 i64 pop SS
+18 sbb Eb,Gb
+19 sbb Ev,Gv
+1a sbb Gb,Eb
+1b sbb Gv,Ev
+1c sbb AL,Ib
+1d sbb rAX,Iz
+1e i64 push DS
+1f i64 pop DS
+20 and Eb,Gb
+21 and Ev,Gv
+22 and Gb,Eb
+23 and Gv,Ev
+24 and AL,Ib
+25 and rAX,Iz
+27 i64 daa
+28 sub Eb,Gb
+29 sub Ev,Gv
+2a sub Gb,Eb
+2b sub Gv,Ev
+2c sub AL,Ib
+2d sub rAX,Iz
+2f i64 das
+30 xor Eb,Gb
+31 xor Ev,Gv
+32 xor Gb,Eb
+33 xor Gv,Ev
+34 xor AL,Ib
+35 xor rAX,Iz
+37 i64 aaa
+38 cmp Eb,Gb
+39 cmp Ev,Gv
+3a cmp Gb,Eb
+3b cmp Gv,Ev
+3c cmp AL,Ib
+3d cmp rAX,Iz
+3f i64 aas
+40+r i64 inc Zv
+48+r i64 dec Zv
+50+r d64 push Zv
+58+r d64 pop Zv
+60 i64 o16 pusha
+60 i64 o32 pushad
+61 i64 o16 popa
+61 i64 o32 popad
+62 i64 bound Gv,Ma
+63 i64 arpl Ew,Gw
+63 x64 movsxd Gv,Ed
+68 d64 push Iz
+69 imul Gv,Ev,Iz
+6a d64 push Ibs
+6b imul Gv,Ev,Ibs
+6c insb Yb,DX
+6d o16 insw Yz,DX
+6d insd Yz,DX
+6e outsb DX,Xb
+6f o16 outsw DX,Xz
+6f outsd DX,Xz
+70+cc f64 j{cc} Jb
+80 /0 add Eb,Ib
+80 /1 or Eb,Ib
+80 /2 adc Eb,Ib
+80 /3 sbb Eb,Ib
+80 /4 and Eb,Ib
+80 /5 sub Eb,Ib
+80 /6 xor Eb,Ib
+80 /7 cmp Eb,Ib
+81 /0 add Ev,Iz
+81 /1 or Ev,Iz
+81 /2 adc Ev,Iz
+81 /3 sbb Ev,Iz
+81 /4 and Ev,Iz
+81 /5 sub Ev,Iz
+81 /6 xor Ev,Iz
+81 /7 cmp Ev,Iz
+82 /0 i64 add Eb,Ib
+82 /1 i64 or Eb,Ib
+82 /2 i64 adc Eb,Ib
+82 /3 i64 sbb Eb,Ib
+82 /4 i64 and Eb,Ib
+82 /5 i64 sub Eb,Ib
+82 /6 i64 xor Eb,Ib
+82 /7 i64 cmp Eb,Ib
+83 /0 add Ev,Ibs
+83 /1 or Ev,Ibs
+83 /2 adc Ev,Ibs
+83 /3 sbb Ev,Ibs
+83 /4 and Ev,Ibs
+83 /5 sub Ev,Ibs
+83 /6 xor Ev,Ibs
+83 /7 cmp Ev,Ibs
+84 test Eb,Gb
+85 test Ev,Gv
+86 xchg Eb,Gb
+87 xchg Ev,Gv
+88 mov Eb,Gb
+89 mov Ev,Gv
+8a mov Gb,Eb
+8b mov Gv,Ev
+8c mov Rv/Mw,Sw
+8d lea Gv,M
+8e mov Sw,Rv/Mw
+8f /0 d64 pop Ev
+90 B0 nop
+66 90 B0 xchg Zv,rAX
+F3 90 B0 pause
+90+r xchg Zv,rAX
+98 o16 cbw
+98 o32 cwde
+98 o64 cdqe
+99 o16 cwd
+99 o32 cdq
+99 o64 cqo
+9a i64 call Ap
+9b fwait
+9c d64 o16 pushf
+9c d64 o32 pushfd
+9c d64 o64 pushfq
+9d d64 o16 popf
+9d d64 o32 popfd
+9d d64 o64 popfq
+9e sahf
+9f lahf
+a0 mov AL,Ob
+a1 mov rAX,Ov
+a2 mov Ob,AL
+a3 mov Ov,rAX
+a4 movsb Yb,Xb
+a5 o16 movsw Yv,Xv
+a5 o32 movsd Yv,Xv
+a5 o64 movsq Yv,Xv
+a6 cmpsb Xb,Yb
+a7 o16 cmpsw Xv,Yv
+a7 o32 cmpsd Xv,Yv
+a7 o64 cmpsq Xv,Yv
+a8 test AL,Ib
+a9 test rAX,Iz
+aa stosb Yb,AL
+ab o16 stosw Yv,rAX
+ab o32 stosd Yv,rAX
+ab o64 stosq Yv,rAX
+ac lodsb AL,Xb
+ad o16 lodsw rAX,Xv
+ad o32 lodsd rAX,Xv
+ad o64 lodsq rAX,Xv
+ae scasb AL,Yb
+af o16 scasw rAX,Yv
+af o32 scasd rAX,Yv
+af o64 scasq rAX,Yv
+b0+r mov Zb,Ib
+b8+r mov Zv,Iv
+c0 /0 rol Eb,Ib
+c0 /1 ror Eb,Ib
+c0 /2 rcl Eb,Ib
+c0 /3 rcr Eb,Ib
+c0 /4 shl Eb,Ib
+c0 /5 shr Eb,Ib
+c0 /6 sal Eb,Ib
+c0 /7 sar Eb,Ib
+c1 /0 rol Ev,Ib
+c1 /1 ror Ev,Ib
+c1 /2 rcl Ev,Ib
+c1 /3 rcr Ev,Ib
+c1 /4 shl Ev,Ib
+c1 /5 shr Ev,Ib
+c1 /6 sal Ev,Ib
+c1 /7 sar Ev,Ib
+c2 f64 ret Iw
+c3 f64 ret
+c4 i64 les Gz,Mp
+c5 i64 lds Gz,Mp
+c6 /0 mov Eb,Ib
+c6 f8 xabort Ib
+c7 /0 mov Ev,Iz
+c7 f8 f64 xbegin Jz
+c8 d64 enter Iw,Ib
+c9 d64 leave
+ca retf Iw
+cb retf
+cc int3
+cd int Ib
+ce i64 into
+cf o16 iret
+cf o32 iretd
+cf o64 iretq
+d0 /0 rol Eb,1
+d0 /1 ror Eb,1
+d0 /2 rcl Eb,1
+d0 /3 rcr Eb,1
+d0 /4 shl Eb,1
+d0 /5 shr Eb,1
+d0 /6 sal Eb,1
+d0 /7 sar Eb,1
+d1 /0 rol Ev,1
+d1 /1 ror Ev,1
+d1 /2 rcl Ev,1
+d1 /3 rcr Ev,1
+d1 /4 shl Ev,1
+d1 /5 shr Ev,1
+d1 /6 sal Ev,1
+d1 /7 sar Ev,1
+d2 /0 rol Eb,CL
+d2 /1 ror Eb,CL
+d2 /2 rcl Eb,CL
+d2 /3 rcr Eb,CL
+d2 /4 shl Eb,CL
+d2 /5 shr Eb,CL
+d2 /6 sal Eb,CL
+d2 /7 sar Eb,CL
+d3 /0 rol Ev,CL
+d3 /1 ror Ev,CL
+d3 /2 rcl Ev,CL
+d3 /3 rcr Ev,CL
+d3 /4 shl Ev,CL
+d3 /5 shr Ev,CL
+d3 /6 sal Ev,CL
+d3 /7 sar Ev,CL
+d4 i64 aam Ib
+d5 i64 aad Ib
+d6 i64 salc
+d7 xlat Xlat
+e0 f64 loopne Jb
+e1 f64 loope Jb
+e2 f64 loop Jb
+e3 a16 f64 jcxz Jb
+e3 a32 f64 jecxz Jb
+e3 a64 f64 jrcxz Jb
+e4 in AL,Ib
+e5 in eAX,Ib
+e6 out Ib,AL
+e7 out Ib,eAX
+e8 f64 call Jz
+e9 f64 jmp Jz
+ea i64 jmp Ap
+eb f64 jmp Jb
+ec in AL,DX
+ed in eAX,DX
+ee out DX,AL
+ef out DX,eAX
+f1 int1
+f4 hlt
+f5 cmc
+f6 /0 test Eb,Ib
+f6 /1 test Eb,Ib
+f6 /2 not Eb
+f6 /3 neg Eb
+f6 /4 mul Eb
+f6 /5 imul Eb
+f6 /6 div Eb
+f6 /7 idiv Eb
+f7 /0 test Ev,Iz
+f7 /1 test Ev,Iz
+f7 /2 not Ev
+f7 /3 neg Ev
+f7 /4 mul Ev
+f7 /5 imul Ev
+f7 /6 div Ev
+f7 /7 idiv Ev
+f8 clc
+f9 stc
+fa cli
+fb sti
+fc cld
+fd std
+fe /0 inc Eb
+fe /1 dec Eb
+ff /0 inc Ev
+ff /1 dec Ev
+ff /2 f64 call Ev
+ff /3 mem call Mp
+ff /4 f64 jmp Ev
+ff /5 mem jmp Mp
+ff /6 d64 push Ev
+
+# x87 escape opcodes, memory forms
+d8 /0 mem fadd Msr
+d8 /1 mem fmul Msr
+d8 /2 mem fcom Msr
+d8 /3 mem fcomp Msr
+d8 /4 mem fsub Msr
+d8 /5 mem fsubr Msr
+d8 /6 mem fdiv Msr
+d8 /7 mem fdivr Msr
+d9 /0 mem fld Msr
+d9 /2 mem fst Msr
+d9 /3 mem fstp Msr
+d9 /4 mem fldenv Me
+d9 /5 mem fldcw Mw
+d9 /6 mem fnstenv Me
+d9 /7 mem fnstcw Mw
+da /0 mem fiadd Md
+da /1 mem fimul Md
+da /2 mem ficom Md
+da /3 mem ficomp Md
+da /4 mem fisub Md
+da /5 mem fisubr Md
+da /6 mem fidiv Md
+da /7 mem fidivr Md
+db /0 mem fild Md
+db /1 mem fisttp Md
+db /2 mem fist Md
+db /3 mem fistp Md
+db /5 mem fld Mer
+db /7 mem fstp Mer
+dc /0 mem fadd Mdr
+dc /1 mem fmul Mdr
+dc /2 mem fcom Mdr
+dc /3 mem fcomp Mdr
+dc /4 mem fsub Mdr
+dc /5 mem fsubr Mdr
+dc /6 mem fdiv Mdr
+dc /7 mem fdivr Mdr
+dd /0 mem fld Mdr
+dd /1 mem fisttp Mq
+dd /2 mem fst Mdr
+dd /3 mem fstp Mdr
+dd /4 mem frstor Mst
+dd /6 mem fnsave Mst
+dd /7 mem fnstsw Mw
+de /0 mem fiadd Mw
+de /1 mem fimul Mw
+de /2 mem ficom Mw
+de /3 mem ficomp Mw
+de /4 mem fisub Mw
+de /5 mem fisubr Mw
+de /6 mem fidiv Mw
+de /7 mem fidivr Mw
+df /0 mem fild Mw
+df /1 mem fisttp Mw
+df /2 mem fist Mw
+df /3 mem fistp Mw
+df /4 mem fbld Mbcd
+df /5 mem fild Mq
+df /6 mem fbstp Mbcd
+df /7 mem fistp Mq
+
+# x87 escape opcodes, register forms
+d8 /0 reg fadd ST,STi
+d8 /1 reg fmul ST,STi
+d8 /2 reg fcom STi
+d8 /3 reg fcomp STi
+d8 /4 reg fsub ST,STi
+d8 /5 reg fsubr ST,STi
+d8 /6 reg fdiv ST,STi
+d8 /7 reg fdivr ST,STi
+d9 /0 reg fld STi
+d9 /1 reg fxch STi
+d9 d0 fnop
+d9 e0 fchs
+d9 e1 fabs
+d9 e4 ftst
+d9 e5 fxam
+d9 e8 fld1
+d9 e9 fldl2t
+d9 ea fldl2e
+d9 eb fldpi
+d9 ec fldlg2
+d9 ed fldln2
+d9 ee fldz
+d9 f0 f2xm1
+d9 f1 fyl2x
+d9 f2 fptan
+d9 f3 fpatan
+d9 f4 fxtract
+d9 f5 fprem1
+d9 f6 fdecstp
+d9 f7 fincstp
+d9 f8 fprem
+d9 f9 fyl2xp1
+d9 fa fsqrt
+d9 fb fsincos
+d9 fc frndint
+d9 fd fscale
+d9 fe fsin
+d9 ff fcos
+da /0 reg fcmovb ST,STi
+da /1 reg fcmove ST,STi
+da /2 reg fcmovbe ST,STi
+da /3 reg fcmovu ST,STi
+da e9 fucompp
+db /0 reg fcmovnb ST,STi
+db /1 reg fcmovne ST,STi
+db /2 reg fcmovnbe ST,STi
+db /3 reg fcmovnu ST,STi
+db e2 fnclex
+db e3 fninit
+db /5 reg fucomi ST,STi
+db /6 reg fcomi ST,STi
+dc /0 reg fadd STi,ST
+dc /1 reg fmul STi,ST
+dc /4 reg fsubr STi,ST
+dc /5 reg fsub STi,ST
+dc /6 reg fdivr STi,ST
+dc /7 reg fdiv STi,ST
+dd /0 reg ffree STi
+dd /2 reg fst STi
+dd /3 reg fstp STi
+dd /4 reg fucom STi
+dd /5 reg fucomp STi
+de /0 reg faddp STi,ST
+de /1 reg fmulp STi,ST
+de d9 fcompp
+de /4 reg fsubrp STi,ST
+de /5 reg fsubp STi,ST
+de /6 reg fdivrp STi,ST
+de /7 reg fdivp STi,ST
+df /0 reg ffreep STi
+df e0 fnstsw AX
+df /5 reg fucomip ST,STi
+df /6 reg fcomip ST,STi
+
+# Two-byte opcode map (0F xx)
+0f 00 /0 sldt Rv/Mw
+0f 00 /1 str Rv/Mw
+0f 00 /2 lldt Ew
+0f 00 /3 ltr Ew
+0f 00 /4 verr Ew
+0f 00 /5 verw Ew
+0f 01 /0 mem sgdt Ms
+0f 01 /1 mem sidt Ms
+0f 01 /2 mem lgdt Ms
+0f 01 /3 mem lidt Ms
+0f 01 /4 smsw Rv/Mw
+0f 01 /6 lmsw Ew
+0f 01 /7 mem invlpg Mb
+0f 01 c1 vmcall
+0f 01 c2 vmlaunch
+0f 01 c3 vmresume
+0f 01 c4 vmxoff
+0f 01 c8 monitor
+0f 01 c9 mwait
+0f 01 ca clac
+0f 01 cb stac
+0f 01 cf encls
+0f 01 d0 xgetbv
+0f 01 d1 xsetbv
+0f 01 d4 vmfunc
+0f 01 d5 xend
+0f 01 d6 xtest
+0f 01 d7 enclu
+0f 01 d8 vmrun
+0f 01 d9 vmmcall
+0f 01 da vmload
+0f 01 db vmsave
+0f 01 dc stgi
+0f 01 dd clgi
+0f 01 de skinit
+0f 01 df invlpga
+0f 01 e8 serialize
+0f 01 ee rdpkru
+0f 01 ef wrpkru
+0f 01 f8 x64 swapgs
+0f 01 f9 rdtscp
+0f 01 fa monitorx
+0f 01 fb mwaitx
+0f 01 fc clzero
+0f 02 lar Gv,Ew
+0f 03 lsl Gv,Ew
+0f 05 syscall
+0f 06 clts
+0f 07 sysret
+0f 08 invd
+0f 09 wbinvd
+0f 0b ud2
+0f 0d /0 mem prefetch Mb
+0f 0d /1 mem prefetchw Mb
+0f 0d /2 mem prefetchwt1 Mb
+0f 0d mem prefetch Mb
+0f 0e femms
+
+LVE NP 0f 10 vmovups Vps,Wps
+LVE 66 0f 10 vmovupd Vpd,Wpd
+LVE F3 0f 10 mem vmovss Vdq,Md
+LVE F3 0f 10 reg vmovss Vdq,Hdq,Udq
+LVE F2 0f 10 mem vmovsd Vdq,Mq
+LVE F2 0f 10 reg vmovsd Vdq,Hdq,Udq
+LVE NP 0f 11 vmovups Wps,Vps
+LVE 66 0f 11 vmovupd Wpd,Vpd
+LVE F3 0f 11 mem vmovss Md,Vdq
+LVE F3 0f 11 reg vmovss Udq,Hdq,Vdq
+LVE F2 0f 11 mem vmovsd Mq,Vdq
+LVE F2 0f 11 reg vmovsd Udq,Hdq,Vdq
+LVE NP 0f 12 mem vmovlps Vdq,Hdq,Mq
+LVE NP 0f 12 reg vmovhlps Vdq,Hdq,Udq
+LVE 66 0f 12 mem vmovlpd Vdq,Hdq,Mq
+LVE F3 0f 12 vmovsldup Vx,Wx
+LVE F2 0f 12 vmovddup Vx,Wxq
+LVE NP 0f 13 mem vmovlps Mq,Vdq
+LVE 66 0f 13 mem vmovlpd Mq,Vdq
+LVE NP 0f 14 vunpcklps Vx,Hx,Wx
+LVE 66 0f 14 vunpcklpd Vx,Hx,Wx
+LVE NP 0f 15 vunpckhps Vx,Hx,Wx
+LVE 66 0f 15 vunpckhpd Vx,Hx,Wx
+LVE NP 0f 16 mem vmovhps Vdq,Hdq,Mq
+LVE NP 0f 16 reg vmovlhps Vdq,Hdq,Udq
+LVE 66 0f 16 mem vmovhpd Vdq,Hdq,Mq
+LVE F3 0f 16 vmovshdup Vx,Wx
+LVE NP 0f 17 mem vmovhps Mq,Vdq
+LVE 66 0f 17 mem vmovhpd Mq,Vdq
+0f 18 /0 mem prefetchnta Mb
+0f 18 /1 mem prefetcht0 Mb
+0f 18 /2 mem prefetcht1 Mb
+0f 18 /3 mem prefetcht2 Mb
+0f 18 nop Ev
+0f 19 nop Ev
+0f 1a nop Ev
+0f 1b nop Ev
+0f 1c nop Ev
+0f 1d nop Ev
+0f 1e nop Ev
+F3 0f 1e fa endbr64
+F3 0f 1e fb endbr32
+0f 1f nop Ev
+0f 20 f64 mov Ry,Cy
+0f 21 f64 mov Ry,Dy
+0f 22 f64 mov Cy,Ry
+0f 23 f64 mov Dy,Ry
+LVE NP 0f 28 vmovaps Vps,Wps
+LVE 66 0f 28 vmovapd Vpd,Wpd
+LVE NP 0f 29 vmovaps Wps,Vps
+LVE 66 0f 29 vmovapd Wpd,Vpd
+NP 0f 2a cvtpi2ps Vps,Qpi
+66 0f 2a cvtpi2pd Vpd,Qpi
+LVE F3 0f 2a vcvtsi2ss Vss,Hss,Ey
+LVE F2 0f 2a vcvtsi2sd Vsd,Hsd,Ey
+LVE NP 0f 2b mem vmovntps Mps,Vps
+LVE 66 0f 2b mem vmovntpd Mpd,Vpd
+NP 0f 2c cvttps2pi Ppi,Wq
+66 0f 2c cvttpd2pi Ppi,Wpd
+LVE F3 0f 2c vcvttss2si Gy,Wss
+LVE F2 0f 2c vcvttsd2si Gy,Wsd
+NP 0f 2d cvtps2pi Ppi,Wq
+66 0f 2d cvtpd2pi Ppi,Wpd
+LVE F3 0f 2d vcvtss2si Gy,Wss
+LVE F2 0f 2d vcvtsd2si Gy,Wsd
+LVE NP 0f 2e vucomiss Vss,Wss
+LVE 66 0f 2e vucomisd Vsd,Wsd
+LVE NP 0f 2f vcomiss Vss,Wss
+LVE 66 0f 2f vcomisd Vsd,Wsd
+0f 30 wrmsr
+0f 31 rdtsc
+0f 32 rdmsr
+0f 33 rdpmc
+0f 34 sysenter
+0f 35 sysexit
+0f 37 getsec
+0f 40+cc cmov{cc} Gv,Ev
+
+LV NP 0f 50 reg vmovmskps Gd,Ups
+LV 66 0f 50 reg vmovmskpd Gd,Upd
+LVE NP 0f 51 vsqrtps Vps,Wps
+LVE 66 0f 51 vsqrtpd Vpd,Wpd
+LVE F3 0f 51 vsqrtss Vss,Hss,Wss
+LVE F2 0f 51 vsqrtsd Vsd,Hsd,Wsd
+LV NP 0f 52 vrsqrtps Vps,Wps
+LV F3 0f 52 vrsqrtss Vss,Hss,Wss
+LV NP 0f 53 vrcpps Vps,Wps
+LV F3 0f 53 vrcpss Vss,Hss,Wss
+LVE NP 0f 54 vandps Vps,Hps,Wps
+LVE 66 0f 54 vandpd Vpd,Hpd,Wpd
+LVE NP 0f 55 vandnps Vps,Hps,Wps
+LVE 66 0f 55 vandnpd Vpd,Hpd,Wpd
+LVE NP 0f 56 vorps Vps,Hps,Wps
+LVE 66 0f 56 vorpd Vpd,Hpd,Wpd
+LVE NP 0f 57 vxorps Vps,Hps,Wps
+LVE 66 0f 57 vxorpd Vpd,Hpd,Wpd
+LVE NP 0f 58 vaddps Vps,Hps,Wps
+LVE 66 0f 58 vaddpd Vpd,Hpd,Wpd
+LVE F3 0f 58 vaddss Vss,Hss,Wss
+LVE F2 0f 58 vaddsd Vsd,Hsd,Wsd
+LVE NP 0f 59 vmulps Vps,Hps,Wps
+LVE 66 0f 59 vmulpd Vpd,Hpd,Wpd
+LVE F3 0f 59 vmulss Vss,Hss,Wss
+LVE F2 0f 59 vmulsd Vsd,Hsd,Wsd
+LVE NP 0f 5a vcvtps2pd Vpd,Wh
+LVE 66 0f 5a vcvtpd2ps Vh,Wpd
+LVE F3 0f 5a vcvtss2sd Vsd,Hsd,Wss
+LVE F2 0f 5a vcvtsd2ss Vss,Hss,Wsd
+LVE NP 0f 5b vcvtdq2ps Vps,Wx
+E NP 0f 5b W1 vcvtqq2ps Vh,Wx
+LVE 66 0f 5b vcvtps2dq Vx,Wps
+LVE F3 0f 5b vcvttps2dq Vx,Wps
+LVE NP 0f 5c vsubps Vps,Hps,Wps
+LVE 66 0f 5c vsubpd Vpd,Hpd,Wpd
+LVE F3 0f 5c vsubss Vss,Hss,Wss
+LVE F2 0f 5c vsubsd Vsd,Hsd,Wsd
+LVE NP 0f 5d vminps Vps,Hps,Wps
+LVE 66 0f 5d vminpd Vpd,Hpd,Wpd
+LVE F3 0f 5d vminss Vss,Hss,Wss
+LVE F2 0f 5d vminsd Vsd,Hsd,Wsd
+LVE NP 0f 5e vdivps Vps,Hps,Wps
+LVE 66 0f 5e vdivpd Vpd,Hpd,Wpd
+LVE F3 0f 5e vdivss Vss,Hss,Wss
+LVE F2 0f 5e vdivsd Vsd,Hsd,Wsd
+LVE NP 0f 5f vmaxps Vps,Hps,Wps
+LVE 66 0f 5f vmaxpd Vpd,Hpd,Wpd
+LVE F3 0f 5f vmaxss Vss,Hss,Wss
+LVE F2 0f 5f vmaxsd Vsd,Hsd,Wsd
+
+NP 0f 60 punpcklbw Pq,Qd
+LVE 66 0f 60 vpunpcklbw Vx,Hx,Wx
+NP 0f 61 punpcklwd Pq,Qd
+LVE 66 0f 61 vpunpcklwd Vx,Hx,Wx
+NP 0f 62 punpckldq Pq,Qd
+LVE 66 0f 62 vpunpckldq Vx,Hx,Wx
+NP 0f 63 packsswb Pq,Qq
+LVE 66 0f 63 vpacksswb Vx,Hx,Wx
+NP 0f 64 pcmpgtb Pq,Qq
+LV 66 0f 64 vpcmpgtb Vx,Hx,Wx
+E 66 0f 64 vpcmpgtb KG,Hx,Wx
+NP 0f 65 pcmpgtw Pq,Qq
+LV 66 0f 65 vpcmpgtw Vx,Hx,Wx
+E 66 0f 65 vpcmpgtw KG,Hx,Wx
+NP 0f 66 pcmpgtd Pq,Qq
+LV 66 0f 66 vpcmpgtd Vx,Hx,Wx
+E 66 0f 66 vpcmpgtd KG,Hx,Wx
+NP 0f 67 packuswb Pq,Qq
+LVE 66 0f 67 vpackuswb Vx,Hx,Wx
+NP 0f 68 punpckhbw Pq,Qq
+LVE 66 0f 68 vpunpckhbw Vx,Hx,Wx
+NP 0f 69 punpckhwd Pq,Qq
+LVE 66 0f 69 vpunpckhwd Vx,Hx,Wx
+NP 0f 6a punpckhdq Pq,Qq
+LVE 66 0f 6a vpunpckhdq Vx,Hx,Wx
+NP 0f 6b packssdw Pq,Qq
+LVE 66 0f 6b vpackssdw Vx,Hx,Wx
+LVE 66 0f 6c vpunpcklqdq Vx,Hx,Wx
+LVE 66 0f 6d vpunpckhqdq Vx,Hx,Wx
+NP 0f 6e W0 movd Pq,Ey
+NP 0f 6e W1 movq Pq,Ey
+LVE 66 0f 6e W0 vmovd Vdq,Ey
+LVE 66 0f 6e W1 vmovq Vdq,Ey
+NP 0f 6f movq Pq,Qq
+LV 66 0f 6f vmovdqa Vx,Wx
+LV F3 0f 6f vmovdqu Vx,Wx
+E 66 0f 6f W0 vmovdqa32 Vx,Wx
+E 66 0f 6f W1 vmovdqa64 Vx,Wx
+E F3 0f 6f W0 vmovdqu32 Vx,Wx
+E F3 0f 6f W1 vmovdqu64 Vx,Wx
+E F2 0f 6f W0 vmovdqu8 Vx,Wx
+E F2 0f 6f W1 vmovdqu16 Vx,Wx
+
+NP 0f 70 pshufw Pq,Qq,Ib
+LVE 66 0f 70 vpshufd Vx,Wx,Ib
+LVE F3 0f 70 vpshufhw Vx,Wx,Ib
+LVE F2 0f 70 vpshuflw Vx,Wx,Ib
+NP 0f 71 /2 reg psrlw Nq,Ib
+NP 0f 71 /4 reg psraw Nq,Ib
+NP 0f 71 /6 reg psllw Nq,Ib
+LVE 66 0f 71 /2 reg vpsrlw Hx,Wx,Ib
+LVE 66 0f 71 /4 reg vpsraw Hx,Wx,Ib
+LVE 66 0f 71 /6 reg vpsllw Hx,Wx,Ib
+NP 0f 72 /2 reg psrld Nq,Ib
+NP 0f 72 /4 reg psrad Nq,Ib
+NP 0f 72 /6 reg pslld Nq,Ib
+E 66 0f 72 /0 W0 vprord Hx,Wx,Ib
+E 66 0f 72 /0 W1 vprorq Hx,Wx,Ib
+E 66 0f 72 /1 W0 vprold Hx,Wx,Ib
+E 66 0f 72 /1 W1 vprolq Hx,Wx,Ib
+LVE 66 0f 72 /2 vpsrld Hx,Wx,Ib
+LVE 66 0f 72 /4 vpsrad Hx,Wx,Ib
+E 66 0f 72 /4 W1 vpsraq Hx,Wx,Ib
+LVE 66 0f 72 /6 vpslld Hx,Wx,Ib
+NP 0f 73 /2 reg psrlq Nq,Ib
+NP 0f 73 /6 reg psllq Nq,Ib
+LVE 66 0f 73 /2 vpsrlq Hx,Wx,Ib
+LVE 66 0f 73 /3 vpsrldq Hx,Wx,Ib
+LVE 66 0f 73 /6 vpsllq Hx,Wx,Ib
+LVE 66 0f 73 /7 vpslldq Hx,Wx,Ib
+NP 0f 74 pcmpeqb Pq,Qq
+LV 66 0f 74 vpcmpeqb Vx,Hx,Wx
+E 66 0f 74 vpcmpeqb KG,Hx,Wx
+NP 0f 75 pcmpeqw Pq,Qq
+LV 66 0f 75 vpcmpeqw Vx,Hx,Wx
+E 66 0f 75 vpcmpeqw KG,Hx,Wx
+NP 0f 76 pcmpeqd Pq,Qq
+LV 66 0f 76 vpcmpeqd Vx,Hx,Wx
+E 66 0f 76 vpcmpeqd KG,Hx,Wx
+NP 0f 77 emms
+V NP 0f 77 L0 vzeroupper
+V NP 0f 77 L1 vzeroall
+NP 0f 78 f64 vmread Ey,Gy
+NP 0f 79 f64 vmwrite Gy,Ey
+E NP 0f 78 W0 vcvttps2udq Vx,Wps
+E NP 0f 78 W1 vcvttpd2udq Vh,Wpd
+E 66 0f 78 W0 vcvttps2uqq Vx,Wh
+E 66 0f 78 W1 vcvttpd2uqq Vx,Wpd
+E F3 0f 78 vcvttss2usi Gy,Wss
+E F2 0f 78 vcvttsd2usi Gy,Wsd
+E NP 0f 79 W0 vcvtps2udq Vx,Wps
+E NP 0f 79 W1 vcvtpd2udq Vh,Wpd
+E 66 0f 79 W0 vcvtps2uqq Vx,Wh
+E 66 0f 79 W1 vcvtpd2uqq Vx,Wpd
+E F3 0f 79 vcvtss2usi Gy,Wss
+E F2 0f 79 vcvtsd2usi Gy,Wsd
+E 66 0f 7a W0 vcvttps2qq Vx,Wh
+E 66 0f 7a W1 vcvttpd2qq Vx,Wpd
+E F3 0f 7a W0 vcvtudq2pd Vx,Wh
+E F3 0f 7a W1 vcvtuqq2pd Vx,Wx
+E F2 0f 7a W0 vcvtudq2ps Vx,Wx
+E F2 0f 7a W1 vcvtuqq2ps Vh,Wx
+E 66 0f 7b W0 vcvtps2qq Vx,Wh
+E 66 0f 7b W1 vcvtpd2qq Vx,Wpd
+E F3 0f 7b vcvtusi2ss Vss,Hss,Ey
+E F2 0f 7b vcvtusi2sd Vsd,Hsd,Ey
+LV 66 0f 7c vhaddpd Vpd,Hpd,Wpd
+LV F2 0f 7c vhaddps Vps,Hps,Wps
+LV 66 0f 7d vhsubpd Vpd,Hpd,Wpd
+LV F2 0f 7d vhsubps Vps,Hps,Wps
+NP 0f 7e W0 movd Ey,Pq
+NP 0f 7e W1 movq Ey,Pq
+LVE 66 0f 7e W0 vmovd Ey,Vdq
+LVE 66 0f 7e W1 vmovq Ey,Vdq
+LVE F3 0f 7e vmovq Vdq,Wq
+NP 0f 7f movq Qq,Pq
+LV 66 0f 7f vmovdqa Wx,Vx
+LV F3 0f 7f vmovdqu Wx,Vx
+E 66 0f 7f W0 vmovdqa32 Wx,Vx
+E 66 0f 7f W1 vmovdqa64 Wx,Vx
+E F3 0f 7f W0 vmovdqu32 Wx,Vx
+E F3 0f 7f W1 vmovdqu64 Wx,Vx
+E F2 0f 7f W0 vmovdqu8 Wx,Vx
+E F2 0f 7f W1 vmovdqu16 Wx,Vx
+
+0f 80+cc f64 j{cc} Jz
+0f 90+cc set{cc} Eb
+
+# Mask register instructions
+V NP 0f 41 L1 W0 kandw KG,KH,KR
+V NP 0f 41 L1 W1 kandq KG,KH,KR
+V 66 0f 41 L1 W0 kandb KG,KH,KR
+V 66 0f 41 L1 W1 kandd KG,KH,KR
+V NP 0f 42 L1 W0 kandnw KG,KH,KR
+V NP 0f 42 L1 W1 kandnq KG,KH,KR
+V 66 0f 42 L1 W0 kandnb KG,KH,KR
+V 66 0f 42 L1 W1 kandnd KG,KH,KR
+V NP 0f 44 L0 W0 knotw KG,KR
+V NP 0f 44 L0 W1 knotq KG,KR
+V 66 0f 44 L0 W0 knotb KG,KR
+V 66 0f 44 L0 W1 knotd KG,KR
+V NP 0f 45 L1 W0 korw KG,KH,KR
+V NP 0f 45 L1 W1 korq KG,KH,KR
+V 66 0f 45 L1 W0 korb KG,KH,KR
+V 66 0f 45 L1 W1 kord KG,KH,KR
+V NP 0f 46 L1 W0 kxnorw KG,KH,KR
+V NP 0f 46 L1 W1 kxnorq KG,KH,KR
+V 66 0f 46 L1 W0 kxnorb KG,KH,KR
+V 66 0f 46 L1 W1 kxnord KG,KH,KR
+V NP 0f 47 L1 W0 kxorw KG,KH,KR
+V NP 0f 47 L1 W1 kxorq KG,KH,KR
+V 66 0f 47 L1 W0 kxorb KG,KH,KR
+V 66 0f 47 L1 W1 kxord KG,KH,KR
+V NP 0f 4a L1 W0 kaddw KG,KH,KR
+V NP 0f 4a L1 W1 kaddq KG,KH,KR
+V 66 0f 4a L1 W0 kaddb KG,KH,KR
+V 66 0f 4a L1 W1 kaddd KG,KH,KR
+V 66 0f 4b L1 W0 kunpckbw KG,KH,KR
+V NP 0f 4b L1 W0 kunpckwd KG,KH,KR
+V NP 0f 4b L1 W1 kunpckdq KG,KH,KR
+V NP 0f 90 L0 W0 kmovw KG,KEw
+V NP 0f 90 L0 W1 kmovq KG,KEq
+V 66 0f 90 L0 W0 kmovb KG,KEb
+V 66 0f 90 L0 W1 kmovd KG,KEd
+V NP 0f 91 L0 W0 mem kmovw Mw,KG
+V NP 0f 91 L0 W1 mem kmovq Mq,KG
+V 66 0f 91 L0 W0 mem kmovb Mb,KG
+V 66 0f 91 L0 W1 mem kmovd Md,KG
+V NP 0f 92 L0 W0 reg kmovw KG,Rd
+V 66 0f 92 L0 W0 reg kmovb KG,Rd
+V F2 0f 92 L0 W0 reg kmovd KG,Rd
+V F2 0f 92 L0 W1 reg kmovq KG,Rq
+V NP 0f 93 L0 W0 reg kmovw Gd,KR
+V 66 0f 93 L0 W0 reg kmovb Gd,KR
+V F2 0f 93 L0 W0 reg kmovd Gd,KR
+V F2 0f 93 L0 W1 reg kmovq Gq,KR
+V NP 0f 98 L0 W0 reg kortestw KG,KR
+V NP 0f 98 L0 W1 reg kortestq KG,KR
+V 66 0f 98 L0 W0 reg kortestb KG,KR
+V 66 0f 98 L0 W1 reg kortestd KG,KR
+V NP 0f 99 L0 W0 reg ktestw KG,KR
+V NP 0f 99 L0 W1 reg ktestq KG,KR
+V 66 0f 99 L0 W0 reg ktestb KG,KR
+V 66 0f 99 L0 W1 reg ktestd KG,KR
+
+0f a0 d64 push FS
+0f a1 d64 pop FS
+0f a2 cpuid
+0f a3 bt Ev,Gv
+0f a4 shld Ev,Gv,Ib
+0f a5 shld Ev,Gv,CL
+0f a8 d64 push GS
+0f a9 d64 pop GS
+0f aa rsm
+0f ab bts Ev,Gv
+0f ac shrd Ev,Gv,Ib
+0f ad shrd Ev,Gv,CL
+NP 0f ae /0 mem W0 fxsave M
+NP 0f ae /0 mem W1 fxsave64 M
+NP 0f ae /1 mem W0 fxrstor M
+NP 0f ae /1 mem W1 fxrstor64 M
+LV NP 0f ae /2 mem vldmxcsr Md
+LV NP 0f ae /3 mem vstmxcsr Md
+NP 0f ae /4 mem W0 xsave M
+NP 0f ae /4 mem W1 xsave64 M
+NP 0f ae /5 mem W0 xrstor M
+NP 0f ae /5 mem W1 xrstor64 M
+NP 0f ae /6 mem W0 xsaveopt M
+NP 0f ae /6 mem W1 xsaveopt64 M
+NP 0f ae /7 mem clflush Mb
+66 0f ae /6 mem clwb Mb
+66 0f ae /7 mem clflushopt Mb
+NP 0f ae /5 reg lfence
+NP 0f ae /6 reg mfence
+NP 0f ae /7 reg sfence
+F3 0f ae /0 reg x64 rdfsbase Ry
+F3 0f ae /1 reg x64 rdgsbase Ry
+F3 0f ae /2 reg x64 wrfsbase Ry
+F3 0f ae /3 reg x64 wrgsbase Ry
+F3 0f ae /5 reg W0 incsspd Rd
+F3 0f ae /5 reg W1 incsspq Rq
+66 0f ae /6 reg tpause Rd
+F3 0f ae /6 reg umonitor Rv
+F2 0f ae /6 reg umwait Rd
+0f af imul Gv,Ev
+0f b0 cmpxchg Eb,Gb
+0f b1 cmpxchg Ev,Gv
+0f b2 lss Gv,Mp
+0f b3 btr Ev,Gv
+0f b4 lfs Gv,Mp
+0f b5 lgs Gv,Mp
+0f b6 movzx Gv,Eb
+0f b7 movzx Gv,Ew
+F3 0f b8 popcnt Gv,Ev
+0f b9 ud1 Gv,Ev
+0f ba /4 bt Ev,Ib
+0f ba /5 bts Ev,Ib
+0f ba /6 btr Ev,Ib
+0f ba /7 btc Ev,Ib
+0f bb btc Ev,Gv
+0f bc bsf Gv,Ev
+F3 0f bc tzcnt Gv,Ev
+0f bd bsr Gv,Ev
+F3 0f bd lzcnt Gv,Ev
+0f be movsx Gv,Eb
+0f bf movsx Gv,Ew
+0f c0 xadd Eb,Gb
+0f c1 xadd Ev,Gv
+LV NP 0f c2 vcmpps Vps,Hps,Wps,Ib
+E NP 0f c2 vcmpps KG,Hps,Wps,Ib
+LV 66 0f c2 vcmppd Vpd,Hpd,Wpd,Ib
+E 66 0f c2 vcmppd KG,Hpd,Wpd,Ib
+LV F3 0f c2 vcmpss Vss,Hss,Wss,Ib
+E F3 0f c2 vcmpss KG,Hss,Wss,Ib
+LV F2 0f c2 vcmpsd Vsd,Hsd,Wsd,Ib
+E F2 0f c2 vcmpsd KG,Hsd,Wsd,Ib
+NP 0f c3 mem movnti My,Gy
+NP 0f c4 pinsrw Pq,Rd/Mw,Ib
+LVE 66 0f c4 vpinsrw Vdq,Hdq,Rd/Mw,Ib
+NP 0f c5 reg pextrw Gd,Nq,Ib
+LVE 66 0f c5 reg vpextrw Gd,Udq,Ib
+LVE NP 0f c6 vshufps Vps,Hps,Wps,Ib
+LVE 66 0f c6 vshufpd Vpd,Hpd,Wpd,Ib
+0f c7 /1 mem W0 cmpxchg8b Mq
+0f c7 /1 mem W1 cmpxchg16b Mdq
+0f c7 /3 mem W0 xrstors M
+0f c7 /3 mem W1 xrstors64 M
+0f c7 /4 mem W0 xsavec M
+0f c7 /4 mem W1 xsavec64 M
+0f c7 /5 mem W0 xsaves M
+0f c7 /5 mem W1 xsaves64 M
+NP 0f c7 /6 mem vmptrld Mq
+66 0f c7 /6 mem vmclear Mq
+F3 0f c7 /6 mem vmxon Mq
+NP 0f c7 /7 mem vmptrst Mq
+0f c7 /6 reg rdrand Rv
+0f c7 /7 reg rdseed Rv
+F3 0f c7 /7 reg f64 rdpid Ry
+0f c8+r bswap Zy
+
+LV 66 0f d0 vaddsubpd Vpd,Hpd,Wpd
+LV F2 0f d0 vaddsubps Vps,Hps,Wps
+NP 0f d1 psrlw Pq,Qq
+LVE 66 0f d1 vpsrlw Vx,Hx,Wdq
+NP 0f d2 psrld Pq,Qq
+LVE 66 0f d2 vpsrld Vx,Hx,Wdq
+NP 0f d3 psrlq Pq,Qq
+LVE 66 0f d3 vpsrlq Vx,Hx,Wdq
+NP 0f d4 paddq Pq,Qq
+LVE 66 0f d4 vpaddq Vx,Hx,Wx
+NP 0f d5 pmullw Pq,Qq
+LVE 66 0f d5 vpmullw Vx,Hx,Wx
+LVE 66 0f d6 vmovq Wq,Vdq
+F3 0f d6 reg movq2dq Vdq,Nq
+F2 0f d6 reg movdq2q Pq,Uq
+NP 0f d7 reg pmovmskb Gd,Nq
+LV 66 0f d7 reg vpmovmskb Gd,Ux
+NP 0f d8 psubusb Pq,Qq
+LVE 66 0f d8 vpsubusb Vx,Hx,Wx
+NP 0f d9 psubusw Pq,Qq
+LVE 66 0f d9 vpsubusw Vx,Hx,Wx
+NP 0f da pminub Pq,Qq
+LVE 66 0f da vpminub Vx,Hx,Wx
+NP 0f db pand Pq,Qq
+LV 66 0f db vpand Vx,Hx,Wx
+E 66 0f db W0 vpandd Vx,Hx,Wx
+E 66 0f db W1 vpandq Vx,Hx,Wx
+NP 0f dc paddusb Pq,Qq
+LVE 66 0f dc vpaddusb Vx,Hx,Wx
+NP 0f dd paddusw Pq,Qq
+LVE 66 0f dd vpaddusw Vx,Hx,Wx
+NP 0f de pmaxub Pq,Qq
+LVE 66 0f de vpmaxub Vx,Hx,Wx
+NP 0f df pandn Pq,Qq
+LV 66 0f df vpandn Vx,Hx,Wx
+E 66 0f df W0 vpandnd Vx,Hx,Wx
+E 66 0f df W1 vpandnq Vx,Hx,Wx
+NP 0f e0 pavgb Pq,Qq
+LVE 66 0f e0 vpavgb Vx,Hx,Wx
+NP 0f e1 psraw Pq,Qq
+LVE 66 0f e1 vpsraw Vx,Hx,Wdq
+NP 0f e2 psrad Pq,Qq
+LVE 66 0f e2 vpsrad Vx,Hx,Wdq
+E 66 0f e2 W1 vpsraq Vx,Hx,Wdq
+NP 0f e3 pavgw Pq,Qq
+LVE 66 0f e3 vpavgw Vx,Hx,Wx
+NP 0f e4 pmulhuw Pq,Qq
+LVE 66 0f e4 vpmulhuw Vx,Hx,Wx
+NP 0f e5 pmulhw Pq,Qq
+LVE 66 0f e5 vpmulhw Vx,Hx,Wx
+LVE 66 0f e6 vcvttpd2dq Vh,Wpd
+LVE F3 0f e6 vcvtdq2pd Vx,Wh
+E F3 0f e6 W1 vcvtqq2pd Vx,Wx
+LVE F2 0f e6 vcvtpd2dq Vh,Wpd
+NP 0f e7 mem movntq Mq,Pq
+LVE 66 0f e7 mem vmovntdq Mx,Vx
+NP 0f e8 psubsb Pq,Qq
+LVE 66 0f e8 vpsubsb Vx,Hx,Wx
+NP 0f e9 psubsw Pq,Qq
+LVE 66 0f e9 vpsubsw Vx,Hx,Wx
+NP 0f ea pminsw Pq,Qq
+LVE 66 0f ea vpminsw Vx,Hx,Wx
+NP 0f eb por Pq,Qq
+LV 66 0f eb vpor Vx,Hx,Wx
+E 66 0f eb W0 vpord Vx,Hx,Wx
+E 66 0f eb W1 vporq Vx,Hx,Wx
+NP 0f ec paddsb Pq,Qq
+LVE 66 0f ec vpaddsb Vx,Hx,Wx
+NP 0f ed paddsw Pq,Qq
+LVE 66 0f ed vpaddsw Vx,Hx,Wx
+NP 0f ee pmaxsw Pq,Qq
+LVE 66 0f ee vpmaxsw Vx,Hx,Wx
+NP 0f ef pxor Pq,Qq
+LV 66 0f ef vpxor Vx,Hx,Wx
+E 66 0f ef W0 vpxord Vx,Hx,Wx
+E 66 0f ef W1 vpxorq Vx,Hx,Wx
+LV F2 0f f0 mem vlddqu Vx,Mx
+NP 0f f1 psllw Pq,Qq
+LVE 66 0f f1 vpsllw Vx,Hx,Wdq
+NP 0f f2 pslld Pq,Qq
+LVE 66 0f f2 vpslld Vx,Hx,Wdq
+NP 0f f3 psllq Pq,Qq
+LVE 66 0f f3 vpsllq Vx,Hx,Wdq
+NP 0f f4 pmuludq Pq,Qq
+LVE 66 0f f4 vpmuludq Vx,Hx,Wx
+NP 0f f5 pmaddwd Pq,Qq
+LVE 66 0f f5 vpmaddwd Vx,Hx,Wx
+NP 0f f6 psadbw Pq,Qq
+LVE 66 0f f6 vpsadbw Vx,Hx,Wx
+NP 0f f7 reg maskmovq Pq,Nq
+LV 66 0f f7 reg vmaskmovdqu Vdq,Udq
+NP 0f f8 psubb Pq,Qq
+LVE 66 0f f8 vpsubb Vx,Hx,Wx
+NP 0f f9 psubw Pq,Qq
+LVE 66 0f f9 vpsubw Vx,Hx,Wx
+NP 0f fa psubd Pq,Qq
+LVE 66 0f fa vpsubd Vx,Hx,Wx
+NP 0f fb psubq Pq,Qq
+LVE 66 0f fb vpsubq Vx,Hx,Wx
+NP 0f fc paddb Pq,Qq
+LVE 66 0f fc vpaddb Vx,Hx,Wx
+NP 0f fd paddw Pq,Qq
+LVE 66 0f fd vpaddw Vx,Hx,Wx
+NP 0f fe paddd Pq,Qq
+LVE 66 0f fe vpaddd Vx,Hx,Wx
+0f ff ud0 Gv,Ev
+
+# Three-byte opcode map (0F 38 xx)
+NP 0f 38 00 pshufb Pq,Qq
+LVE 66 0f 38 00 vpshufb Vx,Hx,Wx
+NP 0f 38 01 phaddw Pq,Qq
+LV 66 0f 38 01 vphaddw Vx,Hx,Wx
+NP 0f 38 02 phaddd Pq,Qq
+LV 66 0f 38 02 vphaddd Vx,Hx,Wx
+NP 0f 38 03 phaddsw Pq,Qq
+LV 66 0f 38 03 vphaddsw Vx,Hx,Wx
+NP 0f 38 04 pmaddubsw Pq,Qq
+LVE 66 0f 38 04 vpmaddubsw Vx,Hx,Wx
+NP 0f 38 05 phsubw Pq,Qq
+LV 66 0f 38 05 vphsubw Vx,Hx,Wx
+NP 0f 38 06 phsubd Pq,Qq
+LV 66 0f 38 06 vphsubd Vx,Hx,Wx
+NP 0f 38 07 phsubsw Pq,Qq
+LV 66 0f 38 07 vphsubsw Vx,Hx,Wx
+NP 0f 38 08 psignb Pq,Qq
+LV 66 0f 38 08 vpsignb Vx,Hx,Wx
+NP 0f 38 09 psignw Pq,Qq
+LV 66 0f 38 09 vpsignw Vx,Hx,Wx
+NP 0f 38 0a psignd Pq,Qq
+LV 66 0f 38 0a vpsignd Vx,Hx,Wx
+NP 0f 38 0b pmulhrsw Pq,Qq
+LVE 66 0f 38 0b vpmulhrsw Vx,Hx,Wx
+VE 66 0f 38 0c W0 vpermilps Vx,Hx,Wx
+VE 66 0f 38 0d W0 vpermilpd Vx,Hx,Wx
+E 66 0f 38 0d W1 vpermilpd Vx,Hx,Wx
+V 66 0f 38 0e W0 vtestps Vx,Wx
+V 66 0f 38 0f W0 vtestpd Vx,Wx
+66 0f 38 10 pblendvb Vdq,Wdq,XMM0
+E 66 0f 38 10 W1 vpsrlvw Vx,Hx,Wx
+E F3 0f 38 10 W0 vpmovuswb Wh,Vx
+E F3 0f 38 11 W0 vpmovusdb Wqr,Vx
+E 66 0f 38 11 W1 vpsravw Vx,Hx,Wx
+E F3 0f 38 12 W0 vpmovusqb Woc,Vx
+E 66 0f 38 12 W1 vpsllvw Vx,Hx,Wx
+VE 66 0f 38 13 W0 vcvtph2ps Vx,Wh
+E F3 0f 38 13 W0 vpmovusdw Wh,Vx
+66 0f 38 14 blendvps Vdq,Wdq,XMM0
+E F3 0f 38 14 W0 vpmovusqw Wqr,Vx
+E 66 0f 38 14 W0 vprorvd Vx,Hx,Wx
+E 66 0f 38 14 W1 vprorvq Vx,Hx,Wx
+66 0f 38 15 blendvpd Vdq,Wdq,XMM0
+E F3 0f 38 15 W0 vpmovusqd Wh,Vx
+E 66 0f 38 15 W0 vprolvd Vx,Hx,Wx
+E 66 0f 38 15 W1 vprolvq Vx,Hx,Wx
+VE 66 0f 38 16 W0 vpermps Vx,Hx,Wx
+E 66 0f 38 16 W1 vpermpd Vx,Hx,Wx
+LV 66 0f 38 17 vptest Vx,Wx
+VE 66 0f 38 18 W0 vbroadcastss Vx,Wd
+V 66 0f 38 19 W0 L1 vbroadcastsd Vqq,Wq
+E 66 0f 38 19 W0 vbroadcastf32x2 Vx,Wq
+E 66 0f 38 19 W1 vbroadcastsd Vx,Wq
+V 66 0f 38 1a W0 L1 mem vbroadcastf128 Vqq,Mdq
+E 66 0f 38 1a W0 mem vbroadcastf32x4 Vx,Mdq
+E 66 0f 38 1a W1 mem vbroadcastf64x2 Vx,Mdq
+E 66 0f 38 1b W0 mem vbroadcastf32x8 Vx,Mqq
+E 66 0f 38 1b W1 mem vbroadcastf64x4 Vx,Mqq
+NP 0f 38 1c pabsb Pq,Qq
+LVE 66 0f 38 1c vpabsb Vx,Wx
+NP 0f 38 1d pabsw Pq,Qq
+LVE 66 0f 38 1d vpabsw Vx,Wx
+NP 0f 38 1e pabsd Pq,Qq
+LVE 66 0f 38 1e vpabsd Vx,Wx
+E 66 0f 38 1f W1 vpabsq Vx,Wx
+LVE 66 0f 38 20 vpmovsxbw Vx,Wh
+E F3 0f 38 20 W0 vpmovswb Wh,Vx
+LVE 66 0f 38 21 vpmovsxbd Vx,Wqr
+E F3 0f 38 21 W0 vpmovsdb Wqr,Vx
+LVE 66 0f 38 22 vpmovsxbq Vx,Woc
+E F3 0f 38 22 W0 vpmovsqb Woc,Vx
+LVE 66 0f 38 23 vpmovsxwd Vx,Wh
+E F3 0f 38 23 W0 vpmovsdw Wh,Vx
+LVE 66 0f 38 24 vpmovsxwq Vx,Wqr
+E F3 0f 38 24 W0 vpmovsqw Wqr,Vx
+LVE 66 0f 38 25 vpmovsxdq Vx,Wh
+E F3 0f 38 25 W0 vpmovsqd Wh,Vx
+E 66 0f 38 26 W0 vptestmb KG,Hx,Wx
+E 66 0f 38 26 W1 vptestmw KG,Hx,Wx
+E F3 0f 38 26 W0 vptestnmb KG,Hx,Wx
+E F3 0f 38 26 W1 vptestnmw KG,Hx,Wx
+E 66 0f 38 27 W0 vptestmd KG,Hx,Wx
+E 66 0f 38 27 W1 vptestmq KG,Hx,Wx
+E F3 0f 38 27 W0 vptestnmd KG,Hx,Wx
+E F3 0f 38 27 W1 vptestnmq KG,Hx,Wx
+LVE 66 0f 38 28 vpmuldq Vx,Hx,Wx
+E F3 0f 38 28 W0 reg vpmovm2b Vx,KR
+E F3 0f 38 28 W1 reg vpmovm2w Vx,KR
+LV 66 0f 38 29 vpcmpeqq Vx,Hx,Wx
+E 66 0f 38 29 vpcmpeqq KG,Hx,Wx
+E F3 0f 38 29 W0 reg vpmovb2m KG,Ux
+E F3 0f 38 29 W1 reg vpmovw2m KG,Ux
+LVE 66 0f 38 2a mem vmovntdqa Vx,Mx
+E F3 0f 38 2a W1 reg vpbroadcastmb2q Vx,KR
+LVE 66 0f 38 2b vpackusdw Vx,Hx,Wx
+V 66 0f 38 2c W0 mem vmaskmovps Vx,Hx,Mx
+E 66 0f 38 2c W0 vscalefps Vx,Hx,Wx
+E 66 0f 38 2c W1 vscalefpd Vx,Hx,Wx
+V 66 0f 38 2d W0 mem vmaskmovpd Vx,Hx,Mx
+E 66 0f 38 2d W0 vscalefss Vss,Hss,Wss
+E 66 0f 38 2d W1 vscalefsd Vsd,Hsd,Wsd
+V 66 0f 38 2e W0 mem vmaskmovps Mx,Hx,Vx
+V 66 0f 38 2f W0 mem vmaskmovpd Mx,Hx,Vx
+LVE 66 0f 38 30 vpmovzxbw Vx,Wh
+E F3 0f 38 30 W0 vpmovwb Wh,Vx
+LVE 66 0f 38 31 vpmovzxbd Vx,Wqr
+E F3 0f 38 31 W0 vpmovdb Wqr,Vx
+LVE 66 0f 38 32 vpmovzxbq Vx,Woc
+E F3 0f 38 32 W0 vpmovqb Woc,Vx
+LVE 66 0f 38 33 vpmovzxwd Vx,Wh
+E F3 0f 38 33 W0 vpmovdw Wh,Vx
+LVE 66 0f 38 34 vpmovzxwq Vx,Wqr
+E F3 0f 38 34 W0 vpmovqw Wqr,Vx
+LVE 66 0f 38 35 vpmovzxdq Vx,Wh
+E F3 0f 38 35 W0 vpmovqd Wh,Vx
+VE 66 0f 38 36 W0 vpermd Vx,Hx,Wx
+E 66 0f 38 36 W1 vpermq Vx,Hx,Wx
+LV 66 0f 38 37 vpcmpgtq Vx,Hx,Wx
+E 66 0f 38 37 vpcmpgtq KG,Hx,Wx
+LVE 66 0f 38 38 vpminsb Vx,Hx,Wx
+E F3 0f 38 38 W0 reg vpmovm2d Vx,KR
+E F3 0f 38 38 W1 reg vpmovm2q Vx,KR
+LVE 66 0f 38 39 vpminsd Vx,Hx,Wx
+E 66 0f 38 39 W1 vpminsq Vx,Hx,Wx
+E F3 0f 38 39 W0 reg vpmovd2m KG,Ux
+E F3 0f 38 39 W1 reg vpmovq2m KG,Ux
+LVE 66 0f 38 3a vpminuw Vx,Hx,Wx
+E F3 0f 38 3a W0 reg vpbroadcastmw2d Vx,KR
+LVE 66 0f 38 3b vpminud Vx,Hx,Wx
+E 66 0f 38 3b W1 vpminuq Vx,Hx,Wx
+LVE 66 0f 38 3c vpmaxsb Vx,Hx,Wx
+LVE 66 0f 38 3d vpmaxsd Vx,Hx,Wx
+E 66 0f 38 3d W1 vpmaxsq Vx,Hx,Wx
+LVE 66 0f 38 3e vpmaxuw Vx,Hx,Wx
+LVE 66 0f 38 3f vpmaxud Vx,Hx,Wx
+E 66 0f 38 3f W1 vpmaxuq Vx,Hx,Wx
+LVE 66 0f 38 40 vpmulld Vx,Hx,Wx
+E 66 0f 38 40 W1 vpmullq Vx,Hx,Wx
+LV 66 0f 38 41 vphminposuw Vdq,Wdq
+E 66 0f 38 42 W0 vgetexpps Vx,Wx
+E 66 0f 38 42 W1 vgetexppd Vx,Wx
+E 66 0f 38 43 W0 vgetexpss Vss,Hss,Wss
+E 66 0f 38 43 W1 vgetexpsd Vsd,Hsd,Wsd
+E 66 0f 38 44 W0 vplzcntd Vx,Wx
+E 66 0f 38 44 W1 vplzcntq Vx,Wx
+VE 66 0f 38 45 W0 vpsrlvd Vx,Hx,Wx
+VE 66 0f 38 45 W1 vpsrlvq Vx,Hx,Wx
+VE 66 0f 38 46 W0 vpsravd Vx,Hx,Wx
+E 66 0f 38 46 W1 vpsravq Vx,Hx,Wx
+VE 66 0f 38 47 W0 vpsllvd Vx,Hx,Wx
+VE 66 0f 38 47 W1 vpsllvq Vx,Hx,Wx
+E 66 0f 38 4c W0 vrcp14ps Vx,Wx
+E 66 0f 38 4c W1 vrcp14pd Vx,Wx
+E 66 0f 38 4d W0 vrcp14ss Vss,Hss,Wss
+E 66 0f 38 4d W1 vrcp14sd Vsd,Hsd,Wsd
+E 66 0f 38 4e W0 vrsqrt14ps Vx,Wx
+E 66 0f 38 4e W1 vrsqrt14pd Vx,Wx
+E 66 0f 38 4f W0 vrsqrt14ss Vss,Hss,Wss
+E 66 0f 38 4f W1 vrsqrt14sd Vsd,Hsd,Wsd
+VE 66 0f 38 50 W0 vpdpbusd Vx,Hx,Wx
+VE 66 0f 38 51 W0 vpdpbusds Vx,Hx,Wx
+VE 66 0f 38 52 W0 vpdpwssd Vx,Hx,Wx
+VE 66 0f 38 53 W0 vpdpwssds Vx,Hx,Wx
+E 66 0f 38 54 W0 vpopcntb Vx,Wx
+E 66 0f 38 54 W1 vpopcntw Vx,Wx
+E 66 0f 38 55 W0 vpopcntd Vx,Wx
+E 66 0f 38 55 W1 vpopcntq Vx,Wx
+V 66 0f 38 58 W0 vpbroadcastd Vx,Wd
+E 66 0f 38 58 W0 vpbroadcastd Vx,Wd
+V 66 0f 38 59 W0 vpbroadcastq Vx,Wq
+E 66 0f 38 59 W0 vbroadcasti32x2 Vx,Wq
+E 66 0f 38 59 W1 vpbroadcastq Vx,Wq
+V 66 0f 38 5a W0 L1 mem vbroadcasti128 Vqq,Mdq
+E 66 0f 38 5a W0 mem vbroadcasti32x4 Vx,Mdq
+E 66 0f 38 5a W1 mem vbroadcasti64x2 Vx,Mdq
+E 66 0f 38 5b W0 mem vbroadcasti32x8 Vx,Mqq
+E 66 0f 38 5b W1 mem vbroadcasti64x4 Vx,Mqq
+E 66 0f 38 62 W0 vpexpandb Vx,Wx
+E 66 0f 38 62 W1 vpexpandw Vx,Wx
+E 66 0f 38 63 W0 vpcompressb Wx,Vx
+E 66 0f 38 63 W1 vpcompressw Wx,Vx
+E 66 0f 38 64 W0 vpblendmd Vx,Hx,Wx
+E 66 0f 38 64 W1 vpblendmq Vx,Hx,Wx
+E 66 0f 38 65 W0 vblendmps Vx,Hx,Wx
+E 66 0f 38 65 W1 vblendmpd Vx,Hx,Wx
+E 66 0f 38 66 W0 vpblendmb Vx,Hx,Wx
+E 66 0f 38 66 W1 vpblendmw Vx,Hx,Wx
+E 66 0f 38 70 W1 vpshldvw Vx,Hx,Wx
+E 66 0f 38 71 W0 vpshldvd Vx,Hx,Wx
+E 66 0f 38 71 W1 vpshldvq Vx,Hx,Wx
+E 66 0f 38 72 W1 vpshrdvw Vx,Hx,Wx
+E 66 0f 38 73 W0 vpshrdvd Vx,Hx,Wx
+E 66 0f 38 73 W1 vpshrdvq Vx,Hx,Wx
+E 66 0f 38 75 W0 vpermi2b Vx,Hx,Wx
+E 66 0f 38 75 W1 vpermi2w Vx,Hx,Wx
+E 66 0f 38 76 W0 vpermi2d Vx,Hx,Wx
+E 66 0f 38 76 W1 vpermi2q Vx,Hx,Wx
+E 66 0f 38 77 W0 vpermi2ps Vx,Hx,Wx
+E 66 0f 38 77 W1 vpermi2pd Vx,Hx,Wx
+VE 66 0f 38 78 W0 vpbroadcastb Vx,Wb
+VE 66 0f 38 79 W0 vpbroadcastw Vx,Ww
+E 66 0f 38 7a W0 reg vpbroadcastb Vx,Rd
+E 66 0f 38 7b W0 reg vpbroadcastw Vx,Rd
+E 66 0f 38 7c W0 reg vpbroadcastd Vx,Rd
+E 66 0f 38 7c W1 reg vpbroadcastq Vx,Rq
+E 66 0f 38 7d W0 vpermt2b Vx,Hx,Wx
+E 66 0f 38 7d W1 vpermt2w Vx,Hx,Wx
+E 66 0f 38 7e W0 vpermt2d Vx,Hx,Wx
+E 66 0f 38 7e W1 vpermt2q Vx,Hx,Wx
+E 66 0f 38 7f W0 vpermt2ps Vx,Hx,Wx
+E 66 0f 38 7f W1 vpermt2pd Vx,Hx,Wx
+66 0f 38 80 mem invept Gy,Mdq
+66 0f 38 81 mem invvpid Gy,Mdq
+66 0f 38 82 mem invpcid Gy,Mdq
+E 66 0f 38 83 W1 vpmultishiftqb Vx,Hx,Wx
+E 66 0f 38 88 W0 vexpandps Vx,Wx
+E 66 0f 38 88 W1 vexpandpd Vx,Wx
+E 66 0f 38 89 W0 vpexpandd Vx,Wx
+E 66 0f 38 89 W1 vpexpandq Vx,Wx
+E 66 0f 38 8a W0 vcompressps Wx,Vx
+E 66 0f 38 8a W1 vcompresspd Wx,Vx
+E 66 0f 38 8b W0 vpcompressd Wx,Vx
+E 66 0f 38 8b W1 vpcompressq Wx,Vx
+V 66 0f 38 8c W0 mem vpmaskmovd Vx,Hx,Mx
+V 66 0f 38 8c W1 mem vpmaskmovq Vx,Hx,Mx
+E 66 0f 38 8d W0 vpermb Vx,Hx,Wx
+E 66 0f 38 8d W1 vpermw Vx,Hx,Wx
+V 66 0f 38 8e W0 mem vpmaskmovd Mx,Hx,Vx
+V 66 0f 38 8e W1 mem vpmaskmovq Mx,Hx,Vx
+E 66 0f 38 8f W0 vpshufbitqmb KG,Hx,Wx
+V 66 0f 38 90 W0 mem vpgatherdd Vx,MVd,Hx
+V 66 0f 38 90 W1 mem vpgatherdq Vx,MVd,Hx
+V 66 0f 38 91 W0 mem vpgatherqd Vh,MVq,Hh
+V 66 0f 38 91 W1 mem vpgatherqq Vx,MVq,Hx
+V 66 0f 38 92 W0 mem vgatherdps Vx,MVd,Hx
+V 66 0f 38 92 W1 mem vgatherdpd Vx,MVd,Hx
+V 66 0f 38 93 W0 mem vgatherqps Vh,MVq,Hh
+V 66 0f 38 93 W1 mem vgatherqpd Vx,MVq,Hx
+E 66 0f 38 90 W0 mem vpgatherdd Vx,MVd
+E 66 0f 38 90 W1 mem vpgatherdq Vx,MVd
+E 66 0f 38 91 W0 mem vpgatherqd Vh,MVq
+E 66 0f 38 91 W1 mem vpgatherqq Vx,MVq
+E 66 0f 38 92 W0 mem vgatherdps Vx,MVd
+E 66 0f 38 92 W1 mem vgatherdpd Vx,MVd
+E 66 0f 38 93 W0 mem vgatherqps Vh,MVq
+E 66 0f 38 93 W1 mem vgatherqpd Vx,MVq
+VE 66 0f 38 96 W0 vfmaddsub132ps Vx,Hx,Wx
+VE 66 0f 38 96 W1 vfmaddsub132pd Vx,Hx,Wx
+VE 66 0f 38 97 W0 vfmsubadd132ps Vx,Hx,Wx
+VE 66 0f 38 97 W1 vfmsubadd132pd Vx,Hx,Wx
+VE 66 0f 38 98 W0 vfmadd132ps Vx,Hx,Wx
+VE 66 0f 38 98 W1 vfmadd132pd Vx,Hx,Wx
+VE 66 0f 38 99 W0 vfmadd132ss Vss,Hss,Wss
+VE 66 0f 38 99 W1 vfmadd132sd Vsd,Hsd,Wsd
+VE 66 0f 38 9a W0 vfmsub132ps Vx,Hx,Wx
+VE 66 0f 38 9a W1 vfmsub132pd Vx,Hx,Wx
+VE 66 0f 38 9b W0 vfmsub132ss Vss,Hss,Wss
+VE 66 0f 38 9b W1 vfmsub132sd Vsd,Hsd,Wsd
+VE 66 0f 38 9c W0 vfnmadd132ps Vx,Hx,Wx
+VE 66 0f 38 9c W1 vfnmadd132pd Vx,Hx,Wx
+VE 66 0f 38 9d W0 vfnmadd132ss Vss,Hss,Wss
+VE 66 0f 38 9d W1 vfnmadd132sd Vsd,Hsd,Wsd
+VE 66 0f 38 9e W0 vfnmsub132ps Vx,Hx,Wx
+VE 66 0f 38 9e W1 vfnmsub132pd Vx,Hx,Wx
+VE 66 0f 38 9f W0 vfnmsub132ss Vss,Hss,Wss
+VE 66 0f 38 9f W1 vfnmsub132sd Vsd,Hsd,Wsd
+E 66 0f 38 a0 W0 mem vpscatterdd MVd,Vx
+E 66 0f 38 a0 W1 mem vpscatterdq MVd,Vx
+E 66 0f 38 a1 W0 mem vpscatterqd MVq,Vh
+E 66 0f 38 a1 W1 mem vpscatterqq MVq,Vx
+E 66 0f 38 a2 W0 mem vscatterdps MVd,Vx
+E 66 0f 38 a2 W1 mem vscatterdpd MVd,Vx
+E 66 0f 38 a3 W0 mem vscatterqps MVq,Vh
+E 66 0f 38 a3 W1 mem vscatterqpd MVq,Vx
+VE 66 0f 38 a6 W0 vfmaddsub213ps Vx,Hx,Wx
+VE 66 0f 38 a6 W1 vfmaddsub213pd Vx,Hx,Wx
+VE 66 0f 38 a7 W0 vfmsubadd213ps Vx,Hx,Wx
+VE 66 0f 38 a7 W1 vfmsubadd213pd Vx,Hx,Wx
+VE 66 0f 38 a8 W0 vfmadd213ps Vx,Hx,Wx
+VE 66 0f 38 a8 W1 vfmadd213pd Vx,Hx,Wx
+VE 66 0f 38 a9 W0 vfmadd213ss Vss,Hss,Wss
+VE 66 0f 38 a9 W1 vfmadd213sd Vsd,Hsd,Wsd
+VE 66 0f 38 aa W0 vfmsub213ps Vx,Hx,Wx
+VE 66 0f 38 aa W1 vfmsub213pd Vx,Hx,Wx
+VE 66 0f 38 ab W0 vfmsub213ss Vss,Hss,Wss
+VE 66 0f 38 ab W1 vfmsub213sd Vsd,Hsd,Wsd
+VE 66 0f 38 ac W0 vfnmadd213ps Vx,Hx,Wx
+VE 66 0f 38 ac W1 vfnmadd213pd Vx,Hx,Wx
+VE 66 0f 38 ad W0 vfnmadd213ss Vss,Hss,Wss
+VE 66 0f 38 ad W1 vfnmadd213sd Vsd,Hsd,Wsd
+VE 66 0f 38 ae W0 vfnmsub213ps Vx,Hx,Wx
+VE 66 0f 38 ae W1 vfnmsub213pd Vx,Hx,Wx
+VE 66 0f 38 af W0 vfnmsub213ss Vss,Hss,Wss
+VE 66 0f 38 af W1 vfnmsub213sd Vsd,Hsd,Wsd
+E 66 0f 38 b4 W1 vpmadd52luq Vx,Hx,Wx
+E 66 0f 38 b5 W1 vpmadd52huq Vx,Hx,Wx
+VE 66 0f 38 b6 W0 vfmaddsub231ps Vx,Hx,Wx
+VE 66 0f 38 b6 W1 vfmaddsub231pd Vx,Hx,Wx
+VE 66 0f 38 b7 W0 vfmsubadd231ps Vx,Hx,Wx
+VE 66 0f 38 b7 W1 vfmsubadd231pd Vx,Hx,Wx
+VE 66 0f 38 b8 W0 vfmadd231ps Vx,Hx,Wx
+VE 66 0f 38 b8 W1 vfmadd231pd Vx,Hx,Wx
+VE 66 0f 38 b9 W0 vfmadd231ss Vss,Hss,Wss
+VE 66 0f 38 b9 W1 vfmadd231sd Vsd,Hsd,Wsd
+VE 66 0f 38 ba W0 vfmsub231ps Vx,Hx,Wx
+VE 66 0f 38 ba W1 vfmsub231pd Vx,Hx,Wx
+VE 66 0f 38 bb W0 vfmsub231ss Vss,Hss,Wss
+VE 66 0f 38 bb W1 vfmsub231sd Vsd,Hsd,Wsd
+VE 66 0f 38 bc W0 vfnmadd231ps Vx,Hx,Wx
+VE 66 0f 38 bc W1 vfnmadd231pd Vx,Hx,Wx
+VE 66 0f 38 bd W0 vfnmadd231ss Vss,Hss,Wss
+VE 66 0f 38 bd W1 vfnmadd231sd Vsd,Hsd,Wsd
+VE 66 0f 38 be W0 vfnmsub231ps Vx,Hx,Wx
+VE 66 0f 38 be W1 vfnmsub231pd Vx,Hx,Wx
+VE 66 0f 38 bf W0 vfnmsub231ss Vss,Hss,Wss
+VE 66 0f 38 bf W1 vfnmsub231sd Vsd,Hsd,Wsd
+E 66 0f 38 c4 W0 vpconflictd Vx,Wx
+E 66 0f 38 c4 W1 vpconflictq Vx,Wx
+E 66 0f 38 c8 W0 vexp2ps Vx,Wx
+E 66 0f 38 c8 W1 vexp2pd Vx,Wx
+NP 0f 38 c8 sha1nexte Vdq,Wdq
+NP 0f 38 c9 sha1msg1 Vdq,Wdq
+NP 0f 38 ca sha1msg2 Vdq,Wdq
+E 66 0f 38 ca W0 vrcp28ps Vx,Wx
+E 66 0f 38 ca W1 vrcp28pd Vx,Wx
+NP 0f 38 cb sha256rnds2 Vdq,Wdq,XMM0
+E 66 0f 38 cb W0 vrcp28ss Vss,Hss,Wss
+E 66 0f 38 cb W1 vrcp28sd Vsd,Hsd,Wsd
+NP 0f 38 cc sha256msg1 Vdq,Wdq
+E 66 0f 38 cc W0 vrsqrt28ps Vx,Wx
+E 66 0f 38 cc W1 vrsqrt28pd Vx,Wx
+NP 0f 38 cd sha256msg2 Vdq,Wdq
+E 66 0f 38 cd W0 vrsqrt28ss Vss,Hss,Wss
+E 66 0f 38 cd W1 vrsqrt28sd Vsd,Hsd,Wsd
+LVE 66 0f 38 cf vgf2p8mulb Vx,Hx,Wx
+LV 66 0f 38 db vaesimc Vdq,Wdq
+LVE 66 0f 38 dc vaesenc Vx,Hx,Wx
+LVE 66 0f 38 dd vaesenclast Vx,Hx,Wx
+LVE 66 0f 38 de vaesdec Vx,Hx,Wx
+LVE 66 0f 38 df vaesdeclast Vx,Hx,Wx
+NP 0f 38 f0 mem movbe Gv,Mv
+66 0f 38 f0 mem movbe Gv,Mv
+F2 0f 38 f0 crc32 Gy,Eb
+NP 0f 38 f1 mem movbe Mv,Gv
+66 0f 38 f1 mem movbe Mv,Gv
+F2 0f 38 f1 crc32 Gy,Ev
+V NP 0f 38 f2 L0 andn Gy,By,Ey
+V NP 0f 38 f3 /1 L0 blsr By,Ey
+V NP 0f 38 f3 /2 L0 blsmsk By,Ey
+V NP 0f 38 f3 /3 L0 blsi By,Ey
+V NP 0f 38 f5 L0 bzhi Gy,Ey,By
+V F3 0f 38 f5 L0 pext Gy,By,Ey
+V F2 0f 38 f5 L0 pdep Gy,By,Ey
+66 0f 38 f6 adcx Gy,Ey
+F3 0f 38 f6 adox Gy,Ey
+V F2 0f 38 f6 L0 mulx Gy,By,Ey
+V NP 0f 38 f7 L0 bextr Gy,Ey,By
+V 66 0f 38 f7 L0 shlx Gy,Ey,By
+V F3 0f 38 f7 L0 sarx Gy,Ey,By
+V F2 0f 38 f7 L0 shrx Gy,Ey,By
+
+# Three-byte opcode map (0F 3A xx)
+V 66 0f 3a 00 W1 L1 vpermq Vqq,Wqq,Ib
+E 66 0f 3a 00 W1 vpermq Vx,Wx,Ib
+V 66 0f 3a 01 W1 L1 vpermpd Vqq,Wqq,Ib
+E 66 0f 3a 01 W1 vpermpd Vx,Wx,Ib
+V 66 0f 3a 02 W0 vpblendd Vx,Hx,Wx,Ib
+E 66 0f 3a 03 W0 valignd Vx,Hx,Wx,Ib
+E 66 0f 3a 03 W1 valignq Vx,Hx,Wx,Ib
+VE 66 0f 3a 04 W0 vpermilps Vx,Wx,Ib
+VE 66 0f 3a 05 W0 vpermilpd Vx,Wx,Ib
+E 66 0f 3a 05 W1 vpermilpd Vx,Wx,Ib
+V 66 0f 3a 06 W0 L1 vperm2f128 Vqq,Hqq,Wqq,Ib
+LV 66 0f 3a 08 vroundps Vx,Wx,Ib
+E 66 0f 3a 08 W0 vrndscaleps Vx,Wx,Ib
+LV 66 0f 3a 09 vroundpd Vx,Wx,Ib
+E 66 0f 3a 09 W1 vrndscalepd Vx,Wx,Ib
+LV 66 0f 3a 0a vroundss Vss,Hss,Wss,Ib
+E 66 0f 3a 0a W0 vrndscaless Vss,Hss,Wss,Ib
+LV 66 0f 3a 0b vroundsd Vsd,Hsd,Wsd,Ib
+E 66 0f 3a 0b W1 vrndscalesd Vsd,Hsd,Wsd,Ib
+LV 66 0f 3a 0c vblendps Vx,Hx,Wx,Ib
+LV 66 0f 3a 0d vblendpd Vx,Hx,Wx,Ib
+LV 66 0f 3a 0e vpblendw Vx,Hx,Wx,Ib
+NP 0f 3a 0f palignr Pq,Qq,Ib
+LVE 66 0f 3a 0f vpalignr Vx,Hx,Wx,Ib
+LVE 66 0f 3a 14 vpextrb Rd/Mb,Vdq,Ib
+LVE 66 0f 3a 15 vpextrw Rd/Mw,Vdq,Ib
+LVE 66 0f 3a 16 W0 vpextrd Ed,Vdq,Ib
+LVE 66 0f 3a 16 W1 vpextrq Eq,Vdq,Ib
+LVE 66 0f 3a 17 vextractps Ed,Vdq,Ib
+V 66 0f 3a 18 W0 L1 vinsertf128 Vqq,Hqq,Wdq,Ib
+E 66 0f 3a 18 W0 vinsertf32x4 Vx,Hx,Wdq,Ib
+E 66 0f 3a 18 W1 vinsertf64x2 Vx,Hx,Wdq,Ib
+V 66 0f 3a 19 W0 L1 vextractf128 Wdq,Vqq,Ib
+E 66 0f 3a 19 W0 vextractf32x4 Wdq,Vx,Ib
+E 66 0f 3a 19 W1 vextractf64x2 Wdq,Vx,Ib
+E 66 0f 3a 1a W0 vinsertf32x8 Vx,Hx,Wqq,Ib
+E 66 0f 3a 1a W1 vinsertf64x4 Vx,Hx,Wqq,Ib
+E 66 0f 3a 1b W0 vextractf32x8 Wqq,Vx,Ib
+E 66 0f 3a 1b W1 vextractf64x4 Wqq,Vx,Ib
+VE 66 0f 3a 1d W0 vcvtps2ph Wh,Vx,Ib
+E 66 0f 3a 1e W0 vpcmpud KG,Hx,Wx,Ib
+E 66 0f 3a 1e W1 vpcmpuq KG,Hx,Wx,Ib
+E 66 0f 3a 1f W0 vpcmpd KG,Hx,Wx,Ib
+E 66 0f 3a 1f W1 vpcmpq KG,Hx,Wx,Ib
+LVE 66 0f 3a 20 vpinsrb Vdq,Hdq,Rd/Mb,Ib
+LVE 66 0f 3a 21 vinsertps Vdq,Hdq,Udq/Md,Ib
+LVE 66 0f 3a 22 W0 vpinsrd Vdq,Hdq,Ed,Ib
+LVE 66 0f 3a 22 W1 vpinsrq Vdq,Hdq,Eq,Ib
+E 66 0f 3a 23 W0 vshuff32x4 Vx,Hx,Wx,Ib
+E 66 0f 3a 23 W1 vshuff64x2 Vx,Hx,Wx,Ib
+E 66 0f 3a 25 W0 vpternlogd Vx,Hx,Wx,Ib
+E 66 0f 3a 25 W1 vpternlogq Vx,Hx,Wx,Ib
+E 66 0f 3a 26 W0 vgetmantps Vx,Wx,Ib
+E 66 0f 3a 26 W1 vgetmantpd Vx,Wx,Ib
+E 66 0f 3a 27 W0 vgetmantss Vss,Hss,Wss,Ib
+E 66 0f 3a 27 W1 vgetmantsd Vsd,Hsd,Wsd,Ib
+V 66 0f 3a 30 L0 W0 reg kshiftrb KG,KR,Ib
+V 66 0f 3a 30 L0 W1 reg kshiftrw KG,KR,Ib
+V 66 0f 3a 31 L0 W0 reg kshiftrd KG,KR,Ib
+V 66 0f 3a 31 L0 W1 reg kshiftrq KG,KR,Ib
+V 66 0f 3a 32 L0 W0 reg kshiftlb KG,KR,Ib
+V 66 0f 3a 32 L0 W1 reg kshiftlw KG,KR,Ib
+V 66 0f 3a 33 L0 W0 reg kshiftld KG,KR,Ib
+V 66 0f 3a 33 L0 W1 reg kshiftlq KG,KR,Ib
+V 66 0f 3a 38 W0 L1 vinserti128 Vqq,Hqq,Wdq,Ib
+E 66 0f 3a 38 W0 vinserti32x4 Vx,Hx,Wdq,Ib
+E 66 0f 3a 38 W1 vinserti64x2 Vx,Hx,Wdq,Ib
+V 66 0f 3a 39 W0 L1 vextracti128 Wdq,Vqq,Ib
+E 66 0f 3a 39 W0 vextracti32x4 Wdq,Vx,Ib
+E 66 0f 3a 39 W1 vextracti64x2 Wdq,Vx,Ib
+E 66 0f 3a 3a W0 vinserti32x8 Vx,Hx,Wqq,Ib
+E 66 0f 3a 3a W1 vinserti64x4 Vx,Hx,Wqq,Ib
+E 66 0f 3a 3b W0 vextracti32x8 Wqq,Vx,Ib
+E 66 0f 3a 3b W1 vextracti64x4 Wqq,Vx,Ib
+E 66 0f 3a 3e W0 vpcmpub KG,Hx,Wx,Ib
+E 66 0f 3a 3e W1 vpcmpuw KG,Hx,Wx,Ib
+E 66 0f 3a 3f W0 vpcmpb KG,Hx,Wx,Ib
+E 66 0f 3a 3f W1 vpcmpw KG,Hx,Wx,Ib
+LV 66 0f 3a 40 vdpps Vx,Hx,Wx,Ib
+LV 66 0f 3a 41 vdppd Vdq,Hdq,Wdq,Ib
+LV 66 0f 3a 42 vmpsadbw Vx,Hx,Wx,Ib
+E 66 0f 3a 42 W0 vdbpsadbw Vx,Hx,Wx,Ib
+E 66 0f 3a 43 W0 vshufi32x4 Vx,Hx,Wx,Ib
+E 66 0f 3a 43 W1 vshufi64x2 Vx,Hx,Wx,Ib
+LVE 66 0f 3a 44 vpclmulqdq Vx,Hx,Wx,Ib
+V 66 0f 3a 46 W0 L1 vperm2i128 Vqq,Hqq,Wqq,Ib
+V 66 0f 3a 4a W0 vblendvps Vx,Hx,Wx,Lx
+V 66 0f 3a 4b W0 vblendvpd Vx,Hx,Wx,Lx
+V 66 0f 3a 4c W0 vpblendvb Vx,Hx,Wx,Lx
+E 66 0f 3a 50 W0 vrangeps Vx,Hx,Wx,Ib
+E 66 0f 3a 50 W1 vrangepd Vx,Hx,Wx,Ib
+E 66 0f 3a 51 W0 vrangess Vss,Hss,Wss,Ib
+E 66 0f 3a 51 W1 vrangesd Vsd,Hsd,Wsd,Ib
+E 66 0f 3a 54 W0 vfixupimmps Vx,Hx,Wx,Ib
+E 66 0f 3a 54 W1 vfixupimmpd Vx,Hx,Wx,Ib
+E 66 0f 3a 55 W0 vfixupimmss Vss,Hss,Wss,Ib
+E 66 0f 3a 55 W1 vfixupimmsd Vsd,Hsd,Wsd,Ib
+E 66 0f 3a 56 W0 vreduceps Vx,Wx,Ib
+E 66 0f 3a 56 W1 vreducepd Vx,Wx,Ib
+E 66 0f 3a 57 W0 vreducess Vss,Hss,Wss,Ib
+E 66 0f 3a 57 W1 vreducesd Vsd,Hsd,Wsd,Ib
+LV 66 0f 3a 60 vpcmpestrm Vdq,Wdq,Ib
+LV 66 0f 3a 61 vpcmpestri Vdq,Wdq,Ib
+LV 66 0f 3a 62 vpcmpistrm Vdq,Wdq,Ib
+LV 66 0f 3a 63 vpcmpistri Vdq,Wdq,Ib
+E 66 0f 3a 66 W0 vfpclassps KG,Wx,Ib
+E 66 0f 3a 66 W1 vfpclasspd KG,Wx,Ib
+E 66 0f 3a 67 W0 vfpclassss KG,Wss,Ib
+E 66 0f 3a 67 W1 vfpclasssd KG,Wsd,Ib
+E 66 0f 3a 70 W1 vpshldw Vx,Hx,Wx,Ib
+E 66 0f 3a 71 W0 vpshldd Vx,Hx,Wx,Ib
+E 66 0f 3a 71 W1 vpshldq Vx,Hx,Wx,Ib
+E 66 0f 3a 72 W1 vpshrdw Vx,Hx,Wx,Ib
+E 66 0f 3a 73 W0 vpshrdd Vx,Hx,Wx,Ib
+E 66 0f 3a 73 W1 vpshrdq Vx,Hx,Wx,Ib
+NP 0f 3a cc sha1rnds4 Vdq,Wdq,Ib
+66 0f 3a ce gf2p8affineqb Vdq,Wdq,Ib
+VE 66 0f 3a ce W1 vgf2p8affineqb Vx,Hx,Wx,Ib
+66 0f 3a cf gf2p8affineinvqb Vdq,Wdq,Ib
+VE 66 0f 3a cf W1 vgf2p8affineinvqb Vx,Hx,Wx,Ib
+LV 66 0f 3a df vaeskeygenassist Vdq,Wdq,Ib
+V F2 0f 3a f0 L0 rorx Gy,Ey,Ib
+`
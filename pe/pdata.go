@@ -0,0 +1,382 @@
+package pe
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	hde "github.com/can1357/go-hde"
+)
+
+// ErrPrologue is returned when the prologue of a function does not perform the
+// operations its unwind codes describe
+var ErrPrologue = errors.New("pe: prologue does not match the unwind codes")
+
+// UnwindOp is the operation of an unwind code
+type UnwindOp uint8
+
+const (
+	PushNonvol    UnwindOp = iota // Push of a nonvolatile register (UWOP_PUSH_NONVOL)
+	AllocLarge                    // Stack allocation of up to 4 GiB (UWOP_ALLOC_LARGE)
+	AllocSmall                    // Stack allocation of 8 to 128 bytes (UWOP_ALLOC_SMALL)
+	SetFPReg                      // Frame pointer set to the stack pointer plus an offset (UWOP_SET_FPREG)
+	SaveNonvol                    // Store of a nonvolatile register to the stack (UWOP_SAVE_NONVOL)
+	SaveNonvolFar                 // Store of a nonvolatile register at a 32-bit offset (UWOP_SAVE_NONVOL_FAR)
+	Epilog                        // Location of an epilogue, in version 2 unwind information (UWOP_EPILOG)
+	SpareCode                     // Reserved (UWOP_SPARE_CODE)
+	SaveXMM128                    // Store of a nonvolatile XMM register to the stack (UWOP_SAVE_XMM128)
+	SaveXMM128Far                 // Store of a nonvolatile XMM register at a 32-bit offset (UWOP_SAVE_XMM128_FAR)
+	PushMachFrame                 // Machine frame pushed by the processor on an interrupt (UWOP_PUSH_MACHFRAME)
+)
+
+var unwindOpNames = [...]string{
+	PushNonvol:    "PushNonvol",
+	AllocLarge:    "AllocLarge",
+	AllocSmall:    "AllocSmall",
+	SetFPReg:      "SetFPReg",
+	SaveNonvol:    "SaveNonvol",
+	SaveNonvolFar: "SaveNonvolFar",
+	Epilog:        "Epilog",
+	SpareCode:     "SpareCode",
+	SaveXMM128:    "SaveXMM128",
+	SaveXMM128Far: "SaveXMM128Far",
+	PushMachFrame: "PushMachFrame",
+}
+
+// String returns the name of the unwind operation
+func (op UnwindOp) String() string {
+	if int(op) >= len(unwindOpNames) {
+		return fmt.Sprintf("UnwindOp(%d)", op)
+	}
+	return unwindOpNames[op]
+}
+
+// slots returns the number of 16-bit slots an unwind code with the operation and
+// operation info takes up
+func (op UnwindOp) slots(info uint8) int {
+	switch op {
+	case AllocLarge:
+		if info == 0 {
+			return 2
+		}
+		return 3
+	case SaveNonvol, Epilog, SaveXMM128:
+		return 2
+	case SaveNonvolFar, SpareCode, SaveXMM128Far:
+		return 3
+	}
+	return 1
+}
+
+// UnwindCode is an operation of a function prologue, as recorded in its unwind
+// information
+type UnwindCode struct {
+	Offset uint8        // Offset from the function start of the end of the prologue instruction
+	Op     UnwindOp     // Operation
+	Reg    hde.Register // Register pushed or saved
+	Value  uint32       // Allocation size, offset of a saved register, or frame pointer offset for SetFPReg
+}
+
+// Flags of the unwind information
+const (
+	UnwindEHandler  = 0x1 // The function has an exception handler
+	UnwindUHandler  = 0x2 // The function has a termination handler
+	UnwindChainInfo = 0x4 // The unwind information continues that of another function
+)
+
+// UnwindInfo is the UNWIND_INFO structure describing how to undo the prologue of a
+// function
+type UnwindInfo struct {
+	Version     uint8
+	Flags       uint8            // UnwindEHandler, UnwindUHandler and UnwindChainInfo
+	PrologSize  uint8            // Size of the prologue in bytes
+	FrameReg    hde.Register     // Frame pointer register, or RegNone
+	FrameOffset uint32           // Offset of the frame pointer from the stack pointer when it is set
+	Codes       []UnwindCode     // Unwind codes, in reverse order of the prologue operations
+	Handler     uint64           // Virtual address of the exception or termination handler
+	Chained     *RuntimeFunction // Function whose unwind information this continues, for UnwindChainInfo
+}
+
+// RuntimeFunction is a RUNTIME_FUNCTION entry of the exception directory, the .pdata
+// section of x64 images, which covers every function that is not a leaf
+type RuntimeFunction struct {
+	Begin  uint64 // Virtual address of the function start
+	End    uint64 // Virtual address following the function
+	Unwind *UnwindInfo
+}
+
+// maxChain bounds the chains of unwind information that are followed
+const maxChain = 32
+
+// Functions returns the entries of the exception directory, with their unwind
+// information, in the order of the directory, which is sorted by address
+func (img *Image) Functions() ([]RuntimeFunction, error) {
+	dir, size := img.directory(pe.IMAGE_DIRECTORY_ENTRY_EXCEPTION)
+	if size == 0 {
+		return nil, nil
+	}
+	if !img.Mode.IsLong() {
+		return nil, fmt.Errorf("%w: exception directory of a 32-bit image", ErrMachine)
+	}
+	// The directory must be mapped before its size is trusted with an allocation
+	if _, err := img.read(dir, int(size)); err != nil {
+		return nil, fmt.Errorf("pe: exception directory: %w", err)
+	}
+	p := &pdata{img: img, infos: map[uint64]*UnwindInfo{}}
+	fns := make([]RuntimeFunction, size/12)
+	for i := range fns {
+		fn, err := p.function(dir+uint64(i)*12, 0)
+		if err != nil {
+			return nil, err
+		}
+		fns[i] = *fn
+	}
+	return fns, nil
+}
+
+// pdata holds the unwind information read from the exception directory, which
+// functions may share
+type pdata struct {
+	img   *Image
+	infos map[uint64]*UnwindInfo
+}
+
+// function reads the RUNTIME_FUNCTION at the virtual address
+func (p *pdata) function(va uint64, depth int) (*RuntimeFunction, error) {
+	b, err := p.img.read(va, 12)
+	if err != nil {
+		return nil, fmt.Errorf("pe: exception directory: %w", err)
+	}
+	le := binary.LittleEndian
+	fn := &RuntimeFunction{
+		Begin: p.img.ImageBase + uint64(le.Uint32(b)),
+		End:   p.img.ImageBase + uint64(le.Uint32(b[4:])),
+	}
+	unwind := p.img.ImageBase + uint64(le.Uint32(b[8:]))
+	if depth == maxChain {
+		return nil, fmt.Errorf("pe: unwind information chained too deep at %#x", va)
+	}
+	if unwind&1 != 0 {
+		// The entry shares the unwind information of another RUNTIME_FUNCTION
+		shared, err := p.function(unwind&^1, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		fn.Unwind = shared.Unwind
+		return fn, nil
+	}
+	if fn.Unwind, err = p.unwind(unwind, depth); err != nil {
+		return nil, err
+	}
+	return fn, nil
+}
+
+// unwind reads the UNWIND_INFO at the virtual address
+func (p *pdata) unwind(va uint64, depth int) (*UnwindInfo, error) {
+	if info, ok := p.infos[va]; ok {
+		return info, nil
+	}
+	hdr, err := p.img.read(va, 4)
+	if err != nil {
+		return nil, fmt.Errorf("pe: unwind information: %w", err)
+	}
+	info := &UnwindInfo{
+		Version:     hdr[0] & 7,
+		Flags:       hdr[0] >> 3,
+		PrologSize:  hdr[1],
+		FrameOffset: uint32(hdr[3]>>4) * 16,
+	}
+	if hdr[3]&15 != 0 {
+		info.FrameReg = hde.RAX + hde.Register(hdr[3]&15)
+	}
+
+	// The slots are padded to an even count, and followed by the handler or the
+	// chained function
+	n := int(hdr[2])
+	slots, err := p.img.read(va+4, n*2)
+	if err != nil {
+		return nil, fmt.Errorf("pe: unwind codes: %w", err)
+	}
+	le := binary.LittleEndian
+	for i := 0; i < n; {
+		op, opInfo := UnwindOp(slots[i*2+1]&15), slots[i*2+1]>>4
+		k := op.slots(opInfo)
+		if i+k > n {
+			return nil, fmt.Errorf("pe: unwind code %v at %#x is truncated", op, va)
+		}
+		code := UnwindCode{Offset: slots[i*2], Op: op}
+		switch op {
+		case PushNonvol:
+			code.Reg = hde.RAX + hde.Register(opInfo)
+		case AllocLarge:
+			if opInfo == 0 {
+				code.Value = uint32(le.Uint16(slots[i*2+2:])) * 8
+			} else {
+				code.Value = le.Uint32(slots[i*2+2:])
+			}
+		case AllocSmall:
+			code.Value = uint32(opInfo)*8 + 8
+		case SetFPReg:
+			code.Reg, code.Value = info.FrameReg, info.FrameOffset
+		case SaveNonvol:
+			code.Reg, code.Value = hde.RAX+hde.Register(opInfo), uint32(le.Uint16(slots[i*2+2:]))*8
+		case SaveNonvolFar:
+			code.Reg, code.Value = hde.RAX+hde.Register(opInfo), le.Uint32(slots[i*2+2:])
+		case SaveXMM128:
+			code.Reg, code.Value = hde.XMM0+hde.Register(opInfo), uint32(le.Uint16(slots[i*2+2:]))*16
+		case SaveXMM128Far:
+			code.Reg, code.Value = hde.XMM0+hde.Register(opInfo), le.Uint32(slots[i*2+2:])
+		case PushMachFrame:
+			code.Value = uint32(opInfo)
+		}
+		info.Codes = append(info.Codes, code)
+		i += k
+	}
+
+	tail := va + 4 + uint64(n+n&1)*2
+	switch {
+	case info.Flags&UnwindChainInfo != 0:
+		if info.Chained, err = p.function(tail, depth+1); err != nil {
+			return nil, err
+		}
+	case info.Flags&(UnwindEHandler|UnwindUHandler) != 0:
+		b, err := p.img.read(tail, 4)
+		if err != nil {
+			return nil, fmt.Errorf("pe: unwind handler: %w", err)
+		}
+		info.Handler = p.img.ImageBase + uint64(le.Uint32(b))
+	}
+	p.infos[va] = info
+	return info, nil
+}
+
+// DecodeFunction decodes the instructions of the function from its start to its end
+func (img *Image) DecodeFunction(fn RuntimeFunction) ([]hde.Inst, error) {
+	var insts []hde.Inst
+	for inst, err := range img.Instructions(fn.Begin) {
+		if inst.Addr >= fn.End {
+			break
+		}
+		if err != nil {
+			return insts, fmt.Errorf("pe: decoding at %#x: %w", inst.Addr, err)
+		}
+		insts = append(insts, inst)
+	}
+	return insts, nil
+}
+
+// CheckPrologue decodes the prologue of the function and checks that each of its
+// unwind codes matches the instruction ending at the code offset: a push of the
+// register, a subtraction from RSP of the allocation size, or of a register after a
+// stack probe, a LEA or MOV setting the frame pointer, or a store of the saved
+// register. Instructions that move the stack pointer without an unwind code are
+// reported as well.
+func (img *Image) CheckPrologue(fn RuntimeFunction) error {
+	info := fn.Unwind
+	if info == nil {
+		return nil
+	}
+	codes := map[uint8]UnwindCode{}
+	for _, code := range info.Codes {
+		switch code.Op {
+		case Epilog, SpareCode, PushMachFrame:
+			// Not performed by a prologue instruction
+		default:
+			codes[code.Offset] = code
+		}
+	}
+
+	// Registers holding a copy of the stack pointer, which saves may be relative to
+	stack := map[hde.Register]bool{hde.RSP: true}
+	if info.FrameReg != hde.RegNone {
+		stack[info.FrameReg] = true
+	}
+	end := min(fn.Begin+uint64(info.PrologSize), fn.End)
+	for inst, err := range img.Instructions(fn.Begin) {
+		if inst.Addr >= end {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("pe: decoding the prologue at %#x: %w", inst.Addr, err)
+		}
+		off := uint8(inst.Addr - fn.Begin + uint64(len(inst.Bytes)))
+		code, ok := codes[off]
+		if !ok {
+			if movesStack(&inst.Insn) {
+				return fmt.Errorf("%w: %#x: %v moves the stack pointer without an unwind code",
+					ErrPrologue, inst.Addr, inst.Insn.FormatIntel(inst.Addr))
+			}
+			if ops := inst.Insn.Operands(); inst.Insn.Mnemonic() == hde.MOV &&
+				ops[0].Type == hde.OpReg && ops[1].Type == hde.OpReg && ops[1].Reg == hde.RSP {
+				// mov rax, rsp, ahead of saves relative to rax
+				stack[ops[0].Reg] = true
+			}
+			continue
+		}
+		if !matches(&inst.Insn, code, info, stack) {
+			return fmt.Errorf("%w: %#x: %v is not %v %v %#x",
+				ErrPrologue, inst.Addr, inst.Insn.FormatIntel(inst.Addr), code.Op, code.Reg, code.Value)
+		}
+		delete(codes, off)
+	}
+	if len(codes) != 0 {
+		off := slices.Min(slices.Collect(maps.Keys(codes)))
+		code := codes[off]
+		return fmt.Errorf("%w: no instruction ends at %#x for %v %v %#x",
+			ErrPrologue, fn.Begin+uint64(off), code.Op, code.Reg, code.Value)
+	}
+	return nil
+}
+
+// movesStack reports whether the instruction pushes to or allocates from the stack
+func movesStack(insn *hde.Insn) bool {
+	switch insn.Mnemonic() {
+	case hde.PUSH:
+		return true
+	case hde.SUB:
+		ops := insn.Operands()
+		return ops[0].Type == hde.OpReg && ops[0].Reg == hde.RSP
+	}
+	return false
+}
+
+// matches reports whether the instruction performs the operation of the unwind code,
+// with saves addressed from one of the stack registers
+func matches(insn *hde.Insn, code UnwindCode, info *UnwindInfo, stack map[hde.Register]bool) bool {
+	ops := insn.Operands()
+	if len(ops) == 0 {
+		return false
+	}
+	dst := ops[0]
+	switch code.Op {
+	case PushNonvol:
+		return insn.Mnemonic() == hde.PUSH && dst.Type == hde.OpReg && dst.Reg == code.Reg
+	case AllocSmall, AllocLarge:
+		if insn.Mnemonic() != hde.SUB || dst.Type != hde.OpReg || dst.Reg != hde.RSP {
+			return false
+		}
+		// Large allocations probe the stack first and subtract the size in a register
+		return ops[1].Type == hde.OpReg || ops[1].Type == hde.OpImm && uint32(ops[1].Imm) == code.Value
+	case SetFPReg:
+		if dst.Type != hde.OpReg || dst.Reg != info.FrameReg {
+			return false
+		}
+		switch insn.Mnemonic() {
+		case hde.LEA:
+			m := ops[1].Mem
+			return m.Base == hde.RSP && m.Index == hde.RegNone && m.Disp == int64(info.FrameOffset)
+		case hde.MOV:
+			return ops[1].Type == hde.OpReg && ops[1].Reg == hde.RSP && info.FrameOffset == 0
+		}
+		return false
+	case SaveNonvol, SaveNonvolFar, SaveXMM128, SaveXMM128Far:
+		// The offset is relative to the stack pointer after the fixed allocation,
+		// which the store may come before, so only the register is checked
+		return dst.Type == hde.OpMem && len(ops) > 1 && ops[1].Type == hde.OpReg && ops[1].Reg == code.Reg &&
+			stack[dst.Mem.Base]
+	}
+	return false
+}
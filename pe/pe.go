@@ -0,0 +1,314 @@
+// Package pe maps the executable sections of Windows PE images and decodes their
+// code at virtual addresses, from the entry point, exports and TLS callbacks.
+package pe
+
+import (
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/flow"
+	"github.com/can1357/go-hde/internal/image"
+)
+
+var (
+	// ErrMachine is returned for images that are not x86 or x86-64 code
+	ErrMachine = errors.New("pe: unsupported machine type")
+	// ErrUnmapped is returned for addresses outside of the sections of the image
+	ErrUnmapped = errors.New("pe: address not mapped")
+)
+
+// Section is a section of the image mapped at its virtual address
+type Section struct {
+	Name string // Section name, such as .text
+	Addr uint64 // Virtual address of the first byte
+	Data []byte // Contents, up to the virtual size of the section
+	Exec bool   // Whether the section holds executable code
+}
+
+// EntryKind identifies where an entry point of the image comes from
+type EntryKind uint8
+
+const (
+	EntryPoint  EntryKind = iota // The image entry point from the optional header
+	Export                       // An exported function
+	TLSCallback                  // A TLS callback, run before the entry point
+)
+
+var entryKindNames = [...]string{
+	EntryPoint:  "EntryPoint",
+	Export:      "Export",
+	TLSCallback: "TLSCallback",
+}
+
+// String returns the name of the entry kind
+func (k EntryKind) String() string {
+	if int(k) >= len(entryKindNames) {
+		return fmt.Sprintf("EntryKind(%d)", k)
+	}
+	return entryKindNames[k]
+}
+
+// Entry is an address the image is entered at
+type Entry struct {
+	Addr uint64    // Virtual address
+	Kind EntryKind // Where the entry point comes from
+	Name string    // Export name, or #ordinal for exports by ordinal only
+}
+
+// Image is a PE image with its sections mapped at their virtual addresses. Sections
+// lists them in header order, and Mode is Mode32 for I386 images and Mode64 for AMD64
+// images. The embedded image provides Section, Decode and Instructions, which decode
+// executable sections only, and Close.
+type Image struct {
+	image.Image[*Section]
+	File      *pe.File
+	ImageBase uint64 // Preferred load address, which virtual addresses are relative to
+}
+
+// Open opens the named PE file
+func Open(name string) (*Image, error) {
+	return image.Open(name, NewImage)
+}
+
+// NewImage reads a PE image from r and maps its sections
+func NewImage(r io.ReaderAt) (*Image, error) {
+	f, err := pe.NewFile(r)
+	if err != nil {
+		return nil, err
+	}
+	var mode *hde.Mode
+	switch f.Machine {
+	case pe.IMAGE_FILE_MACHINE_I386:
+		mode = hde.Mode32
+	case pe.IMAGE_FILE_MACHINE_AMD64:
+		mode = hde.Mode64
+	default:
+		return nil, fmt.Errorf("%w: %#x", ErrMachine, f.Machine)
+	}
+	img := &Image{Image: image.New[*Section](mode, ErrUnmapped), File: f}
+	switch oh := f.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		img.ImageBase = uint64(oh.ImageBase)
+	case *pe.OptionalHeader64:
+		img.ImageBase = oh.ImageBase
+	}
+
+	for _, s := range f.Sections {
+		data, err := s.Data()
+		if err != nil {
+			return nil, fmt.Errorf("pe: reading section %s: %w", s.Name, err)
+		}
+		if s.VirtualSize != 0 && int(s.VirtualSize) < len(data) {
+			// The raw data is padded to the file alignment
+			data = data[:s.VirtualSize]
+		}
+		sec := &Section{
+			Name: s.Name,
+			Addr: img.ImageBase + uint64(s.VirtualAddress),
+			Data: data,
+			Exec: s.Characteristics&(pe.IMAGE_SCN_MEM_EXECUTE|pe.IMAGE_SCN_CNT_CODE) != 0,
+		}
+		image.Map(&img.Image, sec, sec.Addr, sec.Data, sec.Exec)
+	}
+	return img, nil
+}
+
+// read returns n bytes of the image at the virtual address
+func (img *Image) read(va uint64, n int) ([]byte, error) {
+	if n == 0 {
+		return nil, nil
+	}
+	s := img.Section(va)
+	if s == nil || uint64(len(s.Data))-(va-s.Addr) < uint64(n) {
+		return nil, fmt.Errorf("%w: %#x", ErrUnmapped, va)
+	}
+	off := va - s.Addr
+	return s.Data[off : off+uint64(n)], nil
+}
+
+// readPtr reads a pointer-sized value of the image at the virtual address
+func (img *Image) readPtr(va uint64) (uint64, error) {
+	if img.Mode.IsLong() {
+		b, err := img.read(va, 8)
+		if err != nil {
+			return 0, err
+		}
+		return binary.LittleEndian.Uint64(b), nil
+	}
+	b, err := img.read(va, 4)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(binary.LittleEndian.Uint32(b)), nil
+}
+
+// cstring reads a NUL-terminated string of the image at the virtual address
+func (img *Image) cstring(va uint64) (string, error) {
+	s := img.Section(va)
+	if s == nil {
+		return "", fmt.Errorf("%w: %#x", ErrUnmapped, va)
+	}
+	b := s.Data[va-s.Addr:]
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i]), nil
+		}
+	}
+	return string(b), nil
+}
+
+// directory returns the virtual address and size of a data directory, or a zero
+// size if the image does not have it
+func (img *Image) directory(index int) (uint64, uint32) {
+	var dirs []pe.DataDirectory
+	switch oh := img.File.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		dirs = oh.DataDirectory[:min(oh.NumberOfRvaAndSizes, 16)]
+	case *pe.OptionalHeader64:
+		dirs = oh.DataDirectory[:min(oh.NumberOfRvaAndSizes, 16)]
+	}
+	if index >= len(dirs) || dirs[index].VirtualAddress == 0 {
+		return 0, 0
+	}
+	return img.ImageBase + uint64(dirs[index].VirtualAddress), dirs[index].Size
+}
+
+// EntryPoint returns the virtual address of the image entry point, or false for
+// images without one, such as resource-only DLLs
+func (img *Image) EntryPoint() (uint64, bool) {
+	var rva uint32
+	switch oh := img.File.OptionalHeader.(type) {
+	case *pe.OptionalHeader32:
+		rva = oh.AddressOfEntryPoint
+	case *pe.OptionalHeader64:
+		rva = oh.AddressOfEntryPoint
+	}
+	return img.ImageBase + uint64(rva), rva != 0
+}
+
+// Exports returns the functions exported by the image. Exports forwarded to other
+// images are left out, as they have no code in this one.
+func (img *Image) Exports() ([]Entry, error) {
+	dir, size := img.directory(pe.IMAGE_DIRECTORY_ENTRY_EXPORT)
+	if size == 0 {
+		return nil, nil
+	}
+	hdr, err := img.read(dir, 40)
+	if err != nil {
+		return nil, fmt.Errorf("pe: export directory: %w", err)
+	}
+	le := binary.LittleEndian
+	ordinalBase := le.Uint32(hdr[16:])
+	numFuncs, numNames := le.Uint32(hdr[20:]), le.Uint32(hdr[24:])
+	funcs, err := img.read(img.ImageBase+uint64(le.Uint32(hdr[28:])), int(numFuncs)*4)
+	if err != nil {
+		return nil, fmt.Errorf("pe: export addresses: %w", err)
+	}
+	names, err := img.read(img.ImageBase+uint64(le.Uint32(hdr[32:])), int(numNames)*4)
+	if err != nil {
+		return nil, fmt.Errorf("pe: export names: %w", err)
+	}
+	ordinals, err := img.read(img.ImageBase+uint64(le.Uint32(hdr[36:])), int(numNames)*2)
+	if err != nil {
+		return nil, fmt.Errorf("pe: export ordinals: %w", err)
+	}
+
+	named := make(map[uint32]string, numNames)
+	for i := range numNames {
+		name, err := img.cstring(img.ImageBase + uint64(le.Uint32(names[i*4:])))
+		if err != nil {
+			return nil, fmt.Errorf("pe: export name: %w", err)
+		}
+		named[uint32(le.Uint16(ordinals[i*2:]))] = name
+	}
+
+	var exports []Entry
+	for i := range numFuncs {
+		rva := le.Uint32(funcs[i*4:])
+		va := img.ImageBase + uint64(rva)
+		if rva == 0 || va >= dir && va < dir+uint64(size) {
+			// Unused slot, or a forwarder string inside the export directory
+			continue
+		}
+		name, ok := named[i]
+		if !ok {
+			name = fmt.Sprintf("#%d", ordinalBase+i)
+		}
+		exports = append(exports, Entry{Addr: va, Kind: Export, Name: name})
+	}
+	return exports, nil
+}
+
+// TLSCallbacks returns the TLS callbacks of the image, which the loader runs before
+// the entry point
+func (img *Image) TLSCallbacks() ([]Entry, error) {
+	dir, size := img.directory(pe.IMAGE_DIRECTORY_ENTRY_TLS)
+	if size == 0 {
+		return nil, nil
+	}
+
+	// AddressOfCallBacks follows the raw data range and the index address
+	ptrSize := uint64(4)
+	if img.Mode.IsLong() {
+		ptrSize = 8
+	}
+	list, err := img.readPtr(dir + 3*ptrSize)
+	if err != nil {
+		return nil, fmt.Errorf("pe: TLS directory: %w", err)
+	}
+	var callbacks []Entry
+	for va := list; va != 0; va += ptrSize {
+		cb, err := img.readPtr(va)
+		if err != nil {
+			return nil, fmt.Errorf("pe: TLS callbacks: %w", err)
+		}
+		if cb == 0 {
+			break
+		}
+		callbacks = append(callbacks, Entry{Addr: cb, Kind: TLSCallback})
+	}
+	return callbacks, nil
+}
+
+// Entries returns the entry point, the exports and the TLS callbacks of the image
+func (img *Image) Entries() ([]Entry, error) {
+	var entries []Entry
+	if va, ok := img.EntryPoint(); ok {
+		entries = append(entries, Entry{Addr: va, Kind: EntryPoint})
+	}
+	exports, err := img.Exports()
+	if err != nil {
+		return nil, err
+	}
+	callbacks, err := img.TLSCallbacks()
+	if err != nil {
+		return nil, err
+	}
+	return append(append(entries, exports...), callbacks...), nil
+}
+
+// Disassemble decodes the executable sections by recursive descent from the entry
+// point, the exports and the TLS callbacks of the image
+func (img *Image) Disassemble() (*flow.Graph, error) {
+	entries, err := img.Entries()
+	if err != nil {
+		return nil, err
+	}
+	var segs []flow.Segment
+	for _, s := range img.Sections {
+		if s.Exec {
+			segs = append(segs, flow.Segment{Addr: s.Addr, Data: s.Data})
+		}
+	}
+	var addrs []uint64
+	for _, e := range entries {
+		if s := img.Section(e.Addr); s != nil && s.Exec {
+			addrs = append(addrs, e.Addr)
+		}
+	}
+	return flow.DisassembleSegments(img.Mode, segs, addrs...)
+}
@@ -52,6 +52,24 @@ func (p Segment) Prefix() byte {
 	return segToPfxTable[p]
 }
 
+// segToRegTable maps segments to their segment registers
+var segToRegTable = [...]Register{
+	SegDS: DS,
+	SegCS: CS,
+	SegSS: SS,
+	SegES: ES,
+	SegFS: FS,
+	SegGS: GS,
+}
+
+// Register returns the segment register for the given segment
+func (p Segment) Register() Register {
+	if int(p) >= len(segToRegTable) {
+		return RegNone
+	}
+	return segToRegTable[p]
+}
+
 // PrefixSet is a set of prefixes
 type PrefixSet uint16
 
@@ -136,6 +154,8 @@ const (
 	PreSegES
 	PreSegFS
 	PreSegGS
+	PreVEX
+	PreEVEX
 )
 
 // PrefixToID returns the PrefixID for the given prefix opcode
@@ -188,6 +208,8 @@ var prefixNames = [...]string{
 	PreSegES:    "ES",
 	PreSegFS:    "FS",
 	PreSegGS:    "GS",
+	PreVEX:      "VEX",
+	PreEVEX:     "EVEX",
 }
 
 // SIB represents the SIB (Scale-Index-Base) byte of an x86 instruction
@@ -0,0 +1,729 @@
+package hde
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Register identifies a register, including its size
+type Register uint8
+
+const (
+	RegNone Register = iota // No register
+
+	// General purpose registers
+	AL
+	CL
+	DL
+	BL
+	SPL
+	BPL
+	SIL
+	DIL
+	R8B
+	R9B
+	R10B
+	R11B
+	R12B
+	R13B
+	R14B
+	R15B
+	AH
+	CH
+	DH
+	BH
+	AX
+	CX
+	DX
+	BX
+	SP
+	BP
+	SI
+	DI
+	R8W
+	R9W
+	R10W
+	R11W
+	R12W
+	R13W
+	R14W
+	R15W
+	EAX
+	ECX
+	EDX
+	EBX
+	ESP
+	EBP
+	ESI
+	EDI
+	R8D
+	R9D
+	R10D
+	R11D
+	R12D
+	R13D
+	R14D
+	R15D
+	RAX
+	RCX
+	RDX
+	RBX
+	RSP
+	RBP
+	RSI
+	RDI
+	R8
+	R9
+	R10
+	R11
+	R12
+	R13
+	R14
+	R15
+
+	// Instruction pointer
+	IP
+	EIP
+	RIP
+
+	// Segment registers
+	ES
+	CS
+	SS
+	DS
+	FS
+	GS
+
+	// Control and debug registers
+	CR0
+	CR1
+	CR2
+	CR3
+	CR4
+	CR5
+	CR6
+	CR7
+	CR8
+	CR9
+	CR10
+	CR11
+	CR12
+	CR13
+	CR14
+	CR15
+	DR0
+	DR1
+	DR2
+	DR3
+	DR4
+	DR5
+	DR6
+	DR7
+	DR8
+	DR9
+	DR10
+	DR11
+	DR12
+	DR13
+	DR14
+	DR15
+
+	// x87 and MMX registers
+	ST0
+	ST1
+	ST2
+	ST3
+	ST4
+	ST5
+	ST6
+	ST7
+	MM0
+	MM1
+	MM2
+	MM3
+	MM4
+	MM5
+	MM6
+	MM7
+
+	// Vector registers
+	XMM0
+	XMM1
+	XMM2
+	XMM3
+	XMM4
+	XMM5
+	XMM6
+	XMM7
+	XMM8
+	XMM9
+	XMM10
+	XMM11
+	XMM12
+	XMM13
+	XMM14
+	XMM15
+	XMM16
+	XMM17
+	XMM18
+	XMM19
+	XMM20
+	XMM21
+	XMM22
+	XMM23
+	XMM24
+	XMM25
+	XMM26
+	XMM27
+	XMM28
+	XMM29
+	XMM30
+	XMM31
+	YMM0
+	YMM1
+	YMM2
+	YMM3
+	YMM4
+	YMM5
+	YMM6
+	YMM7
+	YMM8
+	YMM9
+	YMM10
+	YMM11
+	YMM12
+	YMM13
+	YMM14
+	YMM15
+	YMM16
+	YMM17
+	YMM18
+	YMM19
+	YMM20
+	YMM21
+	YMM22
+	YMM23
+	YMM24
+	YMM25
+	YMM26
+	YMM27
+	YMM28
+	YMM29
+	YMM30
+	YMM31
+	ZMM0
+	ZMM1
+	ZMM2
+	ZMM3
+	ZMM4
+	ZMM5
+	ZMM6
+	ZMM7
+	ZMM8
+	ZMM9
+	ZMM10
+	ZMM11
+	ZMM12
+	ZMM13
+	ZMM14
+	ZMM15
+	ZMM16
+	ZMM17
+	ZMM18
+	ZMM19
+	ZMM20
+	ZMM21
+	ZMM22
+	ZMM23
+	ZMM24
+	ZMM25
+	ZMM26
+	ZMM27
+	ZMM28
+	ZMM29
+	ZMM30
+	ZMM31
+
+	// Opmask registers
+	K0
+	K1
+	K2
+	K3
+	K4
+	K5
+	K6
+	K7
+)
+
+// String returns the Intel syntax name of the register
+func (r Register) String() string {
+	if int(r) >= len(registerNames) {
+		return fmt.Sprintf("Register(%d)", r)
+	}
+	return registerNames[r]
+}
+
+// Class returns the register file the register belongs to
+func (r Register) Class() RegClass {
+	switch {
+	case r == RegNone:
+		return ClassNone
+	case r <= R15:
+		return ClassGPR
+	case r <= RIP:
+		return ClassIP
+	case r <= GS:
+		return ClassSeg
+	case r <= CR15:
+		return ClassCR
+	case r <= DR15:
+		return ClassDR
+	case r <= ST7:
+		return ClassX87
+	case r <= MM7:
+		return ClassMMX
+	case r <= XMM31:
+		return ClassXMM
+	case r <= YMM31:
+		return ClassYMM
+	case r <= ZMM31:
+		return ClassZMM
+	case r <= K7:
+		return ClassMask
+	}
+	return ClassNone
+}
+
+// Num returns the number the register is encoded as, with the REX, VEX or EVEX
+// extension bits applied. The high byte registers AH, CH, DH and BH are 4 to 7.
+func (r Register) Num() uint8 {
+	switch {
+	case r == RegNone:
+		return 0
+	case r <= R15B:
+		return uint8(r - AL)
+	case r <= BH:
+		return uint8(r-AH) + 4
+	case r <= R15W:
+		return uint8(r - AX)
+	case r <= R15D:
+		return uint8(r - EAX)
+	case r <= R15:
+		return uint8(r - RAX)
+	case r <= RIP:
+		return 0
+	case r <= GS:
+		return uint8(r - ES)
+	case r <= CR15:
+		return uint8(r - CR0)
+	case r <= DR15:
+		return uint8(r - DR0)
+	case r <= ST7:
+		return uint8(r - ST0)
+	case r <= MM7:
+		return uint8(r - MM0)
+	case r <= XMM31:
+		return uint8(r - XMM0)
+	case r <= YMM31:
+		return uint8(r - YMM0)
+	case r <= ZMM31:
+		return uint8(r - ZMM0)
+	case r <= K7:
+		return uint8(r - K0)
+	}
+	return 0
+}
+
+// Size returns the size of the register in bits. Control and debug registers
+// are reported with their 64-bit mode size.
+func (r Register) Size() int {
+	switch {
+	case r == RegNone:
+		return 0
+	case r <= BH:
+		return 8
+	case r <= R15W, r == IP:
+		return 16
+	case r <= R15D, r == EIP:
+		return 32
+	case r <= RIP:
+		return 64
+	case r <= GS:
+		return 16
+	case r <= DR15:
+		return 64
+	case r <= ST7:
+		return 80
+	case r <= MM7:
+		return 64
+	case r <= XMM31:
+		return 128
+	case r <= YMM31:
+		return 256
+	case r <= ZMM31:
+		return 512
+	case r <= K7:
+		return 64
+	}
+	return 0
+}
+
+// regOf returns the register of the given class, number and size. Byte registers
+// 4 to 7 resolve to SPL, BPL, SIL and DIL, and vector registers are sized by bits.
+func regOf(class RegClass, num uint8, bits int) Register {
+	switch class {
+	case ClassGPR:
+		switch bits {
+		case 8:
+			return AL + Register(num&15)
+		case 16:
+			return AX + Register(num&15)
+		case 32:
+			return EAX + Register(num&15)
+		}
+		return RAX + Register(num&15)
+	case ClassIP:
+		switch bits {
+		case 16:
+			return IP
+		case 32:
+			return EIP
+		}
+		return RIP
+	case ClassSeg:
+		if num > 5 {
+			return RegNone
+		}
+		return ES + Register(num)
+	case ClassCR:
+		return CR0 + Register(num&15)
+	case ClassDR:
+		return DR0 + Register(num&15)
+	case ClassX87:
+		return ST0 + Register(num&7)
+	case ClassMMX:
+		return MM0 + Register(num&7)
+	case ClassXMM, ClassYMM, ClassZMM:
+		switch {
+		case bits > 256:
+			return ZMM0 + Register(num&31)
+		case bits > 128:
+			return YMM0 + Register(num&31)
+		}
+		return XMM0 + Register(num&31)
+	case ClassMask:
+		return K0 + Register(num&7)
+	}
+	return RegNone
+}
+
+// slotReg returns the register operand of the instruction addressed by one of
+// the given methods, or RegNone
+func (insn *Insn) slotReg(ams ...addrMethod) Register {
+	f := insn.form()
+	if f == nil {
+		return RegNone
+	}
+	for i, s := range f.ops {
+		if !slices.Contains(ams, s.am) {
+			continue
+		}
+		if op := insn.operand(f, i); op.Type == OpReg {
+			return op.Reg
+		}
+		break
+	}
+	return RegNone
+}
+
+// Reg returns the register selected by the ModR/M reg field, or RegNone if the
+// instruction uses the field as an opcode extension or is not a known instruction
+func (insn *Insn) Reg() Register {
+	return insn.slotReg(amG, amV, amP, amS, amC, amD, amKG)
+}
+
+// RM returns the register selected by the ModR/M r/m field or by the low bits of
+// the opcode, or RegNone if the operand is in memory
+func (insn *Insn) RM() Register {
+	return insn.slotReg(amE, amR, amW, amU, amQ, amN, amKR, amKE, amSTi, amZ)
+}
+
+// Vvvv returns the register selected by VEX.vvvv or EVEX.V'vvvv, or RegNone if the
+// instruction does not use the field
+func (insn *Insn) Vvvv() Register {
+	return insn.slotReg(amH, amB, amKH)
+}
+
+// Mask returns the opmask register of an EVEX-encoded instruction, or RegNone if
+// the instruction is not masked
+func (insn *Insn) Mask() Register {
+	if insn.encoding() != encEVEX || insn.EVEX.AAA() == 0 {
+		return RegNone
+	}
+	return K0 + Register(insn.EVEX.AAA())
+}
+
+// modrmMem returns the memory operand encoded by the ModR/M byte, if any
+func (insn *Insn) modrmMem() (Mem, bool) {
+	if insn.Flags&IsModRM == 0 || insn.ModRM.Mod() == 3 {
+		return Mem{}, false
+	}
+	f := insn.form()
+	if f == nil {
+		return insn.memory(), true
+	}
+	for i, s := range f.ops {
+		switch s.am {
+		case amE, amM, amW, amQ, amKE, amMV:
+			return insn.operand(f, i).Mem, true
+		}
+	}
+	return Mem{}, false
+}
+
+// Base returns the base register of the ModR/M memory operand, or RegNone. The base
+// of RIP-relative operands is RIP.
+func (insn *Insn) Base() Register {
+	m, _ := insn.modrmMem()
+	return m.Base
+}
+
+// Index returns the index register of the ModR/M memory operand, or RegNone. VSIB
+// operands have a vector index register.
+func (insn *Insn) Index() Register {
+	m, _ := insn.modrmMem()
+	return m.Index
+}
+
+var registerNames = [...]string{
+	RegNone: "",
+	AL:      "al",
+	CL:      "cl",
+	DL:      "dl",
+	BL:      "bl",
+	SPL:     "spl",
+	BPL:     "bpl",
+	SIL:     "sil",
+	DIL:     "dil",
+	R8B:     "r8b",
+	R9B:     "r9b",
+	R10B:    "r10b",
+	R11B:    "r11b",
+	R12B:    "r12b",
+	R13B:    "r13b",
+	R14B:    "r14b",
+	R15B:    "r15b",
+	AH:      "ah",
+	CH:      "ch",
+	DH:      "dh",
+	BH:      "bh",
+	AX:      "ax",
+	CX:      "cx",
+	DX:      "dx",
+	BX:      "bx",
+	SP:      "sp",
+	BP:      "bp",
+	SI:      "si",
+	DI:      "di",
+	R8W:     "r8w",
+	R9W:     "r9w",
+	R10W:    "r10w",
+	R11W:    "r11w",
+	R12W:    "r12w",
+	R13W:    "r13w",
+	R14W:    "r14w",
+	R15W:    "r15w",
+	EAX:     "eax",
+	ECX:     "ecx",
+	EDX:     "edx",
+	EBX:     "ebx",
+	ESP:     "esp",
+	EBP:     "ebp",
+	ESI:     "esi",
+	EDI:     "edi",
+	R8D:     "r8d",
+	R9D:     "r9d",
+	R10D:    "r10d",
+	R11D:    "r11d",
+	R12D:    "r12d",
+	R13D:    "r13d",
+	R14D:    "r14d",
+	R15D:    "r15d",
+	RAX:     "rax",
+	RCX:     "rcx",
+	RDX:     "rdx",
+	RBX:     "rbx",
+	RSP:     "rsp",
+	RBP:     "rbp",
+	RSI:     "rsi",
+	RDI:     "rdi",
+	R8:      "r8",
+	R9:      "r9",
+	R10:     "r10",
+	R11:     "r11",
+	R12:     "r12",
+	R13:     "r13",
+	R14:     "r14",
+	R15:     "r15",
+	IP:      "ip",
+	EIP:     "eip",
+	RIP:     "rip",
+	ES:      "es",
+	CS:      "cs",
+	SS:      "ss",
+	DS:      "ds",
+	FS:      "fs",
+	GS:      "gs",
+	CR0:     "cr0",
+	CR1:     "cr1",
+	CR2:     "cr2",
+	CR3:     "cr3",
+	CR4:     "cr4",
+	CR5:     "cr5",
+	CR6:     "cr6",
+	CR7:     "cr7",
+	CR8:     "cr8",
+	CR9:     "cr9",
+	CR10:    "cr10",
+	CR11:    "cr11",
+	CR12:    "cr12",
+	CR13:    "cr13",
+	CR14:    "cr14",
+	CR15:    "cr15",
+	DR0:     "dr0",
+	DR1:     "dr1",
+	DR2:     "dr2",
+	DR3:     "dr3",
+	DR4:     "dr4",
+	DR5:     "dr5",
+	DR6:     "dr6",
+	DR7:     "dr7",
+	DR8:     "dr8",
+	DR9:     "dr9",
+	DR10:    "dr10",
+	DR11:    "dr11",
+	DR12:    "dr12",
+	DR13:    "dr13",
+	DR14:    "dr14",
+	DR15:    "dr15",
+	ST0:     "st(0)",
+	ST1:     "st(1)",
+	ST2:     "st(2)",
+	ST3:     "st(3)",
+	ST4:     "st(4)",
+	ST5:     "st(5)",
+	ST6:     "st(6)",
+	ST7:     "st(7)",
+	MM0:     "mm0",
+	MM1:     "mm1",
+	MM2:     "mm2",
+	MM3:     "mm3",
+	MM4:     "mm4",
+	MM5:     "mm5",
+	MM6:     "mm6",
+	MM7:     "mm7",
+	XMM0:    "xmm0",
+	XMM1:    "xmm1",
+	XMM2:    "xmm2",
+	XMM3:    "xmm3",
+	XMM4:    "xmm4",
+	XMM5:    "xmm5",
+	XMM6:    "xmm6",
+	XMM7:    "xmm7",
+	XMM8:    "xmm8",
+	XMM9:    "xmm9",
+	XMM10:   "xmm10",
+	XMM11:   "xmm11",
+	XMM12:   "xmm12",
+	XMM13:   "xmm13",
+	XMM14:   "xmm14",
+	XMM15:   "xmm15",
+	XMM16:   "xmm16",
+	XMM17:   "xmm17",
+	XMM18:   "xmm18",
+	XMM19:   "xmm19",
+	XMM20:   "xmm20",
+	XMM21:   "xmm21",
+	XMM22:   "xmm22",
+	XMM23:   "xmm23",
+	XMM24:   "xmm24",
+	XMM25:   "xmm25",
+	XMM26:   "xmm26",
+	XMM27:   "xmm27",
+	XMM28:   "xmm28",
+	XMM29:   "xmm29",
+	XMM30:   "xmm30",
+	XMM31:   "xmm31",
+	YMM0:    "ymm0",
+	YMM1:    "ymm1",
+	YMM2:    "ymm2",
+	YMM3:    "ymm3",
+	YMM4:    "ymm4",
+	YMM5:    "ymm5",
+	YMM6:    "ymm6",
+	YMM7:    "ymm7",
+	YMM8:    "ymm8",
+	YMM9:    "ymm9",
+	YMM10:   "ymm10",
+	YMM11:   "ymm11",
+	YMM12:   "ymm12",
+	YMM13:   "ymm13",
+	YMM14:   "ymm14",
+	YMM15:   "ymm15",
+	YMM16:   "ymm16",
+	YMM17:   "ymm17",
+	YMM18:   "ymm18",
+	YMM19:   "ymm19",
+	YMM20:   "ymm20",
+	YMM21:   "ymm21",
+	YMM22:   "ymm22",
+	YMM23:   "ymm23",
+	YMM24:   "ymm24",
+	YMM25:   "ymm25",
+	YMM26:   "ymm26",
+	YMM27:   "ymm27",
+	YMM28:   "ymm28",
+	YMM29:   "ymm29",
+	YMM30:   "ymm30",
+	YMM31:   "ymm31",
+	ZMM0:    "zmm0",
+	ZMM1:    "zmm1",
+	ZMM2:    "zmm2",
+	ZMM3:    "zmm3",
+	ZMM4:    "zmm4",
+	ZMM5:    "zmm5",
+	ZMM6:    "zmm6",
+	ZMM7:    "zmm7",
+	ZMM8:    "zmm8",
+	ZMM9:    "zmm9",
+	ZMM10:   "zmm10",
+	ZMM11:   "zmm11",
+	ZMM12:   "zmm12",
+	ZMM13:   "zmm13",
+	ZMM14:   "zmm14",
+	ZMM15:   "zmm15",
+	ZMM16:   "zmm16",
+	ZMM17:   "zmm17",
+	ZMM18:   "zmm18",
+	ZMM19:   "zmm19",
+	ZMM20:   "zmm20",
+	ZMM21:   "zmm21",
+	ZMM22:   "zmm22",
+	ZMM23:   "zmm23",
+	ZMM24:   "zmm24",
+	ZMM25:   "zmm25",
+	ZMM26:   "zmm26",
+	ZMM27:   "zmm27",
+	ZMM28:   "zmm28",
+	ZMM29:   "zmm29",
+	ZMM30:   "zmm30",
+	ZMM31:   "zmm31",
+	K0:      "k0",
+	K1:      "k1",
+	K2:      "k2",
+	K3:      "k3",
+	K4:      "k4",
+	K5:      "k5",
+	K6:      "k6",
+	K7:      "k7",
+}
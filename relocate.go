@@ -0,0 +1,80 @@
+package hde
+
+import "errors"
+
+// ErrOutOfRange is returned when a relocated relative operand cannot reach its target
+var ErrOutOfRange error = &DecoderError{errors.New("relative target out of range")}
+
+// relDisp returns the displacement from next to target as a bits-wide literal value,
+// with addresses wrapping at size bits. Fields at least as wide as the address always
+// reach; narrower ones report whether the signed distance fits.
+func relDisp(next, target uint64, bits, size int) (uint64, bool) {
+	d := truncate(target-next, size)
+	if bits >= size {
+		return truncate(d, bits), true
+	}
+	sd := int64(d<<(64-size)) >> (64 - size)
+	hi := sd >> (bits - 1)
+	return truncate(uint64(sd), bits), hi == 0 || hi == -1
+}
+
+// promote returns the instruction with its rel8 branch widened to the rel16 or rel32
+// form of the same opcode, or false if the instruction only has a rel8 form
+func (insn *Insn) promote() (Insn, bool) {
+	wide := *insn
+	switch {
+	case insn.Map() == MapPrimary && insn.Opcode == 0xeb:
+		wide.Opcode = 0xe9
+	case insn.Map() == MapPrimary && insn.Opcode&0xf0 == 0x70:
+		wide.Opcode, wide.Opcode2 = 0x0f, 0x80|insn.Opcode&0xf
+		wide.Length++
+	default:
+		return wide, false
+	}
+	wide.Flags &^= HasImm8
+	if insn.OperandSize() == 16 {
+		wide.Flags |= HasImm16
+		wide.Imm.Bits = 16
+	} else {
+		wide.Flags |= HasImm32
+		wide.Imm.Bits = 32
+	}
+	wide.Length += wide.Imm.Bits/8 - 1
+	return wide, true
+}
+
+// Relocate encodes the instruction, decoded at oldPC, to run at newPC with the same
+// effective targets. Relative branches and RIP-relative displacements are rewritten,
+// and short JMP and Jcc branches are promoted to their rel32 forms (rel16 in 16-bit
+// code) when the new distance does not fit in a rel8. Targets out of the ±2GB reach
+// of a rel32, and LOOP and JCXZ targets out of reach of their rel8, yield
+// ErrOutOfRange. Other instructions are encoded unchanged.
+func (insn *Insn) Relocate(oldPC, newPC uint64) ([]byte, error) {
+	mode := insn.Mode
+	moved := *insn
+	if target, ok := insn.MemTarget(oldPC); ok {
+		d, ok := relDisp(newPC+uint64(insn.Len()), target, int(insn.Disp.Bits), insn.AddressSize())
+		if !ok {
+			return nil, ErrOutOfRange
+		}
+		moved.Disp.Value = d
+		return mode.Encode(&moved)
+	}
+
+	target, ok := insn.BranchTarget(oldPC)
+	if !ok {
+		return mode.Encode(insn)
+	}
+	d, ok := relDisp(newPC+uint64(insn.Len()), target, int(insn.Imm.Bits), insn.branchSize())
+	if !ok && insn.Imm.Bits == 8 {
+		if moved, ok = insn.promote(); !ok {
+			return nil, ErrOutOfRange
+		}
+		d, ok = relDisp(newPC+uint64(moved.Len()), target, int(moved.Imm.Bits), moved.branchSize())
+	}
+	if !ok {
+		return nil, ErrOutOfRange
+	}
+	moved.Imm.Value = d
+	return mode.Encode(&moved)
+}
@@ -0,0 +1,60 @@
+package hde
+
+import "iter"
+
+// Inst is an instruction located in a buffer by a linear sweep
+type Inst struct {
+	Addr  uint64 // Address of the instruction
+	Bytes []byte // Encoded bytes of the instruction, or the bytes skipped after a decoding error
+	Insn  Insn   // Decoded instruction
+}
+
+// Resync decides where a linear sweep resumes after the code at addr fails to decode
+// with err. It returns the number of bytes to skip, or 0 to end the sweep.
+type Resync func(addr uint64, code []byte, err error) int
+
+var (
+	// SkipByte resumes the sweep at the next byte
+	SkipByte Resync = func(uint64, []byte, error) int { return 1 }
+	// StopOnError ends the sweep at the first decoding error
+	StopOnError Resync = func(uint64, []byte, error) int { return 0 }
+)
+
+// Instructions returns an iterator over the instructions in code, located at base,
+// decoded one after the other. Decoding errors are yielded along with the byte
+// skipped past them, and the sweep resumes at the next byte. Instructions at the end
+// of the buffer are decoded from the bytes that remain.
+func (mode *Mode) Instructions(code []byte, base uint64) iter.Seq2[Inst, error] {
+	return mode.Sweep(code, base, SkipByte)
+}
+
+// Sweep is like Instructions, resuming after decoding errors where resync says. An
+// error is yielded with the bytes skipped, or with the rest of the buffer if resync
+// ends the sweep.
+func (mode *Mode) Sweep(code []byte, base uint64, resync Resync) iter.Seq2[Inst, error] {
+	return func(yield func(Inst, error) bool) {
+		for off := 0; off < len(code); {
+			addr := base + uint64(off)
+			insn, err := mode.Decode(code[off:])
+			if err == nil {
+				n := off + int(insn.Len())
+				if !yield(Inst{addr, code[off:n:n], insn}, nil) {
+					return
+				}
+				off = n
+				continue
+			}
+
+			skip := resync(addr, code[off:], err)
+			stop := skip <= 0
+			if stop || skip > len(code)-off {
+				skip = len(code) - off
+			}
+			n := off + skip
+			if !yield(Inst{addr, code[off:n:n], insn}, err) || stop {
+				return
+			}
+			off = n
+		}
+	}
+}
@@ -0,0 +1,1350 @@
+package decode_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+)
+
+// unhex decodes a space separated hex string
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVEX(t *testing.T) {
+	tests := []struct {
+		mode    *hde.Mode
+		code    string
+		length  uint8
+		opcode  uint8
+		vex     bool
+		mmmmm   uint8
+		vvvv    uint8
+		l, w    uint8
+		pp      uint8
+		r, x, b uint8
+	}{
+		// vaddps ymm1, ymm2, ymm3
+		{hde.Mode64, "c5 ec 58 cb", 4, 0x58, true, 1, 2, 1, 0, 0, 0, 0, 0},
+		// vaddps xmm1, xmm2, [rax+0x10]
+		{hde.Mode64, "c5 e8 58 48 10", 5, 0x58, true, 1, 2, 0, 0, 0, 0, 0, 0},
+		// vpaddd ymm9, ymm10, [r12+r13*4+0x12345678]
+		{hde.Mode64, "c4 01 2d fe 8c ac 78 56 34 12", 10, 0xfe, true, 1, 10, 1, 0, 1, 1, 1, 1},
+		// vpshufb xmm1, xmm2, xmm3
+		{hde.Mode64, "c4 e2 69 00 cb", 5, 0x00, true, 2, 2, 0, 0, 1, 0, 0, 0},
+		// vpblendd ymm1, ymm2, ymm3, 5
+		{hde.Mode64, "c4 e3 6d 02 cb 05", 6, 0x02, true, 3, 2, 1, 0, 1, 0, 0, 0},
+		// vzeroupper
+		{hde.Mode64, "c5 f8 77", 3, 0x77, true, 1, 0, 0, 0, 0, 0, 0, 0},
+		// vmovdqu ymm0, [rip+0x100]
+		{hde.Mode64, "c5 fe 6f 05 00 01 00 00", 8, 0x6f, true, 1, 0, 1, 0, 2, 0, 0, 0},
+		// vpsrld ymm1, ymm2, 3
+		{hde.Mode64, "c5 f5 72 d2 03", 5, 0x72, true, 1, 1, 1, 0, 1, 0, 0, 0},
+		// andn rax, rbx, rcx
+		{hde.Mode64, "c4 e2 e0 f2 c1", 5, 0xf2, true, 2, 3, 0, 1, 0, 0, 0, 0},
+		// rorx eax, ebx, 7
+		{hde.Mode64, "c4 e3 7b f0 c3 07", 6, 0xf0, true, 3, 0, 0, 0, 3, 0, 0, 0},
+		// vpshufb xmm1, xmm2, xmm3 (32-bit)
+		{hde.Mode32, "c4 e2 69 00 cb", 5, 0x00, true, 2, 2, 0, 0, 1, 0, 0, 0},
+		// vaddps ymm1, ymm2, [eax+0x10] (32-bit)
+		{hde.Mode32, "c5 ec 58 48 10", 5, 0x58, true, 1, 2, 1, 0, 0, 0, 0, 0},
+		// les eax, [esi]
+		{hde.Mode32, "c4 06", 2, 0, false, 0, 0, 0, 0, 0, 0, 0, 0},
+		// lds eax, [ebp+8]
+		{hde.Mode32, "c5 45 08", 3, 0, false, 0, 0, 0, 0, 0, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if insn.Len() != tt.length {
+			t.Errorf("%s: length %d, want %d", tt.code, insn.Len(), tt.length)
+		}
+		if has := insn.Flags&hde.HasVEX != 0; has != tt.vex {
+			t.Errorf("%s: HasVEX %v, want %v", tt.code, has, tt.vex)
+			continue
+		}
+		if !tt.vex {
+			continue
+		}
+		v := insn.VEX
+		if insn.OpcodeByte() != tt.opcode || insn.Map() != hde.OpcodeMap(tt.mmmmm) {
+			t.Errorf("%s: opcode %02x in map %d, want %02x in map %d", tt.code, insn.OpcodeByte(), insn.Map(), tt.opcode, tt.mmmmm)
+		}
+		if v.Map() != tt.mmmmm || v.V() != tt.vvvv || v.L() != tt.l || v.W() != tt.w || v.PP() != tt.pp {
+			t.Errorf("%s: %s", tt.code, v)
+		}
+		if v.R() != tt.r || v.X() != tt.x || v.B() != tt.b {
+			t.Errorf("%s: %s", tt.code, v)
+		}
+	}
+}
+
+func TestVEXInvalid(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		err  error
+	}{
+		// VEX may not follow 66/F2/F3/LOCK or REX
+		{hde.Mode64, "66 c5 ec 58 cb", hde.ErrUnknownOpcode},
+		{hde.Mode64, "f3 c5 ec 58 cb", hde.ErrUnknownOpcode},
+		{hde.Mode64, "48 c5 ec 58 cb", hde.ErrUnknownOpcode},
+		// Reserved opcode map
+		{hde.Mode64, "c4 e4 69 00 cb", hde.ErrUnknownOpcode},
+		// No VEX encoding for the 0F map jump
+		{hde.Mode64, "c5 f8 84 00 00 00 00", hde.ErrUnknownOpcode},
+		// Truncated
+		{hde.Mode64, "c4 e2", hde.ErrLength},
+	}
+	for _, tt := range tests {
+		if _, err := tt.mode.Decode(unhex(t, tt.code)); err != tt.err {
+			t.Errorf("%s: got %v, want %v", tt.code, err, tt.err)
+		}
+	}
+}
+
+func TestEVEX(t *testing.T) {
+	tests := []struct {
+		mode   *hde.Mode
+		code   string
+		length uint8
+		ll     uint8
+		aaa    uint8
+		z, b   uint8
+		disp   int64 // displacement after disp8*N scaling
+	}{
+		// vfmadd231ps zmm1, zmm2, zmm3
+		{hde.Mode64, "62 f2 6d 48 b8 cb", 6, 2, 0, 0, 0, 0},
+		// vpcmpeqb k1, zmm2, [rax+0x40]
+		{hde.Mode64, "62 f1 6d 48 74 48 01", 7, 2, 0, 0, 0, 0x40},
+		// vpaddd zmm1{k2}{z}, zmm2, dword ptr [rax+0x100]{1to16}
+		{hde.Mode64, "62 f1 6d da fe 48 40", 7, 2, 2, 1, 1, 0x100},
+		// vmovdqu64 zmm0, [rsp+0x80]
+		{hde.Mode64, "62 f1 fe 48 6f 44 24 02", 8, 2, 0, 0, 0, 0x80},
+		// vaddpd zmm30, zmm29, zmm28
+		{hde.Mode64, "62 01 95 40 58 f4", 6, 2, 0, 0, 0, 0},
+		// vaddps zmm1, zmm2, zmm3, {rn-sae}
+		{hde.Mode64, "62 f1 6c 18 58 cb", 6, 0, 0, 0, 1, 0},
+		// vpternlogd zmm1, zmm2, [rcx+0x40], 0x96
+		{hde.Mode64, "62 f3 6d 48 25 49 01 96", 8, 2, 0, 0, 0, 0x40},
+		// vbroadcasti32x4 zmm1, [rdx+0x20]
+		{hde.Mode64, "62 f2 7d 48 5a 4a 02", 7, 2, 0, 0, 0, 0x20},
+		// vpmovdb [rax+0x20], zmm1
+		{hde.Mode64, "62 f2 7e 48 31 48 02", 7, 2, 0, 0, 0, 0x20},
+		// vprold zmm1, zmm2, 3
+		{hde.Mode64, "62 f1 75 48 72 ca 03", 7, 2, 0, 0, 0, 0},
+		// vcvttss2usi eax, [rax+0x8]
+		{hde.Mode64, "62 f1 7e 08 78 40 02", 7, 0, 0, 0, 0, 0x8},
+		// vcvtudq2pd zmm1, [rax+0x100]
+		{hde.Mode64, "62 f1 7e 48 7a 48 08", 7, 2, 0, 0, 0, 0x100},
+		// vpaddq ymm16, ymm17, [rax+0x1000]
+		{hde.Mode64, "62 e1 f5 20 d4 80 00 10 00 00", 10, 1, 0, 0, 0, 0x1000},
+		// vmovddup zmm1, [rax+0x40]
+		{hde.Mode64, "62 f1 ff 48 12 48 01", 7, 2, 0, 0, 0, 0x40},
+		// vgatherdps zmm1{k1}, [rax+zmm2*4+0x40]
+		{hde.Mode64, "62 f2 7d 49 92 4c 90 10", 8, 2, 1, 0, 0, 0x40},
+		// vpaddd zmm1{k2}{z}, zmm2, dword ptr [eax+0x100]{1to16} (32-bit)
+		{hde.Mode32, "62 f1 6d da fe 48 40", 7, 2, 2, 1, 1, 0x100},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if insn.Len() != tt.length {
+			t.Errorf("%s: length %d, want %d", tt.code, insn.Len(), tt.length)
+		}
+		if insn.Flags&hde.HasEVEX == 0 {
+			t.Errorf("%s: missing HasEVEX in %s", tt.code, insn.Flags)
+			continue
+		}
+		e := insn.EVEX
+		if e.LL() != tt.ll || e.AAA() != tt.aaa || e.Z() != tt.z || e.Bcst() != tt.b {
+			t.Errorf("%s: %s", tt.code, e)
+		}
+		if d, _ := insn.Displacement(); d != tt.disp {
+			t.Errorf("%s: displacement %#x, want %#x", tt.code, d, tt.disp)
+		}
+	}
+
+	// bound eax, [ecx] is still decoded as BOUND in 32-bit mode
+	insn, err := hde.Mode32.Decode(unhex(t, "62 01"))
+	if err != nil || insn.Len() != 2 || insn.Flags&hde.HasEVEX != 0 {
+		t.Errorf("bound: %v %+v", err, insn)
+	}
+}
+
+func TestThreeByteMaps(t *testing.T) {
+	tests := []struct {
+		code   string
+		length uint8
+		opmap  hde.OpcodeMap
+		op     uint8
+	}{
+		{"66 0f 38 00 ca", 5, hde.Map0F38, 0x00},                 // pshufb xmm1, xmm2
+		{"0f 38 00 08", 4, hde.Map0F38, 0x00},                    // pshufb mm1, [rax]
+		{"66 0f 38 10 48 10", 6, hde.Map0F38, 0x10},              // pblendvb xmm1, [rax+0x10], xmm0
+		{"66 0f 38 dc ca", 5, hde.Map0F38, 0xdc},                 // aesenc xmm1, xmm2
+		{"0f 38 cb ca", 4, hde.Map0F38, 0xcb},                    // sha256rnds2 xmm1, xmm2, xmm0
+		{"f2 0f 38 f0 01", 5, hde.Map0F38, 0xf0},                 // crc32 eax, byte ptr [rcx]
+		{"f2 48 0f 38 f1 01", 6, hde.Map0F38, 0xf1},              // crc32 rax, qword ptr [rcx]
+		{"0f 38 f0 01", 4, hde.Map0F38, 0xf0},                    // movbe eax, [rcx]
+		{"66 0f 38 f6 c3", 5, hde.Map0F38, 0xf6},                 // adcx eax, ebx
+		{"f3 48 0f 38 f6 c3", 6, hde.Map0F38, 0xf6},              // adox rax, rbx
+		{"66 0f 3a 0f ca 04", 6, hde.Map0F3A, 0x0f},              // palignr xmm1, xmm2, 4
+		{"66 0f 3a 16 c8 02", 6, hde.Map0F3A, 0x16},              // pextrd eax, xmm1, 2
+		{"66 0f 3a 63 48 20 0c", 7, hde.Map0F3A, 0x63},           // pcmpistri xmm1, [rax+0x20], 0xc
+		{"66 0f 3a df ca 01", 6, hde.Map0F3A, 0xdf},              // aeskeygenassist xmm1, xmm2, 1
+		{"0f 3a cc ca 03", 5, hde.Map0F3A, 0xcc},                 // sha1rnds4 xmm1, xmm2, 3
+		{"0f f6 ca", 3, hde.Map0F, 0xf6},                         // psadbw mm1, mm2
+		{"c4 e3 6d 02 cb 05", 6, hde.Map0F3A, 0x02},              // vpblendd ymm1, ymm2, ymm3, 5
+		{"62 f3 6d 48 25 49 01 96", 8, hde.Map0F3A, 0x25},        // vpternlogd zmm1, zmm2, [rcx+0x40], 0x96
+		{"62 f2 7d 49 92 4c 90 10", 8, hde.Map0F38, 0x92},        // vgatherdps zmm1{k1}, [rax+zmm2*4+0x40]
+		{"66 0f 38 f6 04 25 00 00 00 00", 10, hde.Map0F38, 0xf6}, // adcx eax, [0]
+	}
+	for _, tt := range tests {
+		for _, mode := range []*hde.Mode{hde.Mode32, hde.Mode64} {
+			code := unhex(t, tt.code)
+			if mode == hde.Mode32 && (code[0]&0xf0 == 0x40 || code[1]&0xf0 == 0x40 || code[0] == 0x62 || code[0] == 0xc4) {
+				continue
+			}
+			insn, err := mode.Decode(code)
+			if err != nil {
+				t.Errorf("%s: %v", tt.code, err)
+				continue
+			}
+			if insn.Len() != tt.length {
+				t.Errorf("%s: length %d, want %d", tt.code, insn.Len(), tt.length)
+			}
+			if insn.Map() != tt.opmap || insn.OpcodeByte() != tt.op {
+				t.Errorf("%s: opcode %02x in map %d, want %02x in map %d", tt.code, insn.OpcodeByte(), insn.Map(), tt.op, tt.opmap)
+			}
+		}
+	}
+
+	// Mandatory prefix violations
+	for _, code := range []string{
+		"0f 38 10 ca",       // pblendvb requires 66
+		"f3 0f 38 00 ca",    // pshufb has no F3 form
+		"f3 0f 38 f0 01",    // no F3 form of movbe/crc32
+		"66 0f 3a cc ca 03", // sha1rnds4 has no 66 form
+		"0f 3a 63 48 20 0c", // pcmpistri requires 66
+		"0f 38 50 00",       // unassigned
+		"f0 66 0f 38 00 08", // lock
+	} {
+		if _, err := hde.Mode64.Decode(unhex(t, code)); err == nil {
+			t.Errorf("%s: expected error", code)
+		}
+	}
+}
+
+func TestGroup3Immediate(t *testing.T) {
+	// Only the one-byte F6/F7 /0 and /1 (TEST) take an immediate, the two-byte
+	// 0F F6/F7 with the same ModR/M reg field do not
+	tests := []struct {
+		code   string
+		length uint8
+	}{
+		{"f6 c1 05", 3},                   // test cl, 5
+		{"f7 c1 78 56 34 12", 6},          // test ecx, 0x12345678
+		{"66 f7 c1 34 12", 5},             // test cx, 0x1234
+		{"f6 08 05", 3},                   // test byte ptr [rax], 5 (/1)
+		{"f6 d1", 2},                      // not cl
+		{"0f f6 c1", 3},                   // psadbw mm0, mm1
+		{"0f f6 ca", 3},                   // psadbw mm1, mm2
+		{"66 0f f6 08", 4},                // psadbw xmm1, [rax]
+		{"66 0f f6 40 10", 5},             // psadbw xmm0, [rax+0x10]
+		{"0f f7 ca", 3},                   // maskmovq mm1, mm2
+		{"66 0f f7 c1", 4},                // maskmovdqu xmm0, xmm1
+		{"c5 f9 f6 c1", 4},                // vpsadbw xmm0, xmm0, xmm1
+		{"c5 f9 f7 c1", 4},                // vmaskmovdqu xmm0, xmm1
+		{"66 0f f6 04 25 00 00 00 00", 9}, // psadbw xmm0, [0]
+	}
+	for _, tt := range tests {
+		for _, mode := range []*hde.Mode{hde.Mode32, hde.Mode64} {
+			code := unhex(t, tt.code)
+			if mode == hde.Mode32 && code[0] == 0xc5 {
+				continue
+			}
+			insn, err := mode.Decode(code)
+			if err != nil {
+				t.Errorf("%d-bit %s: %v", mode.Bits(), tt.code, err)
+				continue
+			}
+			if insn.Len() != tt.length {
+				t.Errorf("%d-bit %s: length %d, want %d", mode.Bits(), tt.code, insn.Len(), tt.length)
+			}
+		}
+	}
+}
+
+func TestPopcnt(t *testing.T) {
+	// F3 0F B8 is POPCNT and takes a ModR/M byte, 0F B8 alone is JMPE and takes
+	// none
+	tests := []struct {
+		code   string
+		length uint8
+		flags  hde.Flag
+	}{
+		{"f3 0f b8 c1", 4, hde.IsModRM},                                  // popcnt eax, ecx
+		{"66 f3 0f b8 c1", 5, hde.IsModRM},                               // popcnt ax, cx
+		{"f3 0f b8 44 24 08", 6, hde.IsModRM | hde.IsSIB | hde.HasDisp8}, // popcnt eax, [esp+8]
+		{"f3 0f b8 80 78 56 34 12", 8, hde.IsModRM | hde.HasDisp32},      // popcnt eax, [eax+0x12345678]
+		{"0f b8 c1", 2, 0}, // jmpe
+	}
+	const sizes = hde.IsModRM | hde.IsSIB | hde.HasDisp8 | hde.HasDisp32
+	for _, tt := range tests {
+		for _, mode := range []*hde.Mode{hde.Mode32, hde.Mode64} {
+			insn, err := mode.Decode(unhex(t, tt.code))
+			if err != nil {
+				t.Errorf("%d-bit %s: %v", mode.Bits(), tt.code, err)
+				continue
+			}
+			if insn.Len() != tt.length || insn.Flags&sizes != tt.flags {
+				t.Errorf("%d-bit %s: length %d, flags %s, want %d, %s", mode.Bits(), tt.code, insn.Len(), insn.Flags&sizes, tt.length, tt.flags)
+			}
+		}
+	}
+	insn, err := hde.Mode64.Decode(unhex(t, "f3 48 0f b8 c1"))
+	if err != nil || insn.Len() != 5 {
+		t.Errorf("popcnt rax, rcx: %v %+v", err, insn)
+	}
+}
+
+func TestTzcnt(t *testing.T) {
+	// F3 0F BC and BD are TZCNT and LZCNT, 0F BC and BD alone are BSF and BSR
+	tests := []struct {
+		code string
+		want hde.Mnemonic
+		len  uint8
+	}{
+		{"f3 0f bc c1", hde.TZCNT, 4},             // tzcnt eax, ecx
+		{"66 f3 0f bd c1", hde.LZCNT, 5},          // lzcnt ax, cx
+		{"f3 0f bd 44 24 08", hde.LZCNT, 6},       // lzcnt eax, [esp+8]
+		{"f3 0f bc 80 78 56 34 12", hde.TZCNT, 8}, // tzcnt eax, [eax+0x12345678]
+		{"0f bc c1", hde.BSF, 3},                  // bsf eax, ecx
+		{"0f bd c1", hde.BSR, 3},                  // bsr eax, ecx
+	}
+	for _, tt := range tests {
+		for _, mode := range []*hde.Mode{hde.Mode32, hde.Mode64} {
+			insn, err := mode.Decode(unhex(t, tt.code))
+			if err != nil {
+				t.Errorf("%d-bit %s: %v", mode.Bits(), tt.code, err)
+				continue
+			}
+			if insn.Len() != tt.len || insn.Mnemonic() != tt.want {
+				t.Errorf("%d-bit %s: %v of length %d, want %v of length %d", mode.Bits(), tt.code, insn.Mnemonic(), insn.Len(), tt.want, tt.len)
+			}
+		}
+	}
+	insn, err := hde.Mode64.Decode(unhex(t, "f3 48 0f bc c1"))
+	if err != nil || insn.Len() != 5 || insn.Mnemonic() != hde.TZCNT {
+		t.Errorf("tzcnt rax, rcx: %v %+v", err, insn)
+	}
+}
+
+func TestUndefined(t *testing.T) {
+	// UD2 takes no ModR/M byte, UD1 and UD0 take one
+	tests := []struct {
+		code   string
+		length uint8
+		flags  hde.Flag
+	}{
+		{"0f 0b", 2, 0},              // ud2
+		{"f3 0f 0b", 3, 0},           // repz ud2
+		{"0f b9 c1", 3, hde.IsModRM}, // ud1 eax, ecx
+		{"0f b9 44 24 08", 5, hde.IsModRM | hde.IsSIB | hde.HasDisp8}, // ud1 eax, [esp+8]
+		{"0f ff c1", 3, hde.IsModRM},                                  // ud0 eax, ecx
+		{"0f ff 80 78 56 34 12", 7, hde.IsModRM | hde.HasDisp32},      // ud0 eax, [eax+0x12345678]
+	}
+	const sizes = hde.IsModRM | hde.IsSIB | hde.HasDisp8 | hde.HasDisp32
+	for _, tt := range tests {
+		for _, mode := range []*hde.Mode{hde.Mode32, hde.Mode64} {
+			insn, err := mode.Decode(unhex(t, tt.code))
+			if err != nil {
+				t.Errorf("%d-bit %s: %v", mode.Bits(), tt.code, err)
+				continue
+			}
+			if insn.Len() != tt.length || insn.Flags&sizes != tt.flags {
+				t.Errorf("%d-bit %s: length %d, flags %s, want %d, %s", mode.Bits(), tt.code, insn.Len(), insn.Flags&sizes, tt.length, tt.flags)
+			}
+		}
+	}
+}
+
+func TestMode16(t *testing.T) {
+	const sizes = hde.HasImm8 | hde.HasImm16 | hde.HasImm32 | hde.HasDisp8 | hde.HasDisp16 | hde.HasDisp32 | hde.IsSIB
+	tests := []struct {
+		code   string
+		length uint8
+		flags  hde.Flag
+	}{
+		{"8b 00", 2, 0},                                        // mov ax, [bx+si]
+		{"8b 43 10", 3, hde.HasDisp8},                          // mov ax, [bp+di+0x10]
+		{"8b 06 34 12", 4, hde.HasDisp16},                      // mov ax, [0x1234]
+		{"8b 87 34 12", 4, hde.HasDisp16},                      // mov ax, [bx+0x1234]
+		{"8b 04", 2, 0},                                        // mov ax, [si] (no SIB in 16-bit addressing)
+		{"67 66 8b 44 8b 10", 6, hde.IsSIB | hde.HasDisp8},     // mov eax, [ebx+ecx*4+0x10]
+		{"67 8b 05 78 56 34 12", 7, hde.HasDisp32},             // mov ax, [0x12345678]
+		{"b8 34 12", 3, hde.HasImm16},                          // mov ax, 0x1234
+		{"66 b8 78 56 34 12", 6, hde.HasImm32},                 // mov eax, 0x12345678
+		{"e9 fd 00", 3, hde.HasImm16},                          // jmp rel16
+		{"66 e8 fd 00 00 00", 6, hde.HasImm32},                 // call rel32
+		{"68 34 12", 3, hde.HasImm16},                          // push 0x1234
+		{"a1 34 12", 3, hde.HasImm16},                          // mov ax, [0x1234]
+		{"67 a0 78 56 34 12", 6, hde.HasImm32},                 // mov al, [0x12345678]
+		{"cd 10", 2, hde.HasImm8},                              // int 0x10
+		{"ea f0 ff 00 f0", 5, hde.HasImm16 | hde.Has2Imm16},    // jmp 0xf000:0xfff0
+		{"c8 10 00 00", 4, hde.HasImm8 | hde.HasImm16},         // enter 0x10, 0
+		{"f7 06 34 12 78 56", 6, hde.HasImm16 | hde.HasDisp16}, // test word ptr [0x1234], 0x5678
+	}
+	for _, tt := range tests {
+		insn, err := hde.Mode16.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if insn.Len() != tt.length {
+			t.Errorf("%s: length %d, want %d", tt.code, insn.Len(), tt.length)
+		}
+		if insn.Flags&sizes != tt.flags&sizes {
+			t.Errorf("%s: flags %s, want %s", tt.code, insn.Flags&sizes, tt.flags&sizes)
+		}
+	}
+}
+
+func TestAddrSize64(t *testing.T) {
+	// 67h selects 32-bit addressing in 64-bit mode, not 16-bit, and shrinks the
+	// memory offset of A0-A3 from 8 bytes to 4
+	const sizes = hde.HasImm8 | hde.HasImm16 | hde.HasImm32 | hde.HasImm64 | hde.HasDisp8 | hde.HasDisp16 | hde.HasDisp32 | hde.IsSIB
+	tests := []struct {
+		code   string
+		length uint8
+		flags  hde.Flag
+		base   hde.Register
+	}{
+		{"67 8b 00", 3, 0, hde.EAX},                                                     // mov eax, [eax]
+		{"67 8b 06", 3, 0, hde.ESI},                                                     // mov eax, [esi]
+		{"67 8b 05 78 56 34 12", 7, hde.HasDisp32, hde.EIP},                             // mov eax, [eip+0x12345678]
+		{"67 8b 46 10", 4, hde.HasDisp8, hde.ESI},                                       // mov eax, [esi+0x10]
+		{"67 8b 80 78 56 34 12", 7, hde.HasDisp32, hde.EAX},                             // mov eax, [eax+0x12345678]
+		{"67 8b 44 8b 10", 5, hde.IsSIB | hde.HasDisp8, hde.EBX},                        // mov eax, [ebx+ecx*4+0x10]
+		{"67 8b 04 25 78 56 34 12", 8, hde.IsSIB | hde.HasDisp32, 0},                    // mov eax, [0x12345678]
+		{"67 41 8b 46 10", 5, hde.HasDisp8, hde.R14D},                                   // mov eax, [r14d+0x10]
+		{"67 a0 78 56 34 12", 6, hde.HasImm32, 0},                                       // mov al, [0x12345678]
+		{"67 48 a1 78 56 34 12", 7, hde.HasImm32, 0},                                    // mov rax, [0x12345678]
+		{"67 a3 78 56 34 12", 6, hde.HasImm32, 0},                                       // mov [0x12345678], eax
+		{"a0 f0 de bc 9a 78 56 34 12", 9, hde.HasImm64, 0},                              // mov al, [0x123456789abcdef0]
+		{"67 c7 80 78 56 34 12 01 00 00 00", 11, hde.HasDisp32 | hde.HasImm32, hde.EAX}, // mov dword ptr [eax+0x12345678], 1
+	}
+	for _, tt := range tests {
+		insn, err := hde.Mode64.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if insn.Len() != tt.length {
+			t.Errorf("%s: length %d, want %d", tt.code, insn.Len(), tt.length)
+		}
+		if insn.Flags&sizes != tt.flags&sizes {
+			t.Errorf("%s: flags %s, want %s", tt.code, insn.Flags&sizes, tt.flags&sizes)
+		}
+		if insn.Flags&hde.IsModRM == 0 {
+			continue
+		}
+		for _, op := range insn.Operands() {
+			if op.Type == hde.OpMem && op.Mem.Base != tt.base {
+				t.Errorf("%s: base %s, want %s", tt.code, op.Mem.Base, tt.base)
+			}
+		}
+	}
+}
+
+func TestEnter(t *testing.T) {
+	// ENTER keeps its imm16 frame size in Imm and its imm8 nesting level in Disp
+	for _, mode := range []*hde.Mode{hde.Mode16, hde.Mode32, hde.Mode64} {
+		insn, err := mode.Decode(unhex(t, "c8 34 12 03"))
+		if err != nil || insn.Len() != 4 {
+			t.Errorf("%d-bit: %v %+v", mode.Bits(), err, insn)
+			continue
+		}
+		if insn.Imm != (hde.Literal{Value: 0x1234, Bits: 16}) || insn.Disp != (hde.Literal{Value: 3, Bits: 8}) {
+			t.Errorf("%d-bit: imm %+v, disp %+v", mode.Bits(), insn.Imm, insn.Disp)
+		}
+		if insn.Flags&(hde.HasImm8|hde.HasImm16|hde.HasDisp8) != hde.HasImm8|hde.HasImm16 {
+			t.Errorf("%d-bit: flags %s", mode.Bits(), insn.Flags)
+		}
+	}
+}
+
+func TestMnemonic(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want hde.Mnemonic
+	}{
+		{hde.Mode64, "48 89 d8", hde.MOV}, // mov rax, rbx
+		{hde.Mode64, "48 b8 88 77 66 55 44 33 22 11", hde.MOV},
+		{hde.Mode64, "83 e8 01", hde.SUB},             // sub eax, 1
+		{hde.Mode64, "f7 d8", hde.NEG},                // neg eax
+		{hde.Mode64, "ff d0", hde.CALL},               // call rax
+		{hde.Mode64, "ff 28", hde.JMP},                // jmp far [rax]
+		{hde.Mode64, "74 00", hde.JE},                 // je
+		{hde.Mode64, "0f 4f c1", hde.CMOVG},           // cmovg eax, ecx
+		{hde.Mode64, "0f 94 c0", hde.SETE},            // sete al
+		{hde.Mode64, "90", hde.NOP},                   // nop
+		{hde.Mode64, "41 90", hde.XCHG},               // xchg r8d, eax
+		{hde.Mode64, "f3 90", hde.PAUSE},              // pause
+		{hde.Mode64, "98", hde.CWDE},                  // cwde
+		{hde.Mode64, "48 98", hde.CDQE},               // cdqe
+		{hde.Mode64, "66 98", hde.CBW},                // cbw
+		{hde.Mode64, "9c", hde.PUSHFQ},                // pushfq
+		{hde.Mode32, "9c", hde.PUSHFD},                // pushfd
+		{hde.Mode16, "9c", hde.PUSHF},                 // pushf
+		{hde.Mode64, "f3 48 ab", hde.STOSQ},           // rep stosq
+		{hde.Mode64, "e3 00", hde.JRCXZ},              // jrcxz
+		{hde.Mode64, "67 e3 00", hde.JECXZ},           // jecxz
+		{hde.Mode64, "63 c1", hde.MOVSXD},             // movsxd eax, ecx
+		{hde.Mode32, "63 c1", hde.ARPL},               // arpl cx, ax
+		{hde.Mode64, "0f 10 c1", hde.MOVUPS},          // movups xmm0, xmm1
+		{hde.Mode64, "66 0f 10 c1", hde.MOVUPD},       // movupd xmm0, xmm1
+		{hde.Mode64, "f3 0f 10 c1", hde.MOVSS},        // movss xmm0, xmm1
+		{hde.Mode64, "f2 0f 10 c1", hde.MOVSD},        // movsd xmm0, xmm1
+		{hde.Mode64, "66 0f 6e c0", hde.MOVD},         // movd xmm0, eax
+		{hde.Mode64, "66 48 0f 6e c0", hde.MOVQ},      // movq xmm0, rax
+		{hde.Mode64, "f3 0f b8 c1", hde.POPCNT},       // popcnt eax, ecx
+		{hde.Mode64, "c7 f8 00 00 00 00", hde.XBEGIN}, // xbegin
+		{hde.Mode64, "c6 f8 ff", hde.XABORT},          // xabort 0xff
+		{hde.Mode64, "0f 01 f9", hde.RDTSCP},          // rdtscp
+		{hde.Mode64, "0f 0b", hde.UD2},                // ud2
+		{hde.Mode64, "0f 01 e8", hde.SERIALIZE},       // serialize
+		{hde.Mode64, "0f 01 ee", hde.RDPKRU},          // rdpkru
+		{hde.Mode64, "0f 01 ef", hde.WRPKRU},          // wrpkru
+		{hde.Mode64, "0f b9 c1", hde.UD1},             // ud1 eax, ecx
+		{hde.Mode64, "0f ff c1", hde.UD0},             // ud0 eax, ecx
+		{hde.Mode64, "0f ae f0", hde.MFENCE},          // mfence
+		{hde.Mode64, "0f ae 38", hde.CLFLUSH},         // clflush [rax]
+		{hde.Mode64, "66 0f 38 00 c1", hde.PSHUFB},    // pshufb xmm0, xmm1
+		{hde.Mode64, "66 0f 3a 16 c0 01", hde.PEXTRD}, // pextrd eax, xmm0, 1
+		{hde.Mode64, "c5 ec 58 cb", hde.VADDPS},       // vaddps ymm1, ymm2, ymm3
+		{hde.Mode64, "c4 e2 e0 f2 c1", hde.ANDN},      // andn rax, rbx, rcx
+		{hde.Mode64, "c4 e3 f9 16 c0 01", hde.VPEXTRQ},
+		{hde.Mode64, "62 f1 7d 48 6f c1", hde.VMOVDQA32},
+		{hde.Mode64, "62 f1 fd 48 6f c1", hde.VMOVDQA64},
+		{hde.Mode64, "62 f3 7d 48 25 c1 ff", hde.VPTERNLOGD},
+		{hde.Mode64, "d9 e8", hde.FLD1},  // fld1
+		{hde.Mode64, "dd 18", hde.FSTP},  // fstp qword [rax]
+		{hde.Mode64, "de e9", hde.FSUBP}, // fsubp st(1), st
+		{hde.Mode64, "0f 31", hde.RDTSC}, // rdtsc
+		{hde.Mode64, "0f 04", hde.INVALID},
+		{hde.Mode64, "06", hde.INVALID}, // push es
+		{hde.Mode32, "06", hde.PUSH},    // push es
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			if tt.want != hde.INVALID {
+				t.Errorf("%s: %v", tt.code, err)
+			}
+			continue
+		}
+		if got := insn.Mnemonic(); got != tt.want {
+			t.Errorf("%s: mnemonic %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestOperandSize(t *testing.T) {
+	tests := []struct {
+		mode          *hde.Mode
+		code          string
+		opSize, aSize int
+	}{
+		{hde.Mode64, "89 d8", 32, 64},    // mov eax, ebx
+		{hde.Mode64, "48 89 d8", 64, 64}, // mov rax, rbx
+		{hde.Mode64, "66 89 d8", 16, 64}, // mov ax, bx
+		{hde.Mode64, "67 8b 00", 32, 32}, // mov eax, [eax]
+		{hde.Mode64, "50", 64, 64},       // push rax
+		{hde.Mode64, "66 50", 16, 64},    // push ax
+		{hde.Mode64, "e8 00 00 00 00", 64, 64},
+		{hde.Mode32, "50", 32, 32},          // push eax
+		{hde.Mode32, "66 67 8b 00", 16, 16}, // mov ax, [bx+si]
+		{hde.Mode16, "8b 00", 16, 16},       // mov ax, [bx+si]
+		{hde.Mode16, "66 8b 00", 32, 16},    // mov eax, [bx+si]
+		{hde.Mode16, "67 8b 00", 16, 32},    // mov ax, [eax]
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.OperandSize(); got != tt.opSize {
+			t.Errorf("%s: operand size %d, want %d", tt.code, got, tt.opSize)
+		}
+		if got := insn.AddressSize(); got != tt.aSize {
+			t.Errorf("%s: address size %d, want %d", tt.code, got, tt.aSize)
+		}
+	}
+}
+
+func TestOperands(t *testing.T) {
+	reg := func(r hde.Register) hde.Operand {
+		return hde.Operand{Type: hde.OpReg, Size: uint16(r.Size()), Reg: r}
+	}
+	mem := func(size uint16, m hde.Mem) hde.Operand {
+		return hde.Operand{Type: hde.OpMem, Size: size, Mem: m}
+	}
+	imm := func(size uint16, v int64) hde.Operand {
+		return hde.Operand{Type: hde.OpImm, Size: size, Imm: v}
+	}
+
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want []hde.Operand
+	}{
+		// mov rax, rcx
+		{hde.Mode64, "48 89 c8", []hde.Operand{reg(hde.RAX), reg(hde.RCX)}},
+		// mov ax, [rbp+8]
+		{hde.Mode64, "66 8b 45 08", []hde.Operand{
+			reg(hde.AX),
+			mem(16, hde.Mem{Segment: hde.SegSS, Base: hde.RBP, Disp: 8}),
+		}},
+		// mov r9, [rip+0x10]
+		{hde.Mode64, "4c 8b 0d 10 00 00 00", []hde.Operand{
+			reg(hde.R9),
+			mem(64, hde.Mem{Segment: hde.SegDS, Base: hde.RIP, Disp: 0x10}),
+		}},
+		// lea eax, [r8+r12*4-4]
+		{hde.Mode64, "43 8d 44 a0 fc", []hde.Operand{
+			reg(hde.EAX),
+			mem(0, hde.Mem{Segment: hde.SegDS, Base: hde.R8, Index: hde.R12, Scale: 4, Disp: -4}),
+		}},
+		// movzx eax, ah / movzx eax, spl
+		{hde.Mode64, "0f b6 c4", []hde.Operand{reg(hde.EAX), reg(hde.AH)}},
+		{hde.Mode64, "40 0f b6 c4", []hde.Operand{reg(hde.EAX), reg(hde.SPL)}},
+		// add eax, -1
+		{hde.Mode64, "83 c0 ff", []hde.Operand{reg(hde.EAX), imm(32, -1)}},
+		// mov rax, -1
+		{hde.Mode64, "48 c7 c0 ff ff ff ff", []hde.Operand{reg(hde.RAX), imm(64, -1)}},
+		// call $+0
+		{hde.Mode64, "e8 fb ff ff ff", []hde.Operand{{Type: hde.OpRel, Size: 64, Imm: -5}}},
+		// enter 0x10, 1
+		{hde.Mode64, "c8 10 00 01", []hde.Operand{imm(16, 0x10), imm(8, 1)}},
+		// movsq
+		{hde.Mode64, "48 a5", []hde.Operand{
+			mem(64, hde.Mem{Segment: hde.SegES, Base: hde.RDI}),
+			mem(64, hde.Mem{Segment: hde.SegDS, Base: hde.RSI}),
+		}},
+		// mov eax, fs:[0x30]
+		{hde.Mode64, "64 a1 30 00 00 00 00 00 00 00", []hde.Operand{
+			reg(hde.EAX),
+			mem(32, hde.Mem{Segment: hde.SegFS, Disp: 0x30}),
+		}},
+		// fadd st, st(1)
+		{hde.Mode64, "d8 c1", []hde.Operand{reg(hde.ST0), reg(hde.ST1)}},
+		// vaddps xmm0, xmm1, xmm2
+		{hde.Mode64, "c5 f0 58 c2", []hde.Operand{
+			reg(hde.XMM0), reg(hde.XMM1), reg(hde.XMM2),
+		}},
+		// addps xmm0, xmm2
+		{hde.Mode64, "0f 58 c2", []hde.Operand{reg(hde.XMM0), reg(hde.XMM2)}},
+		// vaddps zmm0, zmm0, [rax]{1to16}
+		{hde.Mode64, "62 f1 7c 58 58 00", []hde.Operand{
+			reg(hde.ZMM0), reg(hde.ZMM0),
+			mem(32, hde.Mem{Segment: hde.SegDS, Base: hde.RAX, Broadcast: true}),
+		}},
+		// vpgatherdq zmm1{k1}, [rbp+ymm1*4]
+		{hde.Mode64, "62 f2 fd 49 90 4c 8d 00", []hde.Operand{
+			reg(hde.ZMM1),
+			mem(64, hde.Mem{Segment: hde.SegSS, Base: hde.RBP, Index: hde.YMM1, Scale: 4}),
+		}},
+		// jmp 0x1000:0x12345678
+		{hde.Mode32, "ea 78 56 34 12 00 10", []hde.Operand{{Type: hde.OpFar, Size: 48, Imm: 0x12345678, Selector: 0x1000}}},
+		// mov ax, [bx+si]
+		{hde.Mode16, "8b 00", []hde.Operand{
+			reg(hde.AX),
+			mem(16, hde.Mem{Segment: hde.SegDS, Base: hde.BX, Index: hde.SI, Scale: 1}),
+		}},
+		// mov eax, [bp+2]
+		{hde.Mode16, "66 8b 46 02", []hde.Operand{
+			reg(hde.EAX),
+			mem(32, hde.Mem{Segment: hde.SegSS, Base: hde.BP, Disp: 2}),
+		}},
+		{hde.Mode64, "90", nil}, // nop
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.Operands(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: operands\n got %+v\nwant %+v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestRegisters(t *testing.T) {
+	tests := []struct {
+		mode                             *hde.Mode
+		code                             string
+		reg, rm, vvvv, base, index, mask hde.Register
+	}{
+		// mov ah, ch / mov spl, bpl / mov r13b, r12b
+		{mode: hde.Mode64, code: "88 ec", reg: hde.CH, rm: hde.AH},
+		{mode: hde.Mode64, code: "40 88 ec", reg: hde.BPL, rm: hde.SPL},
+		{mode: hde.Mode64, code: "45 88 e5", reg: hde.R12B, rm: hde.R13B},
+		// mov r9, [rip+0x10]
+		{mode: hde.Mode64, code: "4c 8b 0d 10 00 00 00", reg: hde.R9, base: hde.RIP},
+		// lea eax, [r8d+r12d*4-4]
+		{mode: hde.Mode64, code: "67 43 8d 44 a0 fc", reg: hde.EAX, base: hde.R8D, index: hde.R12D},
+		// push r15
+		{mode: hde.Mode64, code: "41 57", rm: hde.R15},
+		// mov cr8, rax / mov ds, eax
+		{mode: hde.Mode64, code: "44 0f 22 c0", reg: hde.CR8, rm: hde.RAX},
+		{mode: hde.Mode64, code: "8e d8", reg: hde.DS, rm: hde.EAX},
+		// fmul st(3), st
+		{mode: hde.Mode64, code: "dc cb", rm: hde.ST3},
+		// paddb mm1, mm2
+		{mode: hde.Mode64, code: "0f fc ca", reg: hde.MM1, rm: hde.MM2},
+		// vaddps ymm9, ymm10, ymm11
+		{mode: hde.Mode64, code: "c4 41 2c 58 cb", reg: hde.YMM9, rm: hde.YMM11, vvvv: hde.YMM10},
+		// andn eax, ebx, ecx
+		{mode: hde.Mode64, code: "c4 e2 60 f2 c1", reg: hde.EAX, rm: hde.ECX, vvvv: hde.EBX},
+		// vaddps zmm17{k2}, zmm18, [rax+rbx*8]
+		{mode: hde.Mode64, code: "62 e1 6c 42 58 0c d8", reg: hde.ZMM17, vvvv: hde.ZMM18, base: hde.RAX, index: hde.RBX, mask: hde.K2},
+		// vpgatherdq zmm1{k1}, [rbp+ymm1*4]
+		{mode: hde.Mode64, code: "62 f2 fd 49 90 4c 8d 00", reg: hde.ZMM1, base: hde.RBP, index: hde.YMM1, mask: hde.K1},
+		// kandw k1, k2, k3
+		{mode: hde.Mode64, code: "c5 ec 41 cb", reg: hde.K1, rm: hde.K3, vvvv: hde.K2},
+		// mov si, [bp+di]
+		{mode: hde.Mode16, code: "8b 33", reg: hde.SI, base: hde.BP, index: hde.DI},
+		// inc ebx
+		{mode: hde.Mode32, code: "43", rm: hde.EBX},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		got := [...]hde.Register{insn.Reg(), insn.RM(), insn.Vvvv(), insn.Base(), insn.Index(), insn.Mask()}
+		want := [...]hde.Register{tt.reg, tt.rm, tt.vvvv, tt.base, tt.index, tt.mask}
+		if got != want {
+			t.Errorf("%s: reg, rm, vvvv, base, index, mask = %v, want %v", tt.code, got, want)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		reg   hde.Register
+		name  string
+		class hde.RegClass
+		num   uint8
+		size  int
+	}{
+		{hde.AL, "al", hde.ClassGPR, 0, 8},
+		{hde.BH, "bh", hde.ClassGPR, 7, 8},
+		{hde.R15B, "r15b", hde.ClassGPR, 15, 8},
+		{hde.R10W, "r10w", hde.ClassGPR, 10, 16},
+		{hde.ESP, "esp", hde.ClassGPR, 4, 32},
+		{hde.R8, "r8", hde.ClassGPR, 8, 64},
+		{hde.RIP, "rip", hde.ClassIP, 0, 64},
+		{hde.GS, "gs", hde.ClassSeg, 5, 16},
+		{hde.CR4, "cr4", hde.ClassCR, 4, 64},
+		{hde.DR7, "dr7", hde.ClassDR, 7, 64},
+		{hde.ST1, "st(1)", hde.ClassX87, 1, 80},
+		{hde.MM7, "mm7", hde.ClassMMX, 7, 64},
+		{hde.XMM31, "xmm31", hde.ClassXMM, 31, 128},
+		{hde.YMM0, "ymm0", hde.ClassYMM, 0, 256},
+		{hde.ZMM16, "zmm16", hde.ClassZMM, 16, 512},
+		{hde.K7, "k7", hde.ClassMask, 7, 64},
+		{hde.RegNone, "", hde.ClassNone, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.reg.String(); got != tt.name {
+			t.Errorf("%v: name %q, want %q", tt.reg, got, tt.name)
+		}
+		if got := tt.reg.Class(); got != tt.class {
+			t.Errorf("%v: class %d, want %d", tt.reg, got, tt.class)
+		}
+		if got := tt.reg.Num(); got != tt.num {
+			t.Errorf("%v: num %d, want %d", tt.reg, got, tt.num)
+		}
+		if got := tt.reg.Size(); got != tt.size {
+			t.Errorf("%v: size %d, want %d", tt.reg, got, tt.size)
+		}
+	}
+}
+
+func TestFormatIntel(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		addr uint64
+		code string
+		want string
+	}{
+		{hde.Mode64, 0, "48 8b 05 34 12 00 00", "mov rax, qword ptr [rip+0x1234]"},
+		{hde.Mode64, 0, "48 8d 05 92 fe ff ff", "lea rax, [rip+0xfffffffffffffe92]"},
+		{hde.Mode64, 0, "48 89 44 24 f8", "mov qword ptr [rsp-0x8], rax"},
+		{hde.Mode64, 0, "8b 04 8d 00 00 00 00", "mov eax, dword ptr [rcx*4+0x0]"},
+		{hde.Mode64, 0, "8a 04 0b", "mov al, byte ptr [rbx+rcx*1]"},
+		{hde.Mode64, 0, "64 48 8b 04 25 28 00 00 00", "mov rax, qword ptr fs:0x28"},
+		{hde.Mode64, 0, "48 b8 88 77 66 55 44 33 22 11", "movabs rax, 0x1122334455667788"},
+		{hde.Mode64, 0, "48 c7 c0 ff ff ff ff", "mov rax, 0xffffffffffffffff"},
+		{hde.Mode64, 0, "6a 80", "push 0xffffffffffffff80"},
+		{hde.Mode64, 0, "c8 10 00 01", "enter 0x10, 0x1"},
+		{hde.Mode64, 0, "d1 e0", "shl eax, 1"},
+		{hde.Mode64, 0x1000, "e8 fb 0f 00 00", "call 0x2000"},
+		{hde.Mode64, 0x1000, "75 fe", "jne 0x1000"},
+		{hde.Mode64, 0, "ff 24 c5 00 10 00 00", "jmp qword ptr [rax*8+0x1000]"},
+		{hde.Mode64, 0, "3e ff e0", "notrack jmp rax"},
+		{hde.Mode64, 0, "f2 ff e0", "bnd jmp rax"},
+		{hde.Mode64, 0, "f3 c3", "repz ret"},
+		{hde.Mode64, 0, "f3 a4", "rep movs byte ptr es:[rdi], byte ptr ds:[rsi]"},
+		{hde.Mode64, 0, "f2 ae", "repnz scas al, byte ptr es:[rdi]"},
+		{hde.Mode64, 0, "48 ab", "stos qword ptr es:[rdi], rax"},
+		{hde.Mode64, 0, "f0 0f b1 0a", "lock cmpxchg dword ptr [rdx], ecx"},
+		{hde.Mode64, 0, "48 0f c7 08", "cmpxchg16b oword ptr [rax]"},
+		{hde.Mode64, 0, "66 2e 0f 1f 84 00 00 00 00 00", "cs nop word ptr [rax+rax*1+0x0]"},
+		{hde.Mode64, 0, "d8 c1", "fadd st, st(1)"},
+		{hde.Mode64, 0, "d9 c0", "fld st(0)"},
+		{hde.Mode64, 0, "db 28", "fld tbyte ptr [rax]"},
+		{hde.Mode64, 0, "0f 01 00", "sgdt [rax]"},
+		{hde.Mode64, 0, "f2 0f 10 00", "movsd xmm0, qword ptr [rax]"},
+		{hde.Mode64, 0, "c4 e2 75 0c 00", "vpermilps ymm0, ymm1, ymmword ptr [rax]"},
+		{hde.Mode64, 0, "62 f1 74 d9 58 00", "vaddps zmm0{k1}{z}, zmm1, dword bcst [rax]"},
+		{hde.Mode64, 0, "62 f1 74 18 58 c2", "vaddps zmm0, zmm1, zmm2{rn-sae}"},
+		{hde.Mode64, 0, "62 f1 7f 49 6f 40 01", "vmovdqu8 zmm0{k1}, zmmword ptr [rax+0x40]"},
+		{hde.Mode64, 0, "62 f2 fd 49 90 4c 8d 00", "vpgatherdq zmm1{k1}, qword ptr [rbp+ymm1*4+0x0]"},
+		{hde.Mode64, 0, "62 f3 7d 48 3f c2 00", "vpcmpeqb k0, zmm0, zmm2"},
+		{hde.Mode64, 0, "62 f3 7d 48 3e c2 01", "vpcmpltub k0, zmm0, zmm2"},
+		{hde.Mode64, 0, "62 f3 7d 48 3f c2 03", "vpcmpb k0, zmm0, zmm2, 0x3"},
+		{hde.Mode64, 0, "62 f3 fd 48 1e 40 01 06", "vpcmpnleuq k0, zmm0, zmmword ptr [rax+0x40]"},
+		{hde.Mode64, 0, "62 f3 7d 59 1f 00 04", "vpcmpneqd k0{k1}, zmm0, dword bcst [rax]"},
+		{hde.Mode64, 0, "62 f3 7d 48 3f c2 08", "vpcmpb k0, zmm0, zmm2, 0x8"},
+		{hde.Mode64, 0, "0f c2 c1 01", "cmpltps xmm0, xmm1"},
+		{hde.Mode64, 0, "66 0f c2 08 03", "cmpunordpd xmm1, xmmword ptr [rax]"},
+		{hde.Mode64, 0, "f3 0f c2 c1 07", "cmpordss xmm0, xmm1"},
+		{hde.Mode64, 0, "f2 0f c2 c1 04", "cmpneqsd xmm0, xmm1"},
+		{hde.Mode64, 0, "0f c2 c1 08", "cmpps xmm0, xmm1, 0x8"},
+		{hde.Mode64, 0, "c5 f0 c2 c2 08", "vcmpeq_uqps xmm0, xmm1, xmm2"},
+		{hde.Mode64, 0, "c5 f5 c2 c2 1f", "vcmptrue_uspd ymm0, ymm1, ymm2"},
+		{hde.Mode64, 0, "c5 f2 c2 c2 20", "vcmpss xmm0, xmm1, xmm2, 0x20"},
+		{hde.Mode64, 0, "62 f1 74 48 c2 c2 10", "vcmpeq_osps k0, zmm1, zmm2"},
+		{hde.Mode64, 0, "62 f1 f7 18 c2 c2 00", "vcmpeqsd k0, xmm1, xmm2{sae}"},
+		{hde.Mode64, 0, "62 f1 f7 18 c2 c2 20", "vcmpsd k0, xmm1, xmm2{sae}, 0x20"},
+		{hde.Mode64, 0, "a7", "cmps dword ptr ds:[rsi], dword ptr es:[rdi]"},
+		{hde.Mode64, 0, "66 8c d8", "mov ax, ds"},
+		{hde.Mode64, 0, "66 8c 18", "mov word ptr [rax], ds"}, // objdump: data16 mov
+		{hde.Mode64, 0, "66 8e 18", "mov ds, word ptr [rax]"}, // objdump: data16 mov
+		{hde.Mode32, 0, "a1 34 12 00 00", "mov eax, ds:0x1234"},
+		{hde.Mode32, 0, "8b 04 25 28 00 00 00", "mov eax, dword ptr [eiz*1+0x28]"},
+		{hde.Mode32, 0, "ea 78 56 34 12 00 10", "jmp 0x1000:0x12345678"},
+		{hde.Mode32, 0, "ff 2d 00 10 00 00", "jmp fword ptr ds:0x1000"},
+		{hde.Mode16, 0, "8b 00", "mov ax, word ptr [bx+si]"},
+		{hde.Mode16, 0, "26 8b 46 fe", "mov ax, word ptr es:[bp-0x2]"},
+		{hde.Mode16, 0x1fff0, "e8 20 00", "call 0x10013"},
+		{hde.Mode64, 0, "0f 31", "rdtsc"},
+		{hde.Mode64, 0, "0f 0b", "ud2"},
+		{hde.Mode64, 0, "0f 01 ee", "rdpkru"},
+		{hde.Mode64, 0, "f3 48 0f bc c1", "tzcnt rax, rcx"},
+		{hde.Mode64, 0, "f3 0f bd 44 24 08", "lzcnt eax, dword ptr [rsp+0x8]"},
+		{hde.Mode64, 0, "0f b9 c1", "ud1 eax, ecx"},
+		{hde.Mode64, 0, "0f ff 48 08", "ud0 ecx, dword ptr [rax+0x8]"},
+		{hde.Mode64, 0, "0f 0f c1 0c", "(bad)"},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.FormatIntel(tt.addr); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.code, got, tt.want)
+		}
+	}
+
+	insn, err := hde.Mode64.Decode(unhex(t, "48 89 c8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprintf("%v|%s", insn, &insn); got != "mov rax, rcx|mov rax, rcx" {
+		t.Errorf("Sprintf: got %q", got)
+	}
+}
+
+func TestFormatATT(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		addr uint64
+		code string
+		want string
+	}{
+		{hde.Mode64, 0, "48 8b 05 34 12 00 00", "movq 0x1234(%rip), %rax"},
+		{hde.Mode64, 0, "48 89 44 24 f8", "movq %rax, -0x8(%rsp)"},
+		{hde.Mode64, 0, "8b 04 8d 00 00 00 00", "movl 0x0(,%rcx,4), %eax"},
+		{hde.Mode64, 0, "64 48 8b 04 25 28 00 00 00", "movq %fs:0x28, %rax"},
+		{hde.Mode64, 0, "48 b8 88 77 66 55 44 33 22 11", "movabsq $0x1122334455667788, %rax"},
+		{hde.Mode64, 0, "66 89 c8", "movw %cx, %ax"},
+		{hde.Mode64, 0, "6a 80", "pushq $0xffffffffffffff80"},
+		{hde.Mode64, 0, "c8 10 00 01", "enterq $0x10, $0x1"},
+		{hde.Mode64, 0, "d1 e0", "shll %eax"},
+		{hde.Mode64, 0x1000, "e8 fb 0f 00 00", "callq 0x2000"},
+		{hde.Mode64, 0x1000, "eb fe", "jmp 0x1000"},
+		{hde.Mode64, 0x1000, "75 fe", "jne 0x1000"},
+		{hde.Mode64, 0, "ff e0", "jmpq *%rax"},
+		{hde.Mode64, 0, "ff 24 c5 00 10 00 00", "jmpq *0x1000(,%rax,8)"},
+		{hde.Mode64, 0, "c3", "retq"},
+		{hde.Mode64, 0, "f3 a4", "rep movsb %ds:(%rsi), %es:(%rdi)"},
+		{hde.Mode64, 0, "f2 ae", "repnz scasb %es:(%rdi), %al"},
+		{hde.Mode64, 0, "ec", "inb (%dx), %al"},
+		{hde.Mode64, 0, "0f b6 00", "movzbl (%rax), %eax"},
+		{hde.Mode64, 0, "48 63 c1", "movslq %ecx, %rax"},
+		{hde.Mode64, 0, "48 98", "cltq"},
+		{hde.Mode64, 0, "0f 94 c0", "sete %al"},
+		{hde.Mode64, 0, "f2 48 0f 2a 00", "cvtsi2sdq (%rax), %xmm0"},
+		{hde.Mode64, 0, "dd 18", "fstpl (%rax)"},
+		{hde.Mode64, 0, "df 28", "fildll (%rax)"},
+		{hde.Mode64, 0, "de e9", "fsubrp %st, %st(1)"},
+		{hde.Mode64, 0, "62 f1 74 d9 58 00", "vaddps (%rax){1to16}, %zmm1, %zmm0{%k1}{z}"},
+		{hde.Mode64, 0, "62 f1 74 18 58 c2", "vaddps {rn-sae}, %zmm2, %zmm1, %zmm0"},
+		{hde.Mode64, 0, "62 f3 7d 18 08 ca 01", "vrndscaleps $0x1, {sae}, %zmm2, %zmm1"},
+		{hde.Mode64, 0, "62 f3 7d 48 3f c2 00", "vpcmpeqb %zmm2, %zmm0, %k0"},
+		{hde.Mode64, 0, "62 f3 7d 48 3e c2 01", "vpcmpltub %zmm2, %zmm0, %k0"},
+		{hde.Mode64, 0, "62 f3 7d 48 3f c2 03", "vpcmpb $0x3, %zmm2, %zmm0, %k0"},
+		{hde.Mode64, 0, "62 f3 fd 48 1e 40 01 06", "vpcmpnleuq 0x40(%rax), %zmm0, %k0"},
+		{hde.Mode64, 0, "62 f3 7d 59 1f 00 04", "vpcmpneqd (%rax){1to16}, %zmm0, %k0{%k1}"},
+		{hde.Mode64, 0, "0f c2 c1 01", "cmpltps %xmm1, %xmm0"},
+		{hde.Mode64, 0, "f2 0f c2 c1 07", "cmpordsd %xmm1, %xmm0"},
+		{hde.Mode64, 0, "0f c2 c1 08", "cmpps $0x8, %xmm1, %xmm0"},
+		{hde.Mode64, 0, "c5 f0 c2 c2 08", "vcmpeq_uqps %xmm2, %xmm1, %xmm0"},
+		{hde.Mode64, 0, "62 f1 74 48 c2 c2 10", "vcmpeq_osps %zmm2, %zmm1, %k0"},
+		{hde.Mode64, 0, "62 f1 f7 18 c2 c2 20", "vcmpsd $0x20, {sae}, %xmm2, %xmm1, %k0"},
+		{hde.Mode32, 0, "ff 1f", "lcalll *(%edi)"},
+		{hde.Mode32, 0, "ea 78 56 34 12 00 10", "ljmpl $0x1000, $0x12345678"},
+		{hde.Mode32, 0, "e2 fe", "loopl 0x0"},
+		{hde.Mode16, 0, "26 8b 46 fe", "movw %es:-0x2(%bp), %ax"},
+		{hde.Mode64, 0, "0f 0f c1 0c", "(bad)"},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.FormatATT(tt.addr); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestFormatGo(t *testing.T) {
+	symbols := func(addr uint64) (string, uint64) {
+		switch {
+		case addr >= 0x2000 && addr < 0x2100:
+			return "runtime.morestack", 0x2000
+		case addr >= 0x5000 && addr < 0x5100:
+			return "main.table", 0x5000
+		}
+		return "", 0
+	}
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want string
+		sym  string
+	}{
+		{hde.Mode64, "48 8b 44 24 10", "MOVQ 0x10(SP), AX", ""},
+		{hde.Mode64, "e8 fb 0f 00 00", "CALL 0x2000", "CALL runtime.morestack(SB)"},
+		{hde.Mode64, "48 8d 05 f9 3f 00 00", "LEAQ 0x3ff9(IP), AX", "LEAQ main.table(SB), AX"},
+		{hde.Mode64, "48 8d 05 09 40 00 00", "LEAQ 0x4009(IP), AX", "LEAQ main.table+16(SB), AX"},
+		{hde.Mode64, "64 4c 8b 34 25 f8 ff ff ff", "MOVQ FS:0xfffffff8, R14", ""},
+		{hde.Mode64, "49 3b 66 10", "CMPQ SP, 0x10(R14)", ""},
+		{hde.Mode64, "f3 48 ab", "REP; STOSQ AX, ES:0(DI)", ""},
+		{hde.Mode64, "0f b6 c8", "MOVZX AL, CX", ""},
+		{hde.Mode64, "66 0f 1f 44 00 00", "NOPW 0(AX)(AX*1)", ""},
+		{hde.Mode64, "66 90", "NOPW", ""},
+		{hde.Mode64, "cc", "INT $0x3", ""},
+		{hde.Mode64, "50", "PUSHQ AX", ""},
+		{hde.Mode64, "6a 80", "PUSHL $-0x80", ""},
+		{hde.Mode64, "f2 0f 11 44 24 08", "MOVSD_XMM X0, 0x8(SP)", ""},
+		{hde.Mode64, "f0 48 0f b1 0a", "LOCK CMPXCHGQ CX, 0(DX)", ""},
+		{hde.Mode64, "3e ff e0", "DS JMP AX", ""},
+		{hde.Mode64, "62 f1 74 d9 58 00", "VADDPS.BCST.Z 0(AX), Z1, K1, Z0", ""},
+		{hde.Mode64, "62 f1 74 18 58 c2", "VADDPS.RN_SAE Z2, Z1, Z0", ""},
+		{hde.Mode32, "b8 00 50 00 00", "MOVL $0x5000, AX", "MOVL $main.table(SB), AX"},
+		{hde.Mode32, "8b 15 00 50 00 00", "MOVL 0x5000, DX", "MOVL main.table(SB), DX"},
+		{hde.Mode32, "ea 78 56 34 12 00 10", "LJMP $0x12345678, $0x1000", ""},
+		{hde.Mode16, "89 84 24 98", "MOVW AX, 0x9824(SI)", ""},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.FormatGo(0x1000, nil); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.code, got, tt.want)
+		}
+		want := tt.sym
+		if want == "" {
+			want = tt.want
+		}
+		if got := insn.FormatGo(0x1000, symbols); got != want {
+			t.Errorf("%s with symbols: got %q, want %q", tt.code, got, want)
+		}
+	}
+}
+
+func TestBranchTarget(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		pc   uint64
+		code string
+		want uint64
+		ok   bool
+	}{
+		{hde.Mode64, 0x1000, "eb fe", 0x1000, true},
+		{hde.Mode64, 0x1000, "75 10", 0x1012, true},
+		{hde.Mode64, 0x1000, "e8 fb 0f 00 00", 0x2000, true},
+		{hde.Mode64, 0x1000, "e9 00 f0 ff ff", 0x5, true},
+		{hde.Mode64, 0x1000, "0f 84 00 01 00 00", 0x1106, true},
+		{hde.Mode64, 0x1000, "e2 fe", 0x1000, true},
+		{hde.Mode64, 0x1000, "e3 02", 0x1004, true},
+		{hde.Mode64, 0xfffffffffffffff0, "eb 20", 0x12, true},
+		{hde.Mode64, 0x1000, "c7 f8 10 00 00 00", 0x1016, true},
+		{hde.Mode32, 0x1000, "66 c7 f8 10 00", 0x1015, true},
+		{hde.Mode64, 0x1000, "c6 f8 05", 0, false},
+		{hde.Mode32, 0xfffffff0, "e9 10 00 00 00", 0x5, true},
+		{hde.Mode32, 0x1000, "66 e9 00 f0", 0x4, true},
+		{hde.Mode16, 0xfff0, "e8 20 00", 0x13, true},
+		{hde.Mode16, 0x0010, "eb 80", 0xff92, true},
+		{hde.Mode64, 0x1000, "ff e0", 0, false},
+		{hde.Mode64, 0x1000, "c3", 0, false},
+		{hde.Mode32, 0x1000, "ea 78 56 34 12 00 10", 0, false},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got, ok := insn.BranchTarget(tt.pc); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %#x, %v, want %#x, %v", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMemTarget(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want uint64
+		ok   bool
+	}{
+		{hde.Mode64, "48 8b 05 34 12 00 00", 0x223b, true},
+		{hde.Mode64, "48 8d 05 f9 ef ff ff", 0x0, true},
+		{hde.Mode64, "ff 15 fa 0f 00 00", 0x2000, true},
+		{hde.Mode64, "67 8b 05 f5 ef ff ff", 0xfffffffc, true},
+		{hde.Mode64, "c5 fc 28 05 fc 0f 00 00", 0x2004, true},
+		{hde.Mode64, "8b 04 25 34 12 00 00", 0, false},
+		{hde.Mode64, "48 8b 00", 0, false},
+		{hde.Mode32, "8b 05 34 12 00 00", 0, false},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got, ok := insn.MemTarget(0x1000); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %#x, %v, want %#x, %v", tt.code, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestFlow(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want hde.FlowKind
+	}{
+		{hde.Mode64, "48 89 c8", hde.Fallthrough},
+		{hde.Mode64, "75 fe", hde.CondBranch},
+		{hde.Mode64, "0f 84 00 01 00 00", hde.CondBranch},
+		{hde.Mode64, "e2 fe", hde.CondBranch},
+		{hde.Mode64, "e3 fe", hde.CondBranch},
+		{hde.Mode64, "c7 f8 00 00 00 00", hde.CondBranch},
+		{hde.Mode64, "c6 f8 ff", hde.Fallthrough},
+		{hde.Mode64, "eb fe", hde.Jump},
+		{hde.Mode64, "e9 00 00 00 00", hde.Jump},
+		{hde.Mode64, "ff e0", hde.IndirectJump},
+		{hde.Mode64, "ff 25 00 00 00 00", hde.IndirectJump},
+		{hde.Mode64, "ff 28", hde.IndirectJump},
+		{hde.Mode64, "e8 00 00 00 00", hde.Call},
+		{hde.Mode64, "ff d0", hde.IndirectCall},
+		{hde.Mode64, "ff 15 00 00 00 00", hde.IndirectCall},
+		{hde.Mode64, "ff 18", hde.IndirectCall},
+		{hde.Mode64, "ff c0", hde.Fallthrough},
+		{hde.Mode64, "ff 30", hde.Fallthrough},
+		{hde.Mode64, "c3", hde.Return},
+		{hde.Mode64, "c2 08 00", hde.Return},
+		{hde.Mode64, "cb", hde.Return},
+		{hde.Mode64, "48 cf", hde.Return},
+		{hde.Mode64, "48 0f 07", hde.Return},
+		{hde.Mode64, "cd 80", hde.Interrupt},
+		{hde.Mode64, "0f 05", hde.Syscall},
+		{hde.Mode64, "f4", hde.Halt},
+		{hde.Mode64, "cc", hde.Trap},
+		{hde.Mode64, "0f 0b", hde.Trap},
+		{hde.Mode64, "0f b9 c1", hde.Trap},
+		{hde.Mode64, "0f ff c1", hde.Trap},
+		{hde.Mode32, "0f 0b", hde.Trap},
+		{hde.Mode32, "0f 05", hde.Syscall},
+		{hde.Mode32, "0f 34", hde.Syscall},
+		{hde.Mode32, "9a 78 56 34 12 00 10", hde.Call},
+		{hde.Mode32, "ea 78 56 34 12 00 10", hde.Jump},
+		{hde.Mode32, "ce", hde.Interrupt},
+		{hde.Mode32, "cf", hde.Return},
+		{hde.Mode16, "e3 fe", hde.CondBranch},
+		{hde.Mode16, "ff 1e 00 10", hde.IndirectCall},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if got := insn.Flow(); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.code, got, tt.want)
+		}
+		call := tt.want == hde.Call || tt.want == hde.IndirectCall
+		if insn.IsCALL() != call {
+			t.Errorf("%s: IsCALL() = %v", tt.code, insn.IsCALL())
+		}
+	}
+
+	// 9A is invalid in 64-bit mode, and neither a call nor a Call there
+	far := hde.Insn{Mode: hde.Mode64, Opcode: 0x9a, Length: 7}
+	if _, err := hde.Mode64.Decode(unhex(t, "9a 78 56 34 12 00 10")); err == nil || far.IsCALL() || far.Flow() != hde.Fallthrough {
+		t.Errorf("64-bit 9a: decode %v, IsCALL() = %v, Flow() = %v", err, far.IsCALL(), far.Flow())
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+	}{
+		{hde.Mode64, "48 89 c8"},
+		{hde.Mode64, "f3 66 2e 0f 1f 84 00 00 00 00 00"},
+		{hde.Mode64, "66 66 66 90"},
+		{hde.Mode64, "f0 41 81 44 24 08 78 56 34 12"},
+		{hde.Mode64, "48 b8 88 77 66 55 44 33 22 11"},
+		{hde.Mode64, "66 0f 3a 0f c1 08"},
+		{hde.Mode64, "c5 ec 58 cb"},
+		{hde.Mode64, "c4 01 2d fe 8c ac 78 56 34 12"},
+		{hde.Mode64, "62 f1 7c 48 58 40 01"},
+		{hde.Mode64, "c8 10 00 01"},
+		{hde.Mode64, "e8 00 00 00 00"},
+		{hde.Mode32, "ea 78 56 34 12 08 00"},
+		{hde.Mode32, "66 ea 34 12 08 00"},
+		{hde.Mode16, "8b 86 34 12"},
+	}
+	for _, tt := range tests {
+		code := unhex(t, tt.code)
+		insn, err := tt.mode.Decode(code)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		got, err := tt.mode.Encode(&insn)
+		if err != nil || !bytes.Equal(got, code) {
+			t.Errorf("%s: got % x, %v", tt.code, got, err)
+		}
+	}
+}
+
+func TestEncodeEdit(t *testing.T) {
+	insn, err := hde.Mode64.Decode(unhex(t, "48 8b 44 24 08"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	insn.Disp.Value = uint64(0xfffffffffffffff0)
+	if got, err := hde.Mode64.Encode(&insn); err != nil || !bytes.Equal(got, unhex(t, "48 8b 44 24 f0")) {
+		t.Errorf("negative disp8: got % x, %v", got, err)
+	}
+	insn.Disp.Value = 0x100
+	if _, err := hde.Mode64.Encode(&insn); err != hde.ErrLiteralRange {
+		t.Errorf("disp8 out of range: got %v", err)
+	}
+
+	// Widening the displacement needs a ModR/M that selects disp32
+	insn.Disp = hde.Literal{Value: 0x1000, Bits: 32}
+	if _, err := hde.Mode64.Encode(&insn); err != hde.ErrEncoding {
+		t.Errorf("disp32 with mod=1: got %v", err)
+	}
+	insn.ModRM = insn.ModRM&0x3f | 0x80
+	insn.Flags = insn.Flags&^hde.HasDisp8 | hde.HasDisp32
+	if got, err := hde.Mode64.Encode(&insn); err != nil || !bytes.Equal(got, unhex(t, "48 8b 84 24 00 10 00 00")) {
+		t.Errorf("disp32: got % x, %v", got, err)
+	}
+
+	// Instructions built without recorded prefixes take them from the flags
+	mov := hde.Insn{
+		Flags:  hde.IsModRM | hde.IsSIB | hde.HasDisp32 | hde.HasSegGS | hde.HasOpSize,
+		Opcode: 0x8b,
+		ModRM:  0x04,
+		SIB:    0x25,
+		Disp:   hde.Literal{Value: 0x28, Bits: 32},
+	}
+	if got, err := hde.Mode64.Encode(&mov); err != nil || !bytes.Equal(got, unhex(t, "65 66 8b 04 25 28 00 00 00")) {
+		t.Errorf("from flags: got % x, %v", got, err)
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	type offsets struct{ prefixes, opcode, modrm, sib, disp, imm uint8 }
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		want offsets
+	}{
+		{hde.Mode64, "90", offsets{0, 0, 0, 0, 0, 0}},
+		{hde.Mode64, "f0 41 81 44 24 08 78 56 34 12", offsets{1, 2, 3, 4, 5, 6}},
+		{hde.Mode64, "66 2e 0f 1f 84 00 00 00 00 00", offsets{2, 2, 4, 5, 6, 0}},
+		{hde.Mode64, "48 c7 05 10 00 00 00 01 00 00 00", offsets{0, 1, 2, 0, 3, 7}},
+		{hde.Mode64, "66 0f 3a 0f c1 08", offsets{1, 1, 4, 0, 0, 5}},
+		{hde.Mode64, "c4 e3 6d 02 cb 05", offsets{0, 3, 4, 0, 0, 5}},
+		{hde.Mode64, "62 f1 7c 48 58 40 01", offsets{0, 4, 5, 0, 6, 0}},
+		{hde.Mode64, "e8 00 00 00 00", offsets{0, 0, 0, 0, 0, 1}},
+		{hde.Mode64, "c8 10 00 01", offsets{0, 0, 0, 0, 3, 1}},
+		{hde.Mode32, "ea 78 56 34 12 08 00", offsets{0, 0, 0, 0, 5, 1}},
+		{hde.Mode16, "26 8b 86 34 12", offsets{1, 1, 2, 0, 3, 0}},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		got := offsets{insn.NumPrefixes, insn.OpcodeOffset, insn.ModRMOffset, insn.SIBOffset, insn.DispOffset, insn.ImmOffset}
+		if got != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.code, got, tt.want)
+		}
+	}
+
+	code := unhex(t, "f0 41 81 44 24 08 78 56 34 12")
+	if n := testing.AllocsPerRun(100, func() { hde.Mode64.Decode(code) }); n != 0 {
+		t.Errorf("Decode allocates %v times", n)
+	}
+}
+
+func TestRelocate(t *testing.T) {
+	tests := []struct {
+		mode         *hde.Mode
+		code         string
+		oldPC, newPC uint64
+		want         string
+		err          error
+	}{
+		{hde.Mode64, "90", 0x1000, 0x2000, "90", nil},
+		{hde.Mode64, "eb 10", 0x1000, 0x1010, "eb 00", nil},
+		{hde.Mode64, "eb 10", 0x1000, 0x2000, "e9 0d f0 ff ff", nil},
+		{hde.Mode64, "f2 eb 10", 0x1000, 0x2000, "f2 e9 0d f0 ff ff", nil},
+		{hde.Mode64, "74 10", 0x1000, 0x2000, "0f 84 0c f0 ff ff", nil},
+		{hde.Mode64, "0f 84 00 01 00 00", 0x1000, 0x1100, "0f 84 00 00 00 00", nil},
+		{hde.Mode64, "48 8b 05 00 10 00 00", 0x1000, 0x2000, "48 8b 05 00 00 00 00", nil},
+		{hde.Mode64, "e8 00 00 00 00", 0x1000, 0x100000000, "", hde.ErrOutOfRange},
+		{hde.Mode64, "48 8b 05 00 00 00 00", 0x1000, 0x100000000, "", hde.ErrOutOfRange},
+		{hde.Mode64, "e2 10", 0x1000, 0x2000, "", hde.ErrOutOfRange},
+		{hde.Mode32, "e8 00 00 00 00", 0x1000, 0xfffff000, "e8 00 20 00 00", nil},
+		{hde.Mode16, "eb 10", 0x100, 0x1000, "e9 0f f1", nil},
+	}
+	for _, tt := range tests {
+		insn, err := tt.mode.Decode(unhex(t, tt.code))
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		got, err := insn.Relocate(tt.oldPC, tt.newPC)
+		if err != tt.err || !bytes.Equal(got, unhex(t, tt.want)) {
+			t.Errorf("%s: got % x, %v, want %s, %v", tt.code, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestInstructions(t *testing.T) {
+	// nop; mov rax, rcx; ret; truncated two-byte opcode
+	code := unhex(t, "90 48 89 c8 c3 0f")
+	type step struct {
+		addr  uint64
+		bytes string
+		err   bool
+	}
+	collect := func(seq func(func(hde.Inst, error) bool)) []step {
+		var got []step
+		for inst, err := range seq {
+			got = append(got, step{inst.Addr, fmt.Sprintf("% x", inst.Bytes), err != nil})
+		}
+		return got
+	}
+
+	got := collect(hde.Mode64.Instructions(code, 0x1000))
+	want := []step{
+		{0x1000, "90", false},
+		{0x1001, "48 89 c8", false},
+		{0x1004, "c3", false},
+		{0x1005, "0f", true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Instructions: got %v, want %v", got, want)
+	}
+
+	// 06 (PUSH ES) is invalid in 64-bit mode
+	code = unhex(t, "90 06 06 90 c3")
+	got = collect(hde.Mode64.Instructions(code, 0))
+	want = []step{{0, "90", false}, {1, "06", true}, {2, "06", true}, {3, "90", false}, {4, "c3", false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Instructions: got %v, want %v", got, want)
+	}
+	got = collect(hde.Mode64.Sweep(code, 0, hde.StopOnError))
+	want = []step{{0, "90", false}, {1, "06 06 90 c3", true}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("StopOnError: got %v, want %v", got, want)
+	}
+	skip2 := func(uint64, []byte, error) int { return 2 }
+	got = collect(hde.Mode64.Sweep(code, 0, skip2))
+	want = []step{{0, "90", false}, {1, "06 06", true}, {3, "90", false}, {4, "c3", false}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("skip 2: got %v, want %v", got, want)
+	}
+
+	n := 0
+	for range hde.Mode64.Instructions(code, 0) {
+		if n++; n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Errorf("break: iterated %d times", n)
+	}
+}
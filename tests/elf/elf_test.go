@@ -0,0 +1,223 @@
+package elf_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	hdeelf "github.com/can1357/go-hde/elf"
+)
+
+func TestSymbols(t *testing.T) {
+	img, err := hdeelf.Open("testdata/prog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+	if img.Mode.Bits() != 64 {
+		t.Errorf("got %d-bit", img.Mode.Bits())
+	}
+	var names []string
+	for _, s := range img.Sections {
+		names = append(names, s.Name)
+	}
+	if want := []string{".init", ".plt", ".plt.got", ".text", ".fini"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("sections %v, want %v", names, want)
+	}
+
+	syms, err := img.Symbols()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sized := map[string]hdeelf.Symbol{}
+	for _, s := range syms {
+		if s.Size != 0 {
+			sized[s.Name] = s
+		}
+	}
+	want := map[string]hdeelf.Symbol{
+		"_start": {Name: "_start", Addr: 0x1040, Size: 34},
+		"add":    {Name: "add", Addr: 0x1129, Size: 4},
+		"loop":   {Name: "loop", Addr: 0x112d, Size: 54},
+		"main":   {Name: "main", Addr: 0x1163, Size: 6},
+	}
+	if !reflect.DeepEqual(sized, want) {
+		t.Errorf("got %+v, want %+v", sized, want)
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	tests := []struct {
+		file  string
+		bits  int
+		name  string
+		insts []uint64
+		err   uint64 // Address of an instruction past the end of the function, or 0
+	}{
+		{"testdata/prog", 64, "add", []uint64{0x1129, 0x112c}, 0},
+		{"testdata/prog", 64, "main", []uint64{0x1163, 0x1168}, 0},
+		{"testdata/start32", 32, "_start", []uint64{0x8049000, 0x8049005, 0x804900a, 0x804900c}, 0},
+		{"testdata/start32", 32, "short", []uint64{0x804900e}, 0x804900e},
+	}
+	for _, tt := range tests {
+		img, err := hdeelf.Open(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer img.Close()
+		if img.Mode.Bits() != tt.bits {
+			t.Errorf("%s: got %d-bit", tt.file, img.Mode.Bits())
+		}
+		fns, err := img.Functions()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var fn *hdeelf.Function
+		for _, f := range fns {
+			if f.Name == tt.name {
+				fn = f
+			}
+		}
+		if fn == nil {
+			t.Errorf("%s: no function %s", tt.file, tt.name)
+			continue
+		}
+		var addrs []uint64
+		for _, inst := range fn.Insts {
+			addrs = append(addrs, inst.Addr)
+		}
+		if !reflect.DeepEqual(addrs, tt.insts) {
+			t.Errorf("%s: got instructions at %#x, want %#x", tt.name, addrs, tt.insts)
+		}
+		var ie *hdeelf.InstError
+		switch {
+		case tt.err == 0 && len(fn.Errors) != 0:
+			t.Errorf("%s: unexpected errors %v", tt.name, fn.Errors)
+		case tt.err != 0 && (len(fn.Errors) != 1 || !errors.As(fn.Errors[0], &ie) || ie.Addr != tt.err || !errors.Is(ie, hdeelf.ErrOverrun)):
+			t.Errorf("%s: got errors %v, want an overrun at %#x", tt.name, fn.Errors, tt.err)
+		}
+	}
+}
+
+func TestDecodeFunction(t *testing.T) {
+	img, err := hdeelf.Open("testdata/prog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+
+	// Functions without a size run to the given end
+	fn := img.DecodeFunction(hdeelf.Symbol{Name: "loop", Addr: 0x112d}, 0x1163)
+	if len(fn.Insts) != 22 || len(fn.Errors) != 0 {
+		t.Errorf("got %d instructions, errors %v", len(fn.Insts), fn.Errors)
+	}
+	fn = img.DecodeFunction(hdeelf.Symbol{Name: "data", Addr: 0x4000}, 0x4010)
+	if len(fn.Insts) != 0 || len(fn.Errors) != 1 || !errors.Is(fn.Errors[0], hdeelf.ErrUnmapped) {
+		t.Errorf("got %d instructions, errors %v", len(fn.Insts), fn.Errors)
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	img, err := hdeelf.Open("testdata/prog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+	g, err := img.Disassemble()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range []uint64{0x1040, 0x1129, 0x112d, 0x1142, 0x115c, 0x1163} {
+		if b := g.Blocks[addr]; b == nil || b.Err != nil {
+			t.Errorf("block %#x: %+v", addr, b)
+		}
+	}
+}
+
+func TestFDEs(t *testing.T) {
+	img, err := hdeelf.Open("testdata/prog.stripped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+	if syms, err := img.Symbols(); err != nil || len(syms) != 0 {
+		t.Fatalf("got symbols %v, %v", syms, err)
+	}
+	fdes, err := img.FDEs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ranges [][2]uint64
+	for _, fde := range fdes {
+		ranges = append(ranges, [2]uint64{fde.Start, fde.End})
+	}
+	want := [][2]uint64{{0x1040, 0x1062}, {0x1020, 0x1030}, {0x1030, 0x1038}, {0x1129, 0x112d}, {0x112d, 0x1163}, {0x1163, 0x1169}}
+	if !reflect.DeepEqual(ranges, want) {
+		t.Errorf("got ranges %#x, want %#x", ranges, want)
+	}
+
+	// loop pushes three registers, and restores the remembered state for its
+	// early exit after the epilogue
+	rules := []hdeelf.CFARule{
+		{Addr: 0x112d, Reg: hde.RSP, Offset: 8},
+		{Addr: 0x112f, Reg: hde.RSP, Offset: 16},
+		{Addr: 0x1130, Reg: hde.RSP, Offset: 24},
+		{Addr: 0x1131, Reg: hde.RSP, Offset: 32},
+		{Addr: 0x1158, Reg: hde.RSP, Offset: 24},
+		{Addr: 0x1159, Reg: hde.RSP, Offset: 16},
+		{Addr: 0x115b, Reg: hde.RSP, Offset: 8},
+		{Addr: 0x115c, Reg: hde.RSP, Offset: 32},
+	}
+	fde, err := img.FindFDE(0x1150)
+	if err != nil || fde == nil || !reflect.DeepEqual(fde.CFA, rules) {
+		t.Errorf("FindFDE: got %+v, %v", fde, err)
+	}
+	for _, addr := range []uint64{0x1000, 0x1169, 0x2000} {
+		if fde, err := img.FindFDE(addr); fde != nil || err != nil {
+			t.Errorf("FindFDE(%#x): got %+v, %v", addr, fde, err)
+		}
+	}
+
+	// Concurrent lookups share the CIEs of one parse of .eh_frame
+	fresh, err := hdeelf.Open("testdata/prog.stripped")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fresh.Close()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if fde, err := fresh.FindFDE(0x1150); err != nil || fde == nil || !reflect.DeepEqual(fde.CFA, rules) {
+				t.Errorf("concurrent FindFDE: got %+v, %v", fde, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	fns, err := img.FrameFunctions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fn := range fns {
+		if len(fn.Insts) == 0 || len(fn.Errors) != 0 {
+			t.Errorf("%#x: got %d instructions, errors %v", fn.Addr, len(fn.Insts), fn.Errors)
+		}
+	}
+	if fn := fns[4]; fn.Name != "" || len(fn.Insts) != 22 {
+		t.Errorf("got %q with %d instructions", fn.Name, len(fn.Insts))
+	}
+
+	// Without unwind tables there are no FDEs
+	img32, err := hdeelf.Open("testdata/start32")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img32.Close()
+	if fdes, err := img32.FDEs(); fdes != nil || err != nil {
+		t.Errorf("start32: got %v, %v", fdes, err)
+	}
+}
@@ -0,0 +1,14 @@
+int add(int a, int b) { return a + b; }
+
+static int loop(int n)
+{
+	int s = 0;
+	for (int i = 0; i < n; i++)
+		s += add(i, s);
+	return s;
+}
+
+int main(int argc, char **argv)
+{
+	return loop(argc);
+}
@@ -0,0 +1,16 @@
+	.text
+	.globl	_start
+	.type	_start, @function
+_start:
+	call	short
+	mov	$1, %eax
+	xor	%ebx, %ebx
+	int	$0x80
+	.size	_start, .-_start
+
+	# The recorded size cuts the mov in half
+	.type	short, @function
+short:
+	mov	$0x12345678, %eax
+	ret
+	.size	short, 3
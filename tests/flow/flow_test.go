@@ -0,0 +1,170 @@
+package flow_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/flow"
+)
+
+// unhex decodes a space separated hex string
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// function is a small function with a loop, a call and data after its return
+const function = "" +
+	"55" + // 1000: push rbp
+	"48 85 ff" + // 1001: test rdi, rdi
+	"74 07" + // 1004: je 0x100d
+	"e8 0a 00 00 00" + // 1006: call 0x1015
+	"eb f4" + // 100b: jmp 0x1001
+	"5d" + // 100d: pop rbp
+	"c3" + // 100e: ret
+	"ff ff ff ff ff ff" + // 100f: data
+	"31 c0" + // 1015: xor eax, eax
+	"c3" // 1017: ret
+
+// edge returns an edge between two blocks
+func edge(from, to uint64, kind flow.EdgeKind) flow.Edge {
+	return flow.Edge{From: from, To: to, Kind: kind}
+}
+
+func TestDisassemble(t *testing.T) {
+	g, err := flow.Disassemble(hde.Mode64, unhex(t, function), 0x1000, 0x1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	type block struct {
+		start, end uint64
+		insts      int
+		succs      []flow.Edge
+	}
+	want := []block{
+		{0x1000, 0x1001, 1, []flow.Edge{edge(0x1000, 0x1001, flow.Fallthrough)}},
+		{0x1001, 0x1006, 2, []flow.Edge{edge(0x1001, 0x100d, flow.Taken), edge(0x1001, 0x1006, flow.NotTaken)}},
+		{0x1006, 0x100b, 1, []flow.Edge{edge(0x1006, 0x1015, flow.Call), edge(0x1006, 0x100b, flow.Fallthrough)}},
+		{0x100b, 0x100d, 1, []flow.Edge{edge(0x100b, 0x1001, flow.Taken)}},
+		{0x100d, 0x100f, 2, nil},
+		{0x1015, 0x1018, 2, nil},
+	}
+	var got []block
+	for _, b := range g.Sorted() {
+		if b.Err != nil {
+			t.Errorf("block %#x: %v", b.Start, b.Err)
+		}
+		got = append(got, block{b.Start, b.End, len(b.Insts), b.Succs})
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+	if want := []uint64{0x1000, 0x1015}; !reflect.DeepEqual(g.Entries, want) {
+		t.Errorf("entries: got %#x, want %#x", g.Entries, want)
+	}
+	preds := []flow.Edge{edge(0x1000, 0x1001, flow.Fallthrough), edge(0x100b, 0x1001, flow.Taken)}
+	if got := g.Blocks[0x1001].Preds; !reflect.DeepEqual(got, preds) {
+		t.Errorf("preds: got %+v, want %+v", got, preds)
+	}
+}
+
+func TestDisassembleOverlap(t *testing.T) {
+	// jmp into its own displacement, which decodes as inc eax; ret
+	g, err := flow.Disassemble(hde.Mode64, unhex(t, "eb ff c0 c3"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := g.Sorted()
+	if len(blocks) != 2 || blocks[0].End != 2 || blocks[1].Start != 1 || blocks[1].End != 4 || len(blocks[1].Insts) != 2 {
+		t.Fatalf("got %+v", blocks)
+	}
+}
+
+func TestDisassembleErrors(t *testing.T) {
+	if _, err := flow.Disassemble(hde.Mode64, unhex(t, "c3"), 0x1000, 0x2000); !errors.Is(err, flow.ErrOutside) {
+		t.Errorf("entry outside: got %v", err)
+	}
+	// nop; (bad) in 64-bit mode
+	g, err := flow.Disassemble(hde.Mode64, unhex(t, "90 06"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := g.Blocks[0]; len(b.Insts) != 1 || !errors.Is(b.Err, hde.ErrUnknownOpcode) {
+		t.Errorf("bad opcode: got %+v", b)
+	}
+	// je over a ud2, which ends its block without an error
+	g, err = flow.Disassemble(hde.Mode64, unhex(t, "74 02 0f 0b c3"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := g.Blocks[2]; b == nil || b.End != 4 || len(b.Succs) != 0 || b.Err != nil {
+		t.Errorf("ud2: got %+v", b)
+	}
+	// nop running off the end of the code
+	g, _ = flow.Disassemble(hde.Mode64, unhex(t, "90"), 0, 0)
+	if b := g.Blocks[0]; !errors.Is(b.Err, flow.ErrOutside) {
+		t.Errorf("end of code: got %v", b.Err)
+	}
+}
+
+// branchy is je over a ret to a call out of the code
+const branchy = "74 01 c3 e8 f8 0f 00 00 c3"
+
+func TestWriteDOT(t *testing.T) {
+	g, err := flow.Disassemble(hde.Mode64, unhex(t, branchy), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := g.WriteDOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph cfg {
+	node [shape=box, fontname="monospace"];
+	"0x0" [label="0x0:\l0x0  je 0x3\l"];
+	"0x2" [label="0x2:\l0x2  ret\l"];
+	"0x3" [label="0x3:\l0x3  call 0x1000\l"];
+	"0x8" [label="0x8:\l0x8  ret\l"];
+	"0x1000" [shape=ellipse, style=dashed];
+	"0x0" -> "0x3" [label="taken", color=darkgreen];
+	"0x0" -> "0x2" [label="not-taken", color=red];
+	"0x3" -> "0x1000" [label="call", color=gray];
+	"0x3" -> "0x8" [label="fallthrough", color=blue];
+}
+`
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	g, err := flow.Disassemble(hde.Mode64, unhex(t, branchy), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(g)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"bits":64,"entries":["0x0"],"blocks":[` +
+		`{"start":"0x0","end":"0x2","instructions":[{"addr":"0x0","bytes":"7401","text":"je 0x3"}],` +
+		`"successors":[{"block":"0x3","kind":"taken"},{"block":"0x2","kind":"not-taken"}],"predecessors":[]},` +
+		`{"start":"0x2","end":"0x3","instructions":[{"addr":"0x2","bytes":"c3","text":"ret"}],` +
+		`"successors":[],"predecessors":[{"block":"0x0","kind":"not-taken"}]},` +
+		`{"start":"0x3","end":"0x8","instructions":[{"addr":"0x3","bytes":"e8f80f0000","text":"call 0x1000"}],` +
+		`"successors":[{"block":"0x1000","kind":"call"},{"block":"0x8","kind":"fallthrough"}],"predecessors":[{"block":"0x0","kind":"taken"}]},` +
+		`{"start":"0x8","end":"0x9","instructions":[{"addr":"0x8","bytes":"c3","text":"ret"}],` +
+		`"successors":[],"predecessors":[{"block":"0x3","kind":"fallthrough"}]}]}`
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
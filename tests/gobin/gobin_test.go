@@ -0,0 +1,92 @@
+package gobin_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/gobin"
+)
+
+// build builds testdata/handler stripped of its symbol tables for the platform
+func build(t *testing.T, goos string) string {
+	t.Helper()
+	gotool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	out := filepath.Join(t.TempDir(), "handler")
+	cmd := exec.Command(gotool, "build", "-trimpath", "-ldflags=-s -w", "-o", out, "./testdata/handler")
+	cmd.Env = append(os.Environ(), "GOOS="+goos, "GOARCH=amd64", "CGO_ENABLED=0")
+	if msg, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build: %v\n%s", err, msg)
+	}
+	return out
+}
+
+func TestBinary(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds Go binaries")
+	}
+	for _, goos := range []string{"linux", "windows", "darwin"} {
+		b, err := gobin.Open(build(t, goos))
+		if err != nil {
+			t.Fatalf("%s: %v", goos, err)
+		}
+		defer b.Close()
+		if b.Mode != hde.Mode64 {
+			t.Errorf("%s: got %d-bit", goos, b.Mode.Bits())
+		}
+
+		fn := b.Table.LookupFunc("main.handler")
+		if fn == nil {
+			t.Fatalf("%s: no main.handler", goos)
+		}
+		if got := b.Location(fn.Entry); !strings.HasPrefix(got, "main.handler (main.go:") {
+			t.Errorf("%s: got %q", goos, got)
+		}
+		n := 0
+		for inst, err := range b.Instructions(fn) {
+			// The padding after the function has no source line
+			padding := inst.Line == 0 && inst.Insn.Mnemonic() == hde.INT3
+			if err != nil || inst.Func != fn || !padding && (inst.Line < 6 || inst.Line > 12) {
+				t.Errorf("%s: %s: %v", goos, inst.Location(), err)
+			}
+			if loc := inst.Location(); n != 0 && !strings.HasPrefix(loc, "main.handler+0x") {
+				t.Errorf("%s: got %q", goos, loc)
+			}
+			n++
+		}
+		if n == 0 {
+			t.Errorf("%s: no instructions", goos)
+		}
+
+		// main.main calls main.handler, named through the Symbolizer
+		main := b.Table.LookupFunc("main.main")
+		found := false
+		for inst, err := range b.Instructions(main) {
+			if err != nil {
+				t.Fatalf("%s: %s: %v", goos, inst.Location(), err)
+			}
+			if target, ok := inst.Insn.BranchTarget(inst.Addr); ok && target == fn.Entry {
+				found = inst.Line == 15 && inst.Insn.FormatGo(inst.Addr, b.Symbolize) == "CALL main.handler(SB)"
+			}
+		}
+		if !found {
+			t.Errorf("%s: no call to main.handler on line 15", goos)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := gobin.Open("gobin_test.go"); !errors.Is(err, gobin.ErrFormat) {
+		t.Errorf("source file: got %v", err)
+	}
+	if _, err := gobin.Open("../hde64/winrar-x64-710.exe"); !errors.Is(err, gobin.ErrNoPCLN) {
+		t.Errorf("C++ binary: got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "os"
+
+//go:noinline
+func handler(n int) int {
+	s := 0
+	for i := range n {
+		s += i * i
+	}
+	return s
+}
+
+func main() {
+	os.Exit(handler(len(os.Args)))
+}
@@ -2,6 +2,7 @@ package cgohde64_test
 
 import (
 	_ "embed"
+	"encoding/binary"
 	"fmt"
 	"testing"
 
@@ -52,6 +53,12 @@ func TestHde64(t *testing.T) {
 		numOk++
 		flg := cgohde64.CgoFlags(&insn)
 		if flg&hdeutil.F_ERROR != 0 {
+			// Check the length of the instructions HDE rejects but the decoder
+			// accepts, where HDE still reads them whole.
+			if dec, err := hde.Mode64.Decode(winrar[i:]); err == nil && hdeutil.SizedOnError(&dec) &&
+				int(dec.Len()) != cgohde64.CgoLen(&insn) {
+				t.Fatalf("% x: length %d, HDE %d", winrar[i:i+int(dec.Len())], dec.Len(), cgohde64.CgoLen(&insn))
+			}
 			i++
 			continue
 		}
@@ -61,6 +68,52 @@ func TestHde64(t *testing.T) {
 			t.Fatal(err)
 		}
 
+		// HDE sizes the displacement of 67h-prefixed ModR/M operands as in
+		// 16-bit addressing, rather than the 32-bit addressing of 64-bit mode.
+		// Only the forms with a 16-bit displacement, or [rip+disp32] which has
+		// none in 16-bit addressing, differ.
+		if dec.Flags&(hde.HasAddrSize|hde.IsModRM) == hde.HasAddrSize|hde.IsModRM &&
+			(flg&hdeutil.F_DISP16 != 0 || dec.ModRM.Mod() == 0 && dec.ModRM.RM() == 5) {
+			i += int(dec.Len())
+			continue
+		}
+
+		// HDE reads an 8-byte memory offset for A0-A3 even when 67h makes it
+		// 4 bytes.
+		if dec.Flags&hde.HasAddrSize != 0 && dec.Opcode&0xfc == 0xa0 {
+			i += int(dec.Len())
+			continue
+		}
+
+		// HDE applies the TEST immediate of F6/F7 /0 and /1 to the two-byte
+		// opcodes 0F F6/F7 (PSADBW, MASKMOVQ) as well.
+		if dec.Flags&(hde.HasVEX|hde.HasEVEX) == 0 && dec.Opcode == 0x0f && dec.Opcode2&0xfe == 0xf6 &&
+			dec.ModRM.Reg() <= 1 && flg&(hdeutil.F_IMM8|hdeutil.F_IMM16|hdeutil.F_IMM32) != 0 {
+			i += int(dec.Len())
+			continue
+		}
+
+		// HDE decodes F3 0F B8 (POPCNT) as 0F B8 (JMPE), without its ModR/M
+		// byte.
+		if dec.Opcode == 0x0f && dec.Opcode2 == 0xb8 && dec.Flags&hde.HasRep != 0 {
+			i += int(dec.Len())
+			continue
+		}
+
+		// HDE reads both ENTER immediates into Imm, so the nesting level
+		// overwrites the low byte of the frame size. Operands needs both, so the
+		// decoder reads the level into Disp: check both fields against the
+		// encoding, then fold them into the layout of HDE.
+		if dec.Opcode == 0xc8 {
+			code := winrar[i:]
+			if dec.Imm != (hde.Literal{Value: uint64(binary.LittleEndian.Uint16(code[dec.ImmOffset:])), Bits: 16}) ||
+				dec.Disp != (hde.Literal{Value: uint64(code[dec.DispOffset]), Bits: 8}) {
+				t.Fatalf("enter at %#x: imm %+v, disp %+v", i, dec.Imm, dec.Disp)
+			}
+			dec.Imm.Value = dec.Imm.Value&^0xff | dec.Disp.Value
+			dec.Disp = hde.Literal{}
+		}
+
 		insn2 := cgohde64.GoToCGo(&dec, &insn)
 		if insn != insn2 {
 			fmt.Printf("%s\n", dec.Flags.String())
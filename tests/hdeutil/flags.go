@@ -109,3 +109,21 @@ func ToCgoFlags(f hde.Flag) (fl uint32, seg byte) {
 	}
 	return
 }
+
+// SizedOnError reports whether the instruction is one HDE flags with
+// F_ERROR_OPCODE but still sizes as the decoder does: UD2, TZCNT, LZCNT and the
+// 0F 01 register forms SERIALIZE, RDPKRU and WRPKRU.
+func SizedOnError(insn *hde.Insn) bool {
+	if insn.Opcode != 0x0f || insn.Flags&(hde.HasVEX|hde.HasEVEX) != 0 {
+		return false
+	}
+	switch insn.Opcode2 {
+	case 0x0b:
+		return true
+	case 0xbc, 0xbd:
+		return insn.Flags&hde.HasRep != 0
+	case 0x01:
+		return insn.ModRM == 0xe8 || insn.ModRM == 0xee || insn.ModRM == 0xef
+	}
+	return false
+}
@@ -0,0 +1,16 @@
+package hdeutil
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Put writes values in the byte order into b at off, as binary.Write encodes them.
+// The tests use it to lay out headers and code of synthetic binaries.
+func Put(b []byte, off int, order binary.ByteOrder, vals ...any) {
+	var w bytes.Buffer
+	for _, v := range vals {
+		binary.Write(&w, order, v)
+	}
+	copy(b[off:], w.Bytes())
+}
@@ -0,0 +1,102 @@
+package hook_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	"github.com/can1357/go-hde/hook"
+)
+
+// unhex decodes a space separated hex string
+func unhex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		mode     *hde.Mode
+		code     string
+		addr, at uint64
+		n        int
+		stolen   int
+		want     string
+	}{
+		// mov [rsp+8], rbx; push rdi
+		{hde.Mode64, "48 89 5c 24 08 57", 0x1000, 0x2000, 5, 5, "48 89 5c 24 08 e9 fb ef ff ff"},
+		// mov rax, [rip+0x1000]
+		{hde.Mode64, "48 8b 05 00 10 00 00", 0x1000, 0x5000, 5, 7, "48 8b 05 00 d0 ff ff e9 fb bf ff ff"},
+		// je +0x10; nop; nop; nop
+		{hde.Mode64, "74 10 90 90 90", 0x1000, 0x2000, 5, 5, "0f 84 0c f0 ff ff 90 90 90 e9 f7 ef ff ff"},
+		// call +0; jmp out of rel32 range of the trampoline
+		{hde.Mode64, "e8 00 00 00 00", 0x1000, 0x7fff00000000, 5, 5,
+			"ff 15 02 00 00 00 eb 08 05 10 00 00 00 00 00 00 ff 25 00 00 00 00 05 10 00 00 00 00 00 00"},
+		// jne +0x10 out of range
+		{hde.Mode64, "75 10", 0x1000, 0x7fff00000000, 2, 2,
+			"74 0e ff 25 00 00 00 00 12 10 00 00 00 00 00 00 ff 25 00 00 00 00 02 10 00 00 00 00 00 00"},
+		// jrcxz +0x10
+		{hde.Mode64, "e3 10", 0x1000, 0x2000, 2, 2, "e3 02 eb 05 e9 09 f0 ff ff e9 f4 ef ff ff"},
+		// xbegin +0x10
+		{hde.Mode64, "c7 f8 10 00 00 00", 0x1000, 0x2000, 5, 6, "c7 f8 10 f0 ff ff e9 fb ef ff ff"},
+		// xbegin +0x10 out of range
+		{hde.Mode64, "c7 f8 10 00 00 00", 0x1000, 0x7fff00000000, 5, 6,
+			"c7 f8 02 00 00 00 eb 0e ff 25 00 00 00 00 16 10 00 00 00 00 00 00 ff 25 00 00 00 00 06 10 00 00 00 00 00 00"},
+		// call wraps around in 32-bit code
+		{hde.Mode32, "e8 00 00 00 00", 0x1000, 0xfffff000, 5, 5, "e8 00 20 00 00 e9 fb 1f 00 00"},
+	}
+	for _, tt := range tests {
+		tr, err := hook.Build(tt.mode, unhex(t, tt.code), tt.addr, tt.n, tt.at)
+		if err != nil {
+			t.Errorf("%s: %v", tt.code, err)
+			continue
+		}
+		if want := unhex(t, tt.want); !bytes.Equal(tr.Code, want) || tr.Stolen != tt.stolen || tr.Addr != tt.at {
+			t.Errorf("%s: got % x (%d stolen), want % x (%d stolen)", tt.code, tr.Code, tr.Stolen, want, tt.stolen)
+		}
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []struct {
+		mode *hde.Mode
+		code string
+		n    int
+		err  error
+	}{
+		{hde.Mode64, "c3 cc cc cc cc", 5, hook.ErrTooShort},
+		{hde.Mode64, "55 48 89", 5, hook.ErrTooShort},
+		{hde.Mode64, "eb fe", 2, hook.ErrUnrelocatable},
+		{hde.Mode64, "74 01 90 90 90", 5, hook.ErrUnrelocatable},
+		{hde.Mode64, "48 8b 05 f9 ff ff ff", 5, hook.ErrUnrelocatable},
+		{hde.Mode64, "48 8b 05 00 00 00 80", 5, hook.ErrUnrelocatable},
+		{hde.Mode16, "55 89 e5", 3, hook.ErrMode},
+	}
+	for _, tt := range tests {
+		if _, err := hook.Build(tt.mode, unhex(t, tt.code), 0x1000, tt.n, 0x2000); !errors.Is(err, tt.err) {
+			t.Errorf("%s: got %v, want %v", tt.code, err, tt.err)
+		}
+	}
+}
+
+func TestDetour(t *testing.T) {
+	// push rbp; mov rbp, rsp; sub rsp, 0x20
+	code := unhex(t, "55 48 89 e5 48 83 ec 20")
+	tr, patch, err := hook.Detour(hde.Mode64, code, 0x1000, 0x3000, 0x2000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := unhex(t, "e9 fb 1f 00 00 cc cc cc"); !bytes.Equal(patch, want) {
+		t.Errorf("patch: got % x, want % x", patch, want)
+	}
+	if want := unhex(t, "55 48 89 e5 48 83 ec 20 e9 fb ef ff ff"); !bytes.Equal(tr.Code, want) || tr.Stolen != 8 {
+		t.Errorf("trampoline: got % x (%d stolen)", tr.Code, tr.Stolen)
+	}
+}
@@ -0,0 +1,167 @@
+package macho_test
+
+import (
+	"bytes"
+	"debug/macho"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	hdemacho "github.com/can1357/go-hde/macho"
+	"github.com/can1357/go-hde/tests/hdeutil"
+)
+
+// name16 pads a segment or section name
+func name16(s string) (b [16]byte) {
+	copy(b[:], s)
+	return b
+}
+
+// testBinary builds an executable for the CPU with _main, an exported _helper, a
+// local _local and two stubs for the imports _puts and _exit
+func testBinary(cpu macho.Cpu) []byte {
+	const base = 0x100000000
+	img := make([]byte, 0x1100)
+	le := binary.LittleEndian
+	hdeutil.Put(img, 0, le, macho.FileHeader{
+		Magic: macho.Magic64,
+		Cpu:   cpu,
+		Type:  macho.TypeExec,
+		Ncmd:  4,
+		Cmdsz: 360,
+	}, uint32(0), macho.Segment64{
+		Cmd:     macho.LoadCmdSegment64,
+		Len:     232,
+		Name:    name16("__TEXT"),
+		Addr:    base,
+		Memsz:   0x1000,
+		Filesz:  0x1000,
+		Maxprot: 5,
+		Prot:    5,
+		Nsect:   2,
+	}, macho.Section64{
+		Name:   name16("__text"),
+		Seg:    name16("__TEXT"),
+		Addr:   base + 0xf00,
+		Size:   0x13,
+		Offset: 0xf00,
+		Flags:  0x80000400,
+	}, macho.Section64{
+		Name:     name16("__stubs"),
+		Seg:      name16("__TEXT"),
+		Addr:     base + 0xf80,
+		Size:     12,
+		Offset:   0xf80,
+		Flags:    0x80000408,
+		Reserve2: 6,
+	}, macho.SymtabCmd{
+		Cmd:     macho.LoadCmdSymtab,
+		Len:     24,
+		Symoff:  0x1000,
+		Nsyms:   5,
+		Stroff:  0x1060,
+		Strsize: 34,
+	}, macho.DysymtabCmd{
+		Cmd:            macho.LoadCmdDysymtab,
+		Len:            80,
+		Nlocalsym:      1,
+		Iextdefsym:     1,
+		Nextdefsym:     2,
+		Iundefsym:      3,
+		Nundefsym:      2,
+		Indirectsymoff: 0x1050,
+		Nindirectsyms:  2,
+	}, uint32(0x80000028), uint32(24), uint64(0xf00), uint64(0))
+
+	// __text: _main calls _helper and the _puts stub
+	hdeutil.Put(img, 0xf00, le, []byte{0xe8, 0x0b, 0, 0, 0, 0xe8, 0x76, 0, 0, 0, 0xc3})
+	hdeutil.Put(img, 0xf10, le, []byte{0x31, 0xc0, 0xc3})
+	// __stubs: jmp [rip+x] through the lazy symbol pointers
+	hdeutil.Put(img, 0xf80, le, []byte{0xff, 0x25, 0x82, 0, 0, 0, 0xff, 0x25, 0x84, 0, 0, 0})
+
+	hdeutil.Put(img, 0x1000, le,
+		macho.Nlist64{Name: 15, Type: 0x0e, Sect: 1, Value: base + 0xf0a},
+		macho.Nlist64{Name: 1, Type: 0x0f, Sect: 1, Value: base + 0xf00},
+		macho.Nlist64{Name: 7, Type: 0x0f, Sect: 1, Value: base + 0xf10},
+		macho.Nlist64{Name: 22, Type: 0x01},
+		macho.Nlist64{Name: 28, Type: 0x01},
+		[]uint32{3, 4},
+	)
+	hdeutil.Put(img, 0x1060, le, []byte("\x00_main\x00_helper\x00_local\x00_puts\x00_exit\x00"))
+	return img
+}
+
+// testFat builds a universal binary with an arm64 and an x86-64 slice
+func testFat() []byte {
+	img := make([]byte, 0x4100)
+	hdeutil.Put(img, 0, binary.BigEndian, uint32(macho.MagicFat), uint32(2),
+		macho.FatArchHeader{Cpu: macho.CpuArm64, Offset: 0x1000, Size: 0x1100, Align: 12},
+		macho.FatArchHeader{Cpu: macho.CpuAmd64, Offset: 0x3000, Size: 0x1100, Align: 12},
+	)
+	copy(img[0x1000:], testBinary(macho.CpuArm64))
+	copy(img[0x3000:], testBinary(macho.CpuAmd64))
+	return img
+}
+
+func TestEntries(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data []byte
+	}{
+		{"thin", testBinary(macho.CpuAmd64)},
+		{"fat", testFat()},
+	} {
+		img, err := hdemacho.NewImage(bytes.NewReader(tt.data))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if img.Mode != hde.Mode64 {
+			t.Errorf("%s: got %d-bit", tt.name, img.Mode.Bits())
+		}
+		if s := img.Lookup("__TEXT", "__text"); s == nil || s.Addr != 0x100000f00 || len(s.Data) != 0x13 {
+			t.Errorf("%s: __text %+v", tt.name, s)
+		}
+
+		want := []hdemacho.Entry{
+			{Addr: 0x100000f00, Kind: hdemacho.EntryPoint},
+			{Addr: 0x100000f00, Kind: hdemacho.Export, Name: "_main"},
+			{Addr: 0x100000f10, Kind: hdemacho.Export, Name: "_helper"},
+		}
+		if got := img.Entries(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, want)
+		}
+		stubs := []hdemacho.Stub{{Addr: 0x100000f80, Name: "_puts"}, {Addr: 0x100000f86, Name: "_exit"}}
+		if got := img.Stubs(); !reflect.DeepEqual(got, stubs) {
+			t.Errorf("%s: got stubs %+v, want %+v", tt.name, got, stubs)
+		}
+
+		insn, err := img.Decode(0x100000f05)
+		if target, ok := insn.BranchTarget(0x100000f05); err != nil || !ok || target != 0x100000f80 {
+			t.Errorf("%s: call at 0x100000f05 goes to %#x, %v", tt.name, target, err)
+		}
+		g, err := img.Disassemble()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, addr := range []uint64{0x100000f00, 0x100000f10, 0x100000f80} {
+			if b := g.Blocks[addr]; b == nil || b.Err != nil {
+				t.Errorf("%s: block %#x: %+v", tt.name, addr, b)
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if _, err := hdemacho.NewImage(bytes.NewReader(testBinary(macho.CpuArm64))); !errors.Is(err, hdemacho.ErrMachine) {
+		t.Errorf("arm64: got %v", err)
+	}
+	img, err := hdemacho.NewImage(bytes.NewReader(testBinary(macho.CpuAmd64)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := img.Decode(0x100001000); !errors.Is(err, hdemacho.ErrUnmapped) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+package pe_test
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	hde "github.com/can1357/go-hde"
+	hdepe "github.com/can1357/go-hde/pe"
+	"github.com/can1357/go-hde/tests/hdeutil"
+)
+
+// testDLL builds a 64-bit DLL with an entry point, a named export, an export by
+// ordinal, a forwarded export, a TLS callback, and two functions in the exception
+// directory sharing unwind information that only the first one matches
+func testDLL() []byte {
+	const base = 0x180000000
+	img := make([]byte, 0x600)
+	le := binary.LittleEndian
+	hdeutil.Put(img, 0, le, [2]byte{'M', 'Z'})
+	hdeutil.Put(img, 0x3c, le, uint32(0x40))
+	hdeutil.Put(img, 0x40, le, [4]byte{'P', 'E'}, pe.FileHeader{
+		Machine:              pe.IMAGE_FILE_MACHINE_AMD64,
+		NumberOfSections:     2,
+		SizeOfOptionalHeader: 240,
+		Characteristics:      pe.IMAGE_FILE_EXECUTABLE_IMAGE | pe.IMAGE_FILE_DLL,
+	})
+	oh := pe.OptionalHeader64{
+		Magic:               0x20b,
+		AddressOfEntryPoint: 0x1000,
+		ImageBase:           base,
+		SectionAlignment:    0x1000,
+		FileAlignment:       0x200,
+		SizeOfImage:         0x3000,
+		SizeOfHeaders:       0x200,
+		NumberOfRvaAndSizes: 16,
+	}
+	oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXPORT] = pe.DataDirectory{VirtualAddress: 0x2000, Size: 0x100}
+	oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_TLS] = pe.DataDirectory{VirtualAddress: 0x2100, Size: 0x28}
+	oh.DataDirectory[pe.IMAGE_DIRECTORY_ENTRY_EXCEPTION] = pe.DataDirectory{VirtualAddress: 0x2180, Size: 24}
+	hdeutil.Put(img, 0x58, le, oh, pe.SectionHeader32{
+		Name:             [8]uint8{'.', 't', 'e', 'x', 't'},
+		VirtualSize:      0x100,
+		VirtualAddress:   0x1000,
+		SizeOfRawData:    0x200,
+		PointerToRawData: 0x200,
+		Characteristics:  pe.IMAGE_SCN_CNT_CODE | pe.IMAGE_SCN_MEM_EXECUTE | pe.IMAGE_SCN_MEM_READ,
+	}, pe.SectionHeader32{
+		Name:             [8]uint8{'.', 'r', 'd', 'a', 't', 'a'},
+		VirtualSize:      0x200,
+		VirtualAddress:   0x2000,
+		SizeOfRawData:    0x200,
+		PointerToRawData: 0x400,
+		Characteristics:  pe.IMAGE_SCN_CNT_INITIALIZED_DATA | pe.IMAGE_SCN_MEM_READ,
+	})
+
+	// .text: the entry point calls the first export
+	hdeutil.Put(img, 0x200, le, []byte{0xe8, 0x0b, 0, 0, 0, 0xc3})
+	hdeutil.Put(img, 0x210, le, []byte{0x31, 0xc0, 0xc3})
+	hdeutil.Put(img, 0x218, le, []byte{0xc3})
+	hdeutil.Put(img, 0x220, le, []byte{0xc3})
+	// push rbx; sub rsp, 0x20; add rsp, 0x20; pop rbx; ret
+	hdeutil.Put(img, 0x230, le, []byte{0x53, 0x48, 0x83, 0xec, 0x20, 0x48, 0x83, 0xc4, 0x20, 0x5b, 0xc3})
+	// push rbp; sub rsp, 0x20; add rsp, 0x20; pop rbp; ret
+	hdeutil.Put(img, 0x240, le, []byte{0x55, 0x48, 0x83, 0xec, 0x20, 0x48, 0x83, 0xc4, 0x20, 0x5d, 0xc3})
+
+	// .rdata: the export directory and its tables, then the TLS directory
+	hdeutil.Put(img, 0x400, le, [3]uint32{}, uint32(0x2080), uint32(1), uint32(4), uint32(1),
+		uint32(0x2040), uint32(0x2050), uint32(0x2058))
+	hdeutil.Put(img, 0x440, le, [4]uint32{0x1010, 0x1018, 0, 0x2090})
+	hdeutil.Put(img, 0x450, le, uint32(0x2060))
+	hdeutil.Put(img, 0x458, le, uint16(0))
+	hdeutil.Put(img, 0x460, le, []byte("Add\x00"))
+	hdeutil.Put(img, 0x480, le, []byte("test.dll\x00"))
+	hdeutil.Put(img, 0x490, le, []byte("other.Fn\x00"))
+	hdeutil.Put(img, 0x500, le, [3]uint64{}, uint64(base+0x2140))
+	hdeutil.Put(img, 0x540, le, [2]uint64{base + 0x1020, 0})
+	hdeutil.Put(img, 0x580, le, [6]uint32{0x1030, 0x103b, 0x21a0, 0x1040, 0x104b, 0x21a0})
+	// Version 1, a 5 byte prologue and two codes: alloc 0x20 at 5, push rbx at 1
+	hdeutil.Put(img, 0x5a0, le, []byte{0x01, 0x05, 0x02, 0x00, 0x05, 0x32, 0x01, 0x30})
+	return img
+}
+
+func TestEntries(t *testing.T) {
+	img, err := hdepe.NewImage(bytes.NewReader(testDLL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Mode != hde.Mode64 || img.ImageBase != 0x180000000 {
+		t.Errorf("got mode %d, base %#x", img.Mode.Bits(), img.ImageBase)
+	}
+	entries, err := img.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []hdepe.Entry{
+		{Addr: 0x180001000, Kind: hdepe.EntryPoint},
+		{Addr: 0x180001010, Kind: hdepe.Export, Name: "Add"},
+		{Addr: 0x180001018, Kind: hdepe.Export, Name: "#2"},
+		{Addr: 0x180001020, Kind: hdepe.TLSCallback},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got %+v, want %+v", entries, want)
+	}
+
+	g, err := img.Disassemble()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var starts []uint64
+	for _, b := range g.Sorted() {
+		starts = append(starts, b.Start)
+	}
+	if want := []uint64{0x180001000, 0x180001005, 0x180001010, 0x180001018, 0x180001020}; !reflect.DeepEqual(starts, want) {
+		t.Errorf("blocks: got %#x, want %#x", starts, want)
+	}
+
+	if insn, err := img.Decode(0x180001010); err != nil || insn.Mnemonic() != hde.XOR {
+		t.Errorf("Decode: got %v, %v", insn.Mnemonic(), err)
+	}
+	if insn, err := img.Decode(0x180002000); !errors.Is(err, hdepe.ErrUnmapped) || insn.FormatIntel(0) != "(bad)" {
+		t.Errorf("Decode in .rdata: got %q, %v", insn.FormatIntel(0), err)
+	}
+	for inst, err := range img.Instructions(0x180002000) {
+		// The errored instruction has no Mode, and so no form
+		_, rip := inst.Insn.MemTarget(inst.Addr)
+		if !errors.Is(err, hdepe.ErrUnmapped) || inst.Addr != 0x180002000 || inst.Insn.FormatIntel(0) != "(bad)" ||
+			inst.Insn.FormatATT(0) != "(bad)" || inst.Insn.Mnemonic() != hde.INVALID ||
+			inst.Insn.Flow() != hde.Fallthrough || len(inst.Insn.Operands()) != 0 || rip {
+			t.Errorf("Instructions in .rdata: got %q at %#x, %v", inst.Insn.FormatIntel(0), inst.Addr, err)
+		}
+	}
+}
+
+func TestFunctions(t *testing.T) {
+	img, err := hdepe.NewImage(bytes.NewReader(testDLL()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fns, err := img.Functions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &hdepe.UnwindInfo{
+		Version:    1,
+		PrologSize: 5,
+		Codes: []hdepe.UnwindCode{
+			{Offset: 5, Op: hdepe.AllocSmall, Value: 0x20},
+			{Offset: 1, Op: hdepe.PushNonvol, Reg: hde.RBX},
+		},
+	}
+	want := []hdepe.RuntimeFunction{
+		{Begin: 0x180001030, End: 0x18000103b, Unwind: info},
+		{Begin: 0x180001040, End: 0x18000104b, Unwind: info},
+	}
+	if !reflect.DeepEqual(fns, want) {
+		t.Fatalf("got %+v, want %+v", fns, want)
+	}
+	if insts, err := img.DecodeFunction(fns[0]); len(insts) != 5 || err != nil {
+		t.Errorf("DecodeFunction: got %d instructions, %v", len(insts), err)
+	}
+	if err := img.CheckPrologue(fns[0]); err != nil {
+		t.Errorf("CheckPrologue: %v", err)
+	}
+	if err := img.CheckPrologue(fns[1]); !errors.Is(err, hdepe.ErrPrologue) {
+		t.Errorf("CheckPrologue of push rbp: got %v", err)
+	}
+
+	// An exception directory running past its section is not allocated for
+	dll := testDLL()
+	hdeutil.Put(dll, 0x58+112+pe.IMAGE_DIRECTORY_ENTRY_EXCEPTION*8+4, binary.LittleEndian, uint32(0xfffffff0))
+	if img, err = hdepe.NewImage(bytes.NewReader(dll)); err != nil {
+		t.Fatal(err)
+	}
+	if fns, err := img.Functions(); fns != nil || !errors.Is(err, hdepe.ErrUnmapped) {
+		t.Errorf("oversized directory: got %d functions, %v", len(fns), err)
+	}
+
+	// Every function of a real image has a prologue matching its unwind codes
+	img, err = hdepe.Open("../hde64/winrar-x64-710.exe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer img.Close()
+	if fns, err = img.Functions(); err != nil || len(fns) != 1291 {
+		t.Fatalf("got %d functions, %v", len(fns), err)
+	}
+	for _, fn := range fns {
+		if _, err := img.DecodeFunction(fn); err != nil {
+			t.Error(err)
+		}
+		if err := img.CheckPrologue(fn); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestWinRAR(t *testing.T) {
+	tests := []struct {
+		file  string
+		bits  int
+		entry uint64
+	}{
+		{"../hde64/winrar-x64-710.exe", 64, 0x140024840},
+		{"../hde32/winrar-x86-602.exe", 32, 0x41e7b0},
+	}
+	for _, tt := range tests {
+		img, err := hdepe.Open(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer img.Close()
+		if ep, ok := img.EntryPoint(); img.Mode.Bits() != tt.bits || !ok || ep != tt.entry {
+			t.Errorf("%s: got %d-bit, entry point %#x", tt.file, img.Mode.Bits(), ep)
+		}
+		n := 0
+		for inst, err := range img.Instructions(tt.entry) {
+			if err != nil || inst.Addr < tt.entry {
+				t.Errorf("%s: %#x: %v", tt.file, inst.Addr, err)
+			}
+			if n++; n == 100 {
+				break
+			}
+		}
+		g, err := img.Disassemble()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b := g.Blocks[tt.entry]; b == nil || b.Err != nil {
+			t.Errorf("%s: entry block %+v", tt.file, b)
+		}
+	}
+}
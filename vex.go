@@ -0,0 +1,105 @@
+package hde
+
+import "fmt"
+
+// VEX escape bytes
+const (
+	VEX2 byte = 0xc5 // Two-byte VEX prefix
+	VEX3 byte = 0xc4 // Three-byte VEX prefix
+)
+
+// VEX represents a VEX prefix in its three-byte form.
+// Byte 0 holds the escape byte as encoded (C4 or C5), byte 1 holds R.X.B.mmmmm and
+// byte 2 holds W.vvvv.L.pp, with the inverted fields stored as encoded.
+// Two-byte prefixes are expanded to the equivalent three-byte payload.
+type VEX uint32
+
+// newVEX2 expands a two-byte VEX payload into its three-byte form
+func newVEX2(b1 byte) VEX {
+	return VEX(VEX2) | VEX(b1&0x80|0x61)<<8 | VEX(b1&0x7f)<<16
+}
+
+// newVEX3 builds a VEX from a three-byte VEX payload
+func newVEX3(b1, b2 byte) VEX {
+	return VEX(VEX3) | VEX(b1)<<8 | VEX(b2)<<16
+}
+
+// Escape returns the escape byte (C4 or C5) the prefix was encoded with
+func (v VEX) Escape() byte {
+	return byte(v)
+}
+
+// R returns the ModR/M reg field extension from the VEX prefix
+func (v VEX) R() uint8 {
+	return ^uint8(v>>15) & 1
+}
+
+// X returns the SIB index field extension from the VEX prefix
+func (v VEX) X() uint8 {
+	return ^uint8(v>>14) & 1
+}
+
+// B returns the ModR/M r/m or SIB base field extension from the VEX prefix
+func (v VEX) B() uint8 {
+	return ^uint8(v>>13) & 1
+}
+
+// Map returns the opcode map selector (mmmmm) from the VEX prefix.
+// 1 selects the 0F map, 2 the 0F38 map and 3 the 0F3A map.
+func (v VEX) Map() uint8 {
+	return uint8(v>>8) & 0x1f
+}
+
+// W returns the operand size / opcode extension bit from the VEX prefix
+func (v VEX) W() uint8 {
+	return uint8(v>>23) & 1
+}
+
+// V returns the additional register specifier (vvvv) from the VEX prefix
+func (v VEX) V() uint8 {
+	return ^uint8(v>>19) & 0xf
+}
+
+// L returns the vector length bit from the VEX prefix (0 for 128-bit, 1 for 256-bit)
+func (v VEX) L() uint8 {
+	return uint8(v>>18) & 1
+}
+
+// PP returns the implied mandatory prefix selector (pp) from the VEX prefix
+func (v VEX) PP() uint8 {
+	return uint8(v>>16) & 3
+}
+
+// Prefix returns the legacy prefix byte implied by the pp field, if any
+func (v VEX) Prefix() byte {
+	return ppToPrefix[v.PP()]
+}
+
+func (v VEX) String() string {
+	return fmt.Sprintf("VEX[R=%d,X=%d,B=%d,map=%d,W=%d,v=%d,L=%d,pp=%d]",
+		v.R(), v.X(), v.B(), v.Map(), v.W(), v.V(), v.L(), v.PP())
+}
+
+// ppToPrefix maps the pp field to the implied legacy prefix byte
+var ppToPrefix = [4]byte{0, PRE_OP_SIZE, PRE_REPX, PRE_REPNZ}
+
+// ppToPrefixID maps the pp field to the implied legacy prefix ID
+var ppToPrefixID = [4]PrefixID{PreNone, PreOpSize, PreRep, PreRepNZ}
+
+// vexOpcode0F returns true if the 0F map opcode has a VEX encoding
+func vexOpcode0F(op byte) bool {
+	switch {
+	case op >= 0x10 && op <= 0x17,
+		op >= 0x28 && op <= 0x2f,
+		op >= 0x50 && op <= 0x7f,
+		op >= 0xd0 && op <= 0xfe:
+		return true
+	}
+	switch op {
+	case 0x41, 0x42, 0x44, 0x45, 0x46, 0x47, 0x4a, 0x4b, // Mask register logic
+		0x90, 0x91, 0x92, 0x93, 0x98, 0x99, // Mask register moves and tests
+		0xae, 0xc2, 0xc4, 0xc5, 0xc6:
+		return true
+	}
+	return false
+}